@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// AcronymConfig tunes the acronym-expansion rule. With Scope "glossary"
+// only acronyms defined in the project glossary are checked; the default
+// "all" checks every acronym but the ignored ones.
+type AcronymConfig struct {
+	Scope  string   `yaml:"scope" json:"scope"`
+	Ignore []string `yaml:"ignore" json:"ignore"`
+}
+
+// commonAcronyms are familiar enough to need no expansion
+var commonAcronyms = []string{"OK", "ID", "PDF", "HTML", "CSS", "URL", "FAQ", "USB", "TV", "AM", "PM", "II", "III", "IV"}
+
+// markupWords are written in capitals by AsciiDoc convention rather than
+// being acronyms: admonition labels and TODO markers
+var markupWords = []string{"NOTE", "TIP", "WARNING", "IMPORTANT", "CAUTION", "TODO"}
+
+var (
+	// Two or more capitals and digits, starting with a capital, with an
+	// optional plural s
+	acronymExpr       = regexp.MustCompile(`\b([A-Z][A-Z0-9]*[A-Z][A-Z0-9]*)s?\b`)
+	glossaryEntryExpr = regexp.MustCompile(`^(\S[^:]*?)::(?:\s+(.*))?$`)
+
+	// Macro targets, URLs, cross references, anchors and attribute
+	// references, whose capitals are names rather than prose
+	acronymMaskExpr = regexp.MustCompile(`\b[a-z]+:{1,2}[^\s\[]*|<<[^,>]*|\[\[[^\]]*\]\]|\{[\w-]+\}`)
+)
+
+// acronymRules is what the acronym-expansion rule checks against
+type acronymRules struct {
+	// expansions are the glossary's definitions of acronyms
+	expansions   map[string]string
+	glossaryOnly bool
+	ignore       map[string]bool
+}
+
+// loadAcronymRules reads the project's acronym settings and glossary
+func loadAcronymRules(root string, cfg *ProjectConfig) *acronymRules {
+	rules := &acronymRules{expansions: map[string]string{}, ignore: map[string]bool{}}
+	for _, a := range commonAcronyms {
+		rules.ignore[a] = true
+	}
+	for _, w := range markupWords {
+		rules.ignore[w] = true
+	}
+	if cfg == nil {
+		return rules
+	}
+	rules.glossaryOnly = cfg.Lint.Acronyms.Scope == "glossary"
+	for _, a := range cfg.Lint.Acronyms.Ignore {
+		rules.ignore[strings.TrimSpace(a)] = true
+	}
+	if cfg.Glossary != "" {
+		if content, err := os.ReadFile(longPath(filepath.Join(root, filepath.FromSlash(cfg.Glossary)))); err == nil {
+			for term, definition := range parseGlossary(string(content)) {
+				if acronymExpr.FindString(term) == term {
+					rules.expansions[term] = definition
+				}
+			}
+		}
+	}
+	return rules
+}
+
+// parseGlossary reads the "term:: definition" entries of a glossary. A
+// definition on the following line is picked up too.
+func parseGlossary(content string) map[string]string {
+	entries := map[string]string{}
+	pending := ""
+	forEachProseLine(content, func(n int, line string) {
+		trimmed := strings.TrimSpace(line)
+		if pending != "" {
+			if trimmed != "" {
+				entries[pending] = trimmed
+			}
+			pending = ""
+			return
+		}
+		m := glossaryEntryExpr.FindStringSubmatch(trimmed)
+		if m == nil {
+			return
+		}
+		term := strings.TrimSpace(m[1])
+		if m[2] == "" {
+			pending = term
+			return
+		}
+		entries[term] = strings.TrimSpace(m[2])
+	})
+	return entries
+}
+
+// checkAcronymExpansion flags acronyms whose first use in a document is
+// not expanded, as in "Application Programming Interface (API)"
+func checkAcronymExpansion(doc *lintDocument) []Diagnostic {
+	rules := doc.Acronyms
+	if rules == nil {
+		return nil
+	}
+	var found []Diagnostic
+	seen := map[string]bool{}
+	defined := parseGlossary(doc.Content)
+	forEachProseLine(doc.Content, func(n int, line string) {
+		trimmed := strings.TrimSpace(line)
+		// Headings and attribute lines don't count as a first use
+		if strings.HasPrefix(trimmed, "=") || strings.HasPrefix(trimmed, ":") || strings.HasPrefix(trimmed, "[") {
+			return
+		}
+		masked := maskMacroTargets(line)
+		for _, m := range acronymExpr.FindAllStringSubmatchIndex(masked, -1) {
+			acronym := line[m[2]:m[3]]
+			if seen[acronym] || rules.ignore[acronym] || strings.Count(line[:m[0]], "`")%2 == 1 {
+				continue
+			}
+			expansion, known := rules.expansions[acronym]
+			if rules.glossaryOnly && !known {
+				continue
+			}
+			seen[acronym] = true
+			if _, ok := defined[acronym]; ok || acronymExpanded(line, m[0], m[1]) {
+				continue
+			}
+			msg := fmt.Sprintf("Acronym %s is not expanded on first use", acronym)
+			if known {
+				msg += fmt.Sprintf("; the glossary defines it as %q", expansion)
+			}
+			found = append(found, Diagnostic{Line: n, Column: m[0] + 1, Message: msg})
+		}
+	})
+	return found
+}
+
+// maskMacroTargets blanks out the parts of a line that name things rather
+// than read as prose, keeping every other byte where it was
+func maskMacroTargets(line string) string {
+	return acronymMaskExpr.ReplaceAllStringFunc(line, func(m string) string {
+		return strings.Repeat(" ", len(m))
+	})
+}
+
+// acronymExpanded reports whether the acronym at line[start:end] is
+// written as "Expansion (ACR)" or "ACR (Expansion)"
+func acronymExpanded(line string, start, end int) bool {
+	if start > 0 && line[start-1] == '(' && end < len(line) && line[end] == ')' {
+		return strings.TrimSpace(line[:start-1]) != ""
+	}
+	return strings.HasPrefix(strings.TrimLeft(line[end:], " "), "(")
+}
+
+// fixAcronymExpansion returns a fixer that writes out the glossary's
+// expansion before the acronym
+func fixAcronymExpansion(rules *acronymRules) quickFixer {
+	return func(content string, lineStart int, line string, d Diagnostic) []proposedFix {
+		col := d.Column - 1
+		if col < 0 || col > len(line) {
+			return nil
+		}
+		m := acronymExpr.FindStringSubmatchIndex(line[col:])
+		if m == nil || m[0] != 0 {
+			return nil
+		}
+		acronym := line[col+m[2] : col+m[3]]
+		expansion, ok := rules.expansions[acronym]
+		if !ok {
+			return nil
+		}
+		if m[1] > m[3] {
+			expansion += "s"
+		}
+		start, end := lineStart+col+m[0], lineStart+col+m[1]
+		return []proposedFix{{
+			title: fmt.Sprintf("Expand as \"%s (%s)\"", expansion, line[col+m[0]:col+m[1]]),
+			edits: []byteEdit{{start: start, end: start, text: expansion + " ("}, {start: end, end: end, text: ")"}},
+		}}
+	}
+}
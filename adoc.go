@@ -0,0 +1,285 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// adocSection is a section heading found in an AsciiDoc document, along
+// with the text up to the next heading
+type adocSection struct {
+	Level int
+	Title string
+	ID    string
+	Line  int
+	Body  string
+}
+
+var (
+	adocHeadingExpr  = regexp.MustCompile(`^(={1,6})\s+(.+?)\s*$`)
+	adocAnchorExpr   = regexp.MustCompile(`^\[\[([^\],]+)(?:,[^\]]*)?\]\]\s*$`)
+	adocBlockIDExpr  = regexp.MustCompile(`^\[#([^\].,%]+)`)
+	adocDelimiterSet = []string{"----", "....", "====", "****", "____", "////", "++++", "|==="}
+
+	// Verbatim blocks, whose content is never scanned for references
+	adocVerbatimSet = []string{"----", "....", "////", "++++"}
+
+	adocIncludeExpr   = regexp.MustCompile(`^include::([^\[]+)\[`)
+	adocImageExpr     = regexp.MustCompile(`image::?([^\[\s:][^\[\s]*)\[`)
+	adocXrefExpr      = regexp.MustCompile(`xref:([^\[\s]+)\[`)
+	adocXrefAltExpr   = regexp.MustCompile(`<<([^>#,\s]+\.adoc)(?:#[^>,]*)?(?:,[^>]*)?>>`)
+	adocImagesDirExpr = regexp.MustCompile(`^:imagesdir:\s*(.*?)\s*$`)
+	adocAttributeExpr = regexp.MustCompile(`^:([\w-]+):\s*(.*?)\s*$`)
+
+	adocDirectiveExpr = regexp.MustCompile(`^(include|ifdef|ifndef|ifeval|endif)::`)
+)
+
+// adocInline returns text from outside the project as inline AsciiDoc that
+// shows it as is: markup, macros and attribute references have no effect
+func adocInline(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if s == "" {
+		return ""
+	}
+	return "pass:c[" + strings.ReplaceAll(s, "]", `\]`) + "]"
+}
+
+// adocLiteral returns text from outside the project as a literal block,
+// with preprocessor directives escaped so nothing gets included
+func adocLiteral(s string) string {
+	lines := strings.Split(s, "\n")
+	delimiter := "...."
+	for slices.Contains(lines, delimiter) {
+		delimiter += "."
+	}
+	for i, line := range lines {
+		if adocDirectiveExpr.MatchString(line) {
+			lines[i] = `\` + line
+		}
+	}
+	return delimiter + "\n" + strings.Join(lines, "\n") + "\n" + delimiter + "\n"
+}
+
+// Kinds of adocReference
+const (
+	refInclude = "include"
+	refImage   = "image"
+	refXref    = "xref"
+)
+
+// adocReference is a link from a document to another file
+type adocReference struct {
+	Kind   string
+	Target string
+	Line   int
+	Column int
+}
+
+// parseReferences finds the includes, images and cross-document xrefs of a
+// document. Attribute references in targets are expanded with attrs (such
+// as projectAttributes) and the document's header attributes. URLs,
+// targets using unknown attributes and xrefs to anchors in the same
+// document are left out since they can't be resolved to a file.
+func parseReferences(content string, attrs map[string]string) []adocReference {
+	if strings.Contains(content, "{") {
+		merged := headerAttributes(content)
+		for name, value := range attrs {
+			merged[name] = value
+		}
+		attrs = merged
+	}
+	var refs []adocReference
+	add := func(kind, target string, line, col int) {
+		target = expandAttributes(target, attrs)
+		if strings.Contains(target, "{") || strings.Contains(target, "://") {
+			return
+		}
+		refs = append(refs, adocReference{Kind: kind, Target: target, Line: line, Column: col + 1})
+	}
+
+	delimiter := ""
+	for i, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if delimiter != "" {
+			if trimmed == delimiter {
+				delimiter = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") {
+			delimiter = "```"
+			continue
+		}
+		if slices.Contains(adocVerbatimSet, trimmed) {
+			delimiter = trimmed
+			continue
+		}
+
+		if m := adocIncludeExpr.FindStringSubmatchIndex(line); m != nil {
+			add(refInclude, line[m[2]:m[3]], i+1, m[2])
+			continue
+		}
+		for _, m := range adocImageExpr.FindAllStringSubmatchIndex(line, -1) {
+			add(refImage, line[m[2]:m[3]], i+1, m[2])
+		}
+		for _, m := range adocXrefExpr.FindAllStringSubmatchIndex(line, -1) {
+			target := line[m[2]:m[3]]
+			if file, _, _ := strings.Cut(target, "#"); strings.HasSuffix(file, ".adoc") {
+				add(refXref, file, i+1, m[2])
+			}
+		}
+		for _, m := range adocXrefAltExpr.FindAllStringSubmatchIndex(line, -1) {
+			add(refXref, line[m[2]:m[3]], i+1, m[2])
+		}
+	}
+	return refs
+}
+
+// imagesDir returns the :imagesdir: attribute set in a document, if any
+func imagesDir(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if m := adocImagesDirExpr.FindStringSubmatch(strings.TrimRight(line, "\r")); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// headerAttributes returns the attribute entries (":name: value") of a
+// document's header, which ends at the first blank line after the title
+func headerAttributes(content string) map[string]string {
+	attrs := make(map[string]string)
+	started := false
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			if started {
+				break
+			}
+			continue
+		}
+		started = true
+		if m := adocAttributeExpr.FindStringSubmatch(trimmed); m != nil {
+			attrs[m[1]] = m[2]
+		}
+	}
+	return attrs
+}
+
+// documentMetadata returns a document's front matter values when it has
+// front matter, and otherwise its header attributes plus the title as
+// "doctitle"
+func documentMetadata(content []byte) map[string]string {
+	if front, _, ok := splitFrontMatter(content); ok {
+		attrs := make(map[string]string)
+		var meta map[string]interface{}
+		if yaml.Unmarshal(front, &meta) == nil {
+			for k, v := range meta {
+				switch v := v.(type) {
+				case string:
+					attrs[k] = v
+				case time.Time:
+					attrs[k] = v.Format("2006-01-02")
+				case int, float64, bool:
+					attrs[k] = fmt.Sprint(v)
+				}
+			}
+		}
+		return attrs
+	}
+	attrs := headerAttributes(string(content))
+	for _, line := range strings.Split(string(content), "\n") {
+		if title, ok := strings.CutPrefix(strings.TrimRight(line, "\r"), "= "); ok {
+			attrs["doctitle"] = strings.TrimSpace(title)
+			break
+		}
+		if strings.TrimSpace(line) != "" && !strings.HasPrefix(line, "//") && !strings.HasPrefix(line, ":") {
+			break
+		}
+	}
+	return attrs
+}
+
+// parseSections splits an AsciiDoc document into its sections. IDs come
+// from an explicit anchor when one precedes the heading, otherwise they
+// are generated with slug.
+func parseSections(content string, slug SlugOptions) []adocSection {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	var sections []adocSection
+	var body strings.Builder
+	pendingID := ""
+	delimiter := ""
+
+	flush := func() {
+		if len(sections) > 0 {
+			sections[len(sections)-1].Body = strings.TrimSpace(body.String())
+		}
+		body.Reset()
+	}
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		// Headings inside listing/literal/etc. blocks are just text
+		if delimiter != "" {
+			if trimmed == delimiter {
+				delimiter = ""
+			}
+			body.WriteString(line + "\n")
+			continue
+		}
+		if isBlockDelimiter(trimmed) {
+			delimiter = trimmed
+			if strings.HasPrefix(trimmed, "```") {
+				delimiter = "```"
+			}
+			body.WriteString(line + "\n")
+			continue
+		}
+
+		if m := adocAnchorExpr.FindStringSubmatch(trimmed); m != nil {
+			pendingID = m[1]
+			continue
+		}
+		if m := adocBlockIDExpr.FindStringSubmatch(trimmed); m != nil {
+			pendingID = m[1]
+			continue
+		}
+
+		if m := adocHeadingExpr.FindStringSubmatch(line); m != nil {
+			flush()
+			id := pendingID
+			if id == "" {
+				id = Slugify(m[2], slug)
+			}
+			sections = append(sections, adocSection{
+				Level: len(m[1]) - 1,
+				Title: m[2],
+				ID:    id,
+				Line:  i + 1,
+			})
+			pendingID = ""
+			continue
+		}
+
+		pendingID = ""
+		body.WriteString(line + "\n")
+	}
+	flush()
+	return sections
+}
+
+func isBlockDelimiter(line string) bool {
+	for _, d := range adocDelimiterSet {
+		if line == d {
+			return true
+		}
+	}
+	return strings.HasPrefix(line, "```")
+}
@@ -0,0 +1,403 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/option"
+)
+
+// aiRequest is a single prompt sent to the configured AI provider
+type aiRequest struct {
+	Model       string
+	Temperature *float32
+	Prompt      string
+	// Safety maps harm categories to block thresholds (see aiSafetyCategories)
+	Safety map[string]string
+	// Images are sent along with the prompt to vision-capable models
+	Images []aiImage
+	// Action and Document describe the request in the AI history
+	Action   string
+	Document string
+	// JSON is set when the prompt asks for a JSON answer
+	JSON bool
+}
+
+// aiImage is an inline image attached to an aiRequest
+type aiImage struct {
+	MimeType string
+	Data     []byte
+}
+
+// Harm categories and block thresholds that can be set through the
+// "ai_safety_settings" preference
+var (
+	aiSafetyCategories = map[string]genai.HarmCategory{
+		"harassment":        genai.HarmCategoryHarassment,
+		"hate_speech":       genai.HarmCategoryHateSpeech,
+		"sexually_explicit": genai.HarmCategorySexuallyExplicit,
+		"dangerous":         genai.HarmCategoryDangerousContent,
+	}
+	aiSafetyThresholds = map[string]genai.HarmBlockThreshold{
+		"default":                genai.HarmBlockUnspecified,
+		"block_low_and_above":    genai.HarmBlockLowAndAbove,
+		"block_medium_and_above": genai.HarmBlockMediumAndAbove,
+		"block_only_high":        genai.HarmBlockOnlyHigh,
+		"block_none":             genai.HarmBlockNone,
+	}
+)
+
+// AISafetyOptions lists the values the preferences UI can offer
+type AISafetyOptions struct {
+	Categories []string `json:"categories"`
+	Thresholds []string `json:"thresholds"`
+}
+
+// GetAISafetyOptions returns the configurable harm categories and thresholds
+func (a *App) GetAISafetyOptions() AISafetyOptions {
+	opts := AISafetyOptions{}
+	for k := range aiSafetyCategories {
+		opts.Categories = append(opts.Categories, k)
+	}
+	for k := range aiSafetyThresholds {
+		opts.Thresholds = append(opts.Thresholds, k)
+	}
+	sort.Strings(opts.Categories)
+	sort.Strings(opts.Thresholds)
+	return opts
+}
+
+// aiSafetySettings reads the "ai_safety_settings" preference
+func (a *App) aiSafetySettings() map[string]string {
+	raw, _ := a.GetPreference("ai_safety_settings")
+	m, _ := raw.(map[string]interface{})
+	settings := make(map[string]string, len(m))
+	for k, v := range m {
+		if s, ok := v.(string); ok {
+			settings[k] = s
+		}
+	}
+	return settings
+}
+
+// defaultAIModel is used when the "ai_model" preference is not set
+const defaultAIModel = "gemini-2.0-flash"
+
+// aiModel returns the model configured through the "ai_model" preference
+func (a *App) aiModel() string {
+	if v, _ := a.GetPreference("ai_model"); v != nil {
+		if model, ok := v.(string); ok && model != "" {
+			return model
+		}
+	}
+	return defaultAIModel
+}
+
+// aiProvider is implemented by every AI backend the app can talk to
+type aiProvider interface {
+	Generate(ctx context.Context, req aiRequest) (string, error)
+	// Embed returns one embedding vector per text
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+	// EmbeddingModel names the provider and model Embed uses
+	EmbeddingModel() string
+}
+
+// newAIProvider returns the provider selected by the "ai_provider" preference
+func (a *App) newAIProvider() (aiProvider, error) {
+	if a.safeMode {
+		return nil, fmt.Errorf("AI is %w", errSafeMode)
+	}
+	providerRaw, _ := a.GetPreference("ai_provider")
+	provider, _ := providerRaw.(string)
+
+	switch provider {
+	case "", "gemini":
+		return &geminiProvider{}, nil
+	case "mock":
+		p := &mockProvider{}
+		if v, ok := a.prefFloat("ai_mock_latency_ms"); ok {
+			p.Latency = time.Duration(v) * time.Millisecond
+		}
+		if v, _ := a.GetPreference("ai_mock_response"); v != nil {
+			p.Template, _ = v.(string)
+		}
+		return p, nil
+	default:
+		return nil, fmt.Errorf("unknown AI provider %q", provider)
+	}
+}
+
+// generate sends req to the configured provider
+func (a *App) generate(req aiRequest) (string, error) {
+	provider, err := a.newAIProvider()
+	if err != nil {
+		return "", err
+	}
+	if req.Safety == nil {
+		req.Safety = a.aiSafetySettings()
+	}
+	text, err := provider.Generate(a.ctx, req)
+	a.recordAIHistory(req, text, err)
+	return text, err
+}
+
+// generateJSON asks the provider for a JSON answer and decodes it into v
+func (a *App) generateJSON(req aiRequest, v interface{}) error {
+	req.JSON = true
+	text, err := a.generate(req)
+	if err != nil {
+		return err
+	}
+	return parseAIJSON(text, v)
+}
+
+// parseAIJSON decodes a model reply that should be JSON but may be wrapped
+// in a markdown code fence or surrounded by chatter
+func parseAIJSON(text string, v interface{}) error {
+	text = strings.TrimSpace(text)
+	if i := strings.Index(text, "```"); i >= 0 {
+		text = text[i+3:]
+		text = strings.TrimPrefix(text, "json")
+		if j := strings.Index(text, "```"); j >= 0 {
+			text = text[:j]
+		}
+	}
+	start := strings.IndexAny(text, "{[")
+	end := strings.LastIndexAny(text, "}]")
+	if start < 0 || end < start {
+		return fmt.Errorf("AI response was not JSON")
+	}
+	return json.Unmarshal([]byte(text[start:end+1]), v)
+}
+
+func (a *App) prefFloat(key string) (float64, bool) {
+	v, _ := a.GetPreference(key)
+	f, ok := v.(float64)
+	return f, ok
+}
+
+func (a *App) prefString(key string) string {
+	v, _ := a.GetPreference(key)
+	s, _ := v.(string)
+	return s
+}
+
+// Gemini
+
+type geminiProvider struct{}
+
+// geminiEmbeddingModel is used for every embedding request
+const geminiEmbeddingModel = "text-embedding-004"
+
+// geminiBatchLimit is the most texts the API embeds in one request
+const geminiBatchLimit = 100
+
+func newGeminiClient(ctx context.Context) (*genai.Client, error) {
+	apiKey := os.Getenv("GEMINI_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("GEMINI_API_KEY not set")
+	}
+	return genai.NewClient(ctx, option.WithAPIKey(apiKey))
+}
+
+func (p *geminiProvider) Generate(ctx context.Context, req aiRequest) (string, error) {
+	client, err := newGeminiClient(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+
+	model := client.GenerativeModel(req.Model)
+	if req.Temperature != nil {
+		model.SetTemperature(*req.Temperature)
+	}
+	for name, threshold := range req.Safety {
+		category, ok := aiSafetyCategories[name]
+		if !ok {
+			continue
+		}
+		t, ok := aiSafetyThresholds[threshold]
+		if !ok || t == genai.HarmBlockUnspecified {
+			continue
+		}
+		model.SafetySettings = append(model.SafetySettings, &genai.SafetySetting{Category: category, Threshold: t})
+	}
+
+	parts := []genai.Part{genai.Text(req.Prompt)}
+	for _, img := range req.Images {
+		parts = append(parts, genai.Blob{MIMEType: img.MimeType, Data: img.Data})
+	}
+
+	resp, err := model.GenerateContent(ctx, parts...)
+	var blocked *genai.BlockedError
+	if errors.As(err, &blocked) {
+		return "", explainBlocked(blocked)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil || len(resp.Candidates[0].Content.Parts) == 0 {
+		if len(resp.Candidates) > 0 {
+			return "", fmt.Errorf("no content generated (finish reason: %s)", resp.Candidates[0].FinishReason)
+		}
+		return "", fmt.Errorf("no content generated")
+	}
+
+	// Extract text from parts
+	var result string
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if txt, ok := part.(genai.Text); ok {
+			result += string(txt)
+		}
+	}
+	return result, nil
+}
+
+func (p *geminiProvider) EmbeddingModel() string {
+	return "gemini/" + geminiEmbeddingModel
+}
+
+func (p *geminiProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	client, err := newGeminiClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	em := client.EmbeddingModel(geminiEmbeddingModel)
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += geminiBatchLimit {
+		end := min(start+geminiBatchLimit, len(texts))
+		batch := em.NewBatch()
+		for _, t := range texts[start:end] {
+			batch.AddContent(genai.Text(t))
+		}
+		resp, err := em.BatchEmbedContents(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Embeddings) != end-start {
+			return nil, fmt.Errorf("expected %d embeddings, got %d", end-start, len(resp.Embeddings))
+		}
+		for _, e := range resp.Embeddings {
+			vectors = append(vectors, e.Values)
+		}
+	}
+	return vectors, nil
+}
+
+// explainBlocked turns a Gemini safety block into a message that says which
+// category tripped and where to change it
+func explainBlocked(err *genai.BlockedError) error {
+	var ratings []*genai.SafetyRating
+	what := "response"
+	if err.PromptFeedback != nil {
+		what = "prompt"
+		ratings = err.PromptFeedback.SafetyRatings
+	} else if err.Candidate != nil {
+		ratings = err.Candidate.SafetyRatings
+	}
+
+	var reasons []string
+	for _, r := range ratings {
+		if r.Blocked || r.Probability >= genai.HarmProbabilityMedium {
+			reasons = append(reasons, fmt.Sprintf("%s (%s)", r.Category, r.Probability))
+		}
+	}
+	if len(reasons) == 0 {
+		return fmt.Errorf("the %s was blocked by the provider: %v", what, err)
+	}
+	return fmt.Errorf("the %s was blocked by the safety filter: %s. Adjust the AI safety settings in Preferences to allow it",
+		what, strings.Join(reasons, ", "))
+}
+
+// Mock
+
+// defaultMockResponse is returned by the mock provider when no template is set
+const defaultMockResponse = `== Mock Response
+
+This content was produced by the mock AI provider.
+
+Prompt length: {{len .Prompt}} characters.
+`
+
+// mockJSONResponse answers a JSON request with the example in its prompt
+// ("Respond ONLY with JSON of the form ..."), or an empty object
+func mockJSONResponse(prompt string) string {
+	_, example, ok := strings.Cut(prompt, "of the form")
+	if start := strings.IndexAny(example, "{["); ok && start >= 0 {
+		var value json.RawMessage
+		if err := json.NewDecoder(strings.NewReader(example[start:])).Decode(&value); err == nil {
+			return string(value)
+		}
+	}
+	return "{}"
+}
+
+// mockProvider answers without a network connection, for demos and tests.
+// Template is a text/template rendered with the request.
+type mockProvider struct {
+	Latency  time.Duration
+	Template string
+}
+
+// mockEmbeddingDims is the size of the mock provider's vectors
+const mockEmbeddingDims = 64
+
+func (p *mockProvider) EmbeddingModel() string {
+	return fmt.Sprintf("mock/%d", mockEmbeddingDims)
+}
+
+// Embed hashes words into a fixed-size bag-of-words vector, which is enough
+// for similar texts to land near each other
+func (p *mockProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, t := range texts {
+		v := make([]float32, mockEmbeddingDims)
+		for _, word := range strings.Fields(strings.ToLower(t)) {
+			h := fnv.New32a()
+			h.Write([]byte(word))
+			v[h.Sum32()%mockEmbeddingDims]++
+		}
+		vectors[i] = v
+	}
+	return vectors, nil
+}
+
+func (p *mockProvider) Generate(ctx context.Context, req aiRequest) (string, error) {
+	if p.Latency > 0 {
+		select {
+		case <-time.After(p.Latency):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
+	text := p.Template
+	if text == "" && req.JSON {
+		return mockJSONResponse(req.Prompt), nil
+	}
+	if text == "" {
+		text = defaultMockResponse
+	}
+	tmpl, err := template.New("mock").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, req); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
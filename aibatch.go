@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/google/uuid"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Review item states
+const (
+	ReviewPending  = "pending"
+	ReviewAccepted = "accepted"
+	ReviewRejected = "rejected"
+	ReviewFailed   = "failed"
+)
+
+// Events emitted while a batch runs
+const (
+	EventAIBatchProgress = "ai:batch:progress"
+	EventAIBatchDone     = "ai:batch:done"
+)
+
+// AIBatchProgress is the payload of EventAIBatchProgress
+type AIBatchProgress struct {
+	BatchID string `json:"batchId"`
+	Path    string `json:"path"`
+	Done    int    `json:"done"`
+	Total   int    `json:"total"`
+	Error   string `json:"error,omitempty"`
+}
+
+// batchPromptData is what AI templates are rendered with
+type batchPromptData struct {
+	Path    string
+	Name    string
+	Content string
+}
+
+// RunAIBatch applies an AI template to every file in the current project
+// matching fileGlob. Proposals land in the review queue instead of being
+// written, and the batch runs in the background; the returned id
+// identifies it in events and in GetReviewQueue.
+func (a *App) RunAIBatch(fileGlob string, templateID string) (string, error) {
+	if db == nil {
+		return "", fmt.Errorf("database not initialized")
+	}
+	root := a.currentProjectRoot()
+	if root == "" {
+		return "", fmt.Errorf("no project open")
+	}
+
+	tpl, err := db.GetAITemplate(templateID)
+	if err != nil {
+		return "", err
+	}
+	prompt, err := template.New(tpl.Name).Parse(tpl.Prompt)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+
+	files, err := globFiles(root, fileGlob)
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no files match %q", fileGlob)
+	}
+
+	batchID := uuid.New().String()
+	go a.runAIBatch(batchID, files, prompt)
+	return batchID, nil
+}
+
+func (a *App) runAIBatch(batchID string, files []string, prompt *template.Template) {
+	for i, path := range files {
+		item := ReviewItem{BatchID: batchID, Path: path, Status: ReviewPending}
+
+		if err := a.proposeBatchEdit(&item, prompt); err != nil {
+			item.Status = ReviewFailed
+			item.Error = err.Error()
+		}
+		_ = db.AddReviewItem(item)
+
+		runtime.EventsEmit(a.ctx, EventAIBatchProgress, AIBatchProgress{
+			BatchID: batchID,
+			Path:    path,
+			Done:    i + 1,
+			Total:   len(files),
+			Error:   item.Error,
+		})
+	}
+	runtime.EventsEmit(a.ctx, EventAIBatchDone, batchID)
+}
+
+func (a *App) proposeBatchEdit(item *ReviewItem, prompt *template.Template) error {
+	content, err := os.ReadFile(item.Path)
+	if err != nil {
+		return err
+	}
+	item.Original = string(content)
+
+	var buf bytes.Buffer
+	err = prompt.Execute(&buf, batchPromptData{
+		Path:    item.Path,
+		Name:    filepath.Base(item.Path),
+		Content: item.Original,
+	})
+	if err != nil {
+		return err
+	}
+
+	item.Proposed, err = a.generate(aiRequest{
+		Model:    a.aiModel(),
+		Prompt:   buf.String(),
+		Action:   "batch",
+		Document: item.Path,
+	})
+	return err
+}
+
+// GetReviewQueue lists proposed changes, optionally for a single batch
+func (a *App) GetReviewQueue(batchID string) ([]ReviewItem, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	return db.GetReviewItems(batchID)
+}
+
+// AcceptReviewItem writes a proposed change to disk. It refuses when the
+// file was edited after the proposal was made.
+func (a *App) AcceptReviewItem(id string) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	item, err := db.GetReviewItem(id)
+	if err != nil {
+		return err
+	}
+	if item.Status != ReviewPending {
+		return fmt.Errorf("review item is %s", item.Status)
+	}
+
+	current, err := os.ReadFile(item.Path)
+	if err != nil {
+		return err
+	}
+	if string(current) != item.Original {
+		return fmt.Errorf("%s changed since the proposal was made", item.Path)
+	}
+
+	op := a.beginOperation("Accept AI change to " + filepath.Base(item.Path))
+	if err := op.replacing(item.Path); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(item.Path, []byte(item.Proposed), 0644); err != nil {
+		op.discard()
+		return err
+	}
+	a.commitOperation(op)
+	return db.SetReviewStatus(id, ReviewAccepted)
+}
+
+// RejectReviewItem discards a proposed change
+func (a *App) RejectReviewItem(id string) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	return db.SetReviewStatus(id, ReviewRejected)
+}
+
+// ClearReviewBatch removes every queue entry of a batch
+func (a *App) ClearReviewBatch(batchID string) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	return db.ClearReviewItems(batchID)
+}
+
+// AI Template Bindings
+
+func (a *App) SaveAITemplate(t AITemplate) (string, error) {
+	if db == nil {
+		return "", fmt.Errorf("database not initialized")
+	}
+	if _, err := template.New(t.Name).Parse(t.Prompt); err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	return db.SaveAITemplate(t)
+}
+
+func (a *App) GetAITemplates() ([]AITemplate, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	return db.GetAITemplates()
+}
+
+func (a *App) DeleteAITemplate(id string) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	return db.DeleteAITemplate(id)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// recordAIHistory logs a provider call against the current project. Logging
+// is best effort and never fails the request.
+func (a *App) recordAIHistory(req aiRequest, response string, genErr error) {
+	if db == nil {
+		return
+	}
+	entry := AIHistoryEntry{
+		Project:  a.currentProjectRoot(),
+		Action:   req.Action,
+		Document: req.Document,
+		Model:    req.Model,
+		Prompt:   req.Prompt,
+		Response: response,
+	}
+	if len(req.Images) > 0 {
+		entry.Prompt += fmt.Sprintf("\n\n[%d image(s) attached]", len(req.Images))
+	}
+	if genErr != nil {
+		entry.Error = genErr.Error()
+	}
+	_ = db.AddAIHistory(entry)
+}
+
+// GetAIHistory lists the AI interactions recorded for a project
+func (a *App) GetAIHistory(projectPath string) ([]AIHistoryEntry, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	return db.GetAIHistory(projectPath)
+}
+
+// ExportAIHistory renders a project's AI history as "json", "csv" or
+// "markdown" so it can be archived alongside the content it produced
+func (a *App) ExportAIHistory(projectPath string, format string) (string, error) {
+	if db == nil {
+		return "", fmt.Errorf("database not initialized")
+	}
+	entries, err := db.GetAIHistory(projectPath)
+	if err != nil {
+		return "", err
+	}
+
+	switch strings.ToLower(format) {
+	case "", "json":
+		data, err := json.MarshalIndent(entries, "", "  ")
+		return string(data), err
+	case "csv":
+		return aiHistoryCSV(entries)
+	case "md", "markdown":
+		return aiHistoryMarkdown(projectPath, entries), nil
+	default:
+		return "", fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+func aiHistoryCSV(entries []AIHistoryEntry) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"time", "action", "document", "model", "prompt", "response", "error"})
+	for _, e := range entries {
+		_ = w.Write([]string{
+			e.CreatedAt.Format(time.RFC3339), e.Action, e.Document, e.Model, e.Prompt, e.Response, e.Error,
+		})
+	}
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+func aiHistoryMarkdown(projectPath string, entries []AIHistoryEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# AI history: %s\n\n", projectPath)
+	for _, e := range entries {
+		fmt.Fprintf(&b, "## %s %s\n\n", e.CreatedAt.Format(time.RFC3339), orDefault(e.Action, "request"))
+		if e.Document != "" {
+			fmt.Fprintf(&b, "- Document: `%s`\n", e.Document)
+		}
+		fmt.Fprintf(&b, "- Model: `%s`\n\n", e.Model)
+		fmt.Fprintf(&b, "### Prompt\n\n%s\n\n", fenced(e.Prompt))
+		if e.Error != "" {
+			fmt.Fprintf(&b, "### Error\n\n%s\n\n", e.Error)
+		} else {
+			fmt.Fprintf(&b, "### Response\n\n%s\n\n", fenced(e.Response))
+		}
+	}
+	return b.String()
+}
+
+// fenced wraps text in a code fence longer than any backtick run inside it
+func fenced(text string) string {
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	return fence + "\n" + strings.TrimRight(text, "\n") + "\n" + fence
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TitleSuggestions are candidate names for a section
+type TitleSuggestions struct {
+	SectionTitles []string `json:"sectionTitles"`
+	PageTitles    []string `json:"pageTitles"`
+}
+
+// SuggestTitles proposes count section titles and count SEO-friendly page
+// titles for the given section text
+func (a *App) SuggestTitles(sectionText string, count int) (*TitleSuggestions, error) {
+	if count <= 0 {
+		count = 5
+	}
+
+	prompt := fmt.Sprintf(`You are a technical editor naming a documentation section.
+Suggest %d concise section titles (sentence case, no trailing punctuation) and
+%d SEO-friendly page titles (under 60 characters, front-load the key term) for
+the AsciiDoc content below.
+Respond ONLY with JSON of the form {"sectionTitles": ["..."], "pageTitles": ["..."]}.
+
+Content:
+%s`, count, count, sectionText)
+
+	var result TitleSuggestions
+	if err := a.generateJSON(aiRequest{Model: a.aiModel(), Prompt: prompt, Action: "suggestTitles"}, &result); err != nil {
+		return nil, err
+	}
+	if len(result.SectionTitles) > count {
+		result.SectionTitles = result.SectionTitles[:count]
+	}
+	if len(result.PageTitles) > count {
+		result.PageTitles = result.PageTitles[:count]
+	}
+	return &result, nil
+}
+
+// DiagramSuggestion is text-based diagram source proposed for an image
+type DiagramSuggestion struct {
+	PlantUML string `json:"plantuml"`
+	Mermaid  string `json:"mermaid"`
+	Notes    string `json:"notes"`
+}
+
+// SuggestDiagramSource sends a diagram screenshot to a vision model and
+// proposes equivalent PlantUML and Mermaid source
+func (a *App) SuggestDiagramSource(imagePath string) (*DiagramSuggestion, error) {
+	data, err := os.ReadFile(imagePath)
+	if err != nil {
+		return nil, err
+	}
+	mimeType := detectMimeType(imagePath, data)
+	if !strings.HasPrefix(mimeType, "image/") {
+		return nil, fmt.Errorf("%s is not an image (%s)", imagePath, mimeType)
+	}
+
+	prompt := `The attached image is a diagram from technical documentation.
+Recreate it as text-based diagram source, preserving every node, label,
+connection and grouping you can read. Pick the most fitting diagram type
+(sequence, flowchart, class, component, ...).
+Respond ONLY with JSON of the form
+{"plantuml": "@startuml\n...\n@enduml", "mermaid": "...", "notes": "..."}
+where "notes" lists anything you could not read or had to guess.`
+
+	var result DiagramSuggestion
+	err = a.generateJSON(aiRequest{
+		Model:    a.aiModel(),
+		Prompt:   prompt,
+		Images:   []aiImage{{MimeType: mimeType, Data: data}},
+		Action:   "suggestDiagramSource",
+		Document: imagePath,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
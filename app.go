@@ -6,27 +6,68 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	goruntime "runtime"
 	"strings"
+	"sync"
 
-	"github.com/google/generative-ai-go/genai"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
-	"google.golang.org/api/option"
 )
 
 // App struct
 type App struct {
 	ctx context.Context
+
+	// loaded remembers what each file looked like when it was read, so
+	// saves can detect edits made outside the app
+	loadedMu sync.Mutex
+	loaded   map[string]fileStamp
+
+	// safeMode starts the app with default preferences and AI switched off
+	safeMode bool
+	// projectRoot pins the project for a command line check instead of
+	// the projectRoot preference; see runCheckCommand
+	projectRoot string
+
+	// pendingOpen is the file given on the command line, held until the
+	// frontend asks for it
+	pendingOpenMu sync.Mutex
+	pendingOpen   *OpenLocation
+
+	// diagnostics holds each source's findings per file for the problems
+	// panel, and quickFixes the fixes last offered for them
+	diagnosticsMu sync.Mutex
+	diagnostics   map[diagnosticKey][]Diagnostic
+	quickFixes    map[string]*pendingFix
+
+	// discards holds the confirmation tokens of PrepareDiscardChanges
+	discardMu sync.Mutex
+	discards  map[string]pendingDiscard
 }
 
 // NewApp creates a new App application struct
 func NewApp() *App {
-	return &App{}
+	return &App{
+		loaded:      make(map[string]fileStamp),
+		diagnostics: make(map[diagnosticKey][]Diagnostic),
+		quickFixes:  make(map[string]*pendingFix),
+		discards:    make(map[string]pendingDiscard),
+	}
 }
 
 // startup is called when the app starts. The context is saved
 // so we can call the runtime methods
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+	runtime.OnFileDrop(ctx, a.handleFileDrop)
+	if !a.safeMode {
+		go a.runShadowSnapshots(ctx)
+		go a.purgeExpiredTrash()
+		go a.watchProjectRoot(ctx)
+		go a.startWebClipper()
+		go a.runEmailInbox(ctx)
+		go a.runCloudBackups(ctx)
+		go a.runAutoFetch(ctx)
+	}
 }
 
 // Greet returns a greeting for the given name
@@ -34,18 +75,81 @@ func (a *App) Greet(name string) string {
 	return fmt.Sprintf("Hello %s, It's show time!", name)
 }
 
-// ReadFile reads the content of a file
+// ReadFile reads the content of a file. Cloud placeholders are not read,
+// since that would block until they are downloaded; see HydrateFile.
 func (a *App) ReadFile(path string) (string, error) {
-	content, err := os.ReadFile(path)
+	if cloudPlaceholder(path) {
+		return "", errNotDownloaded("read", path)
+	}
+	content, err := os.ReadFile(longPath(path))
 	if err != nil {
-		return "", err
+		return "", newFileError("read", path, err)
 	}
+	a.rememberStamp(path, content)
 	return string(content), nil
 }
 
-// SaveFile saves content to a file
+// SaveFile saves content to a file. It refuses to overwrite changes made
+// on disk since the file was read; see CheckSaveConflict and OverwriteFile.
+// Failures are returned as a *FileError, a conflict with both versions.
 func (a *App) SaveFile(path string, content string) error {
-	return os.WriteFile(path, []byte(content), 0644)
+	if conflict, err := a.CheckSaveConflict(path, content); err != nil {
+		return newFileError("save", path, err)
+	} else if conflict != nil {
+		fe := newFileError("save", path, ErrSaveConflict)
+		fe.Conflict = conflict
+		return fe
+	}
+	return a.OverwriteFile(path, content)
+}
+
+// OverwriteFile saves content without checking for external changes.
+// Line endings are normalized according to the project's preference.
+func (a *App) OverwriteFile(path string, content string) error {
+	if info, err := os.Stat(path); err == nil && isReadOnly(path, info) {
+		return errReadOnly("save", path)
+	}
+
+	data := normalizeLineEndings([]byte(content), a.lineEndingFor(path))
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return newFileError("save", path, err)
+	}
+	a.rememberStamp(path, data)
+	return nil
+}
+
+// FileContent is a document to write with SaveAll
+type FileContent struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// SaveResult is the outcome of saving one file in SaveAll
+type SaveResult struct {
+	Path  string     `json:"path"`
+	Saved bool       `json:"saved"`
+	Error *FileError `json:"error,omitempty"`
+}
+
+// SaveAll saves several documents in one call. Each file is written
+// atomically and with the same conflict check as SaveFile; a failure is
+// reported in its result without stopping the others. Saved files have
+// their shadow copies cleared.
+func (a *App) SaveAll(files []FileContent) []SaveResult {
+	results := make([]SaveResult, 0, len(files))
+	for _, f := range files {
+		result := SaveResult{Path: f.Path}
+		if err := a.SaveFile(f.Path, f.Content); err != nil {
+			result.Error = newFileError("save", f.Path, err)
+		} else {
+			result.Saved = true
+			if db != nil {
+				_ = db.ClearShadowFile(f.Path)
+			}
+		}
+		results = append(results, result)
+	}
+	return results
 }
 
 // SelectFile opens a file dialog and returns the path
@@ -106,22 +210,8 @@ func (a *App) SelectSvgFile() (string, error) {
 	})
 }
 
-// GenerateContent generates AsciiDoc content using Gemini
+// GenerateContent generates AsciiDoc content using the configured AI provider
 func (a *App) GenerateContent(prompt string, contextText string) (string, error) {
-	apiKey := os.Getenv("GEMINI_API_KEY")
-	if apiKey == "" {
-		return "", fmt.Errorf("GEMINI_API_KEY not set")
-	}
-
-	client, err := genai.NewClient(a.ctx, option.WithAPIKey(apiKey))
-	if err != nil {
-		return "", err
-	}
-	defer client.Close()
-
-	model := client.GenerativeModel("gemini-2.0-flash")
-	model.SetTemperature(0.7)
-
 	fullPrompt := fmt.Sprintf(`You are an expert technical writer and AsciiDoc specialist.
     Your task is to generate or improve AsciiDoc content based on the user's request.
     
@@ -136,135 +226,27 @@ func (a *App) GenerateContent(prompt string, contextText string) (string, error)
 		return ""
 	}())
 
-	resp, err := model.GenerateContent(a.ctx, genai.Text(fullPrompt))
-	if err != nil {
-		return "", err
-	}
-
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		return "", fmt.Errorf("no content generated")
-	}
-
-	// Extract text from parts
-	var result string
-	for _, part := range resp.Candidates[0].Content.Parts {
-		if txt, ok := part.(genai.Text); ok {
-			result += string(txt)
-		}
-	}
-
-	return result, nil
+	temperature := float32(0.7)
+	return a.generate(aiRequest{
+		Model:       a.aiModel(),
+		Temperature: &temperature,
+		Prompt:      fullPrompt,
+		Action:      "generateContent",
+	})
 }
 
 // FixGrammar fixes grammar in the given text
 func (a *App) FixGrammar(text string) (string, error) {
-	apiKey := os.Getenv("GEMINI_API_KEY")
-	if apiKey == "" {
-		return "", fmt.Errorf("GEMINI_API_KEY not set")
-	}
-
-	client, err := genai.NewClient(a.ctx, option.WithAPIKey(apiKey))
-	if err != nil {
-		return "", err
-	}
-	defer client.Close()
-
-	model := client.GenerativeModel("gemini-1.5-flash")
-
 	prompt := fmt.Sprintf(`Fix the grammar and improve the clarity of the following AsciiDoc text. Maintain all AsciiDoc syntax/formatting exactly as is. Output ONLY the corrected text.
 
 Text:
 %s`, text)
 
-	resp, err := model.GenerateContent(a.ctx, genai.Text(prompt))
-	if err != nil {
-		return "", err
-	}
-
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		return "", fmt.Errorf("no content generated")
-	}
-
-	var result string
-	for _, part := range resp.Candidates[0].Content.Parts {
-		if txt, ok := part.(genai.Text); ok {
-			result += string(txt)
-		}
-	}
-
-	return result, nil
-}
-
-// FileNode represents a file or directory in the file system
-type FileNode struct {
-	Name     string      `json:"name"`
-	Path     string      `json:"path"`
-	IsDir    bool        `json:"isDir"`
-	Children []*FileNode `json:"children,omitempty"`
-}
-
-// GetFileTree returns the file structure of the given directory
-func (a *App) GetFileTree(dirPath string) ([]*FileNode, error) {
-	if dirPath == "" {
-		dirPath = "./content"
-	}
-
-	// Create content dir if it doesn't exist (legacy behavior)
-	if dirPath == "./content" {
-		if _, err := os.Stat(dirPath); os.IsNotExist(err) {
-			_ = os.Mkdir(dirPath, 0755)
-		}
-	}
-
-	return a.readDirRecursive(dirPath)
-}
-
-func (a *App) readDirRecursive(dirPath string) ([]*FileNode, error) {
-	entries, err := os.ReadDir(dirPath)
-	if err != nil {
-		return nil, err
-	}
-
-	// Check showHiddenFiles preference
-	showHiddenRaw, _ := a.GetPreference("showHiddenFiles")
-	showHidden, _ := showHiddenRaw.(bool)
-
-	var nodes []*FileNode
-	for _, entry := range entries {
-		// Skip hidden files/dirs unless preference is set
-		if !showHidden && strings.HasPrefix(entry.Name(), ".") {
-			continue
-		}
-
-		path := filepath.Join(dirPath, entry.Name())
-		node := &FileNode{
-			Name:  entry.Name(),
-			Path:  path,
-			IsDir: entry.IsDir(),
-		}
-
-		if entry.IsDir() {
-			children, err := a.readDirRecursive(path)
-			if err == nil {
-				node.Children = children
-			}
-			// Only add directories if they have content or just add them anyway?
-			// User wants to traverse subfolders.
-			nodes = append(nodes, node)
-		} else {
-			// Filter for .adoc files or just include all?
-			// The previous logic filtered for .adoc. Let's keep that for files, but maybe allow others?
-			// User said "FS navigation tree", usually implies all relevant files.
-			// Let's stick to .adoc and maybe .txt for now as per previous filter,
-			// but maybe we should be more permissive for a general tree.
-			// Let's allow .adoc, .txt, .md, .json, .css for now.
-			ext := strings.ToLower(filepath.Ext(entry.Name()))
-			if ext == ".adoc" || ext == ".txt" || ext == ".md" || ext == ".css" {
-				nodes = append(nodes, node)
-			}
-		}
-	}
-	return nodes, nil
+	return a.generate(aiRequest{
+		Model:  a.aiModel(),
+		Prompt: prompt,
+		Action: "fixGrammar",
+	})
 }
 
 // ListFiles lists .adoc files in the given directory (Flat list for backward compatibility or simple search)
@@ -294,7 +276,10 @@ func (a *App) ListFiles(dirPath string) ([]string, error) {
 	return files, nil
 }
 
-// OpenGitClient opens the repository in the configured git client
+// OpenGitClient opens the repository in the configured git client: the
+// executable in "git_client_path", the tool named by "git_client" (such as
+// gitkraken, fork, sublime-merge, tower or sourcetree), or GitHub Desktop.
+// It returns false when that client isn't installed.
 func (a *App) OpenGitClient(path string) (bool, error) {
 	if path == "" {
 		var err error
@@ -336,11 +321,23 @@ func (a *App) OpenGitClient(path string) (bool, error) {
 		return true, err
 	}
 
-	// Fallback to GitHub Desktop legacy logic
-	// Check registry for x-github-client protocol
-	cmd := exec.Command("reg", "query", "HKCR\\x-github-client")
-	if err := cmd.Run(); err != nil {
-		return false, nil // Protocol not found
+	// A client chosen by id from DetectInstalledTools
+	if id := a.prefString("git_client"); id != "" && id != "github-desktop" {
+		tool, ok := findTool(id)
+		if !ok || !tool.Installed {
+			return false, nil
+		}
+		return true, launchTool(tool, path)
+	}
+
+	// Fall back to GitHub Desktop
+	desktop, ok := detectGitHubDesktop()
+	if !ok {
+		return false, nil
+	}
+	if goruntime.GOOS == "linux" {
+		// The Linux builds don't reliably register the protocol
+		return true, exec.Command(desktop, path).Start()
 	}
 
 	// Try to get remote URL first
@@ -381,6 +378,9 @@ func (a *App) GetPreference(key string) (interface{}, error) {
 	if db == nil {
 		return nil, fmt.Errorf("database not initialized")
 	}
+	if a.safeMode && !safeModePreferences[key] {
+		return nil, nil
+	}
 	return db.GetPreference(key)
 }
 
@@ -477,6 +477,96 @@ func (a *App) UpdateProjectLastOpened(path string) error {
 	return db.UpdateProjectLastOpened(path)
 }
 
+func (a *App) SaveProjectSetting(project string, key string, value interface{}) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	return db.SetProjectSetting(project, key, value)
+}
+
+// GetProjectSettings returns the DB-stored settings of a project merged with
+// the settings block of its committed config file
+func (a *App) GetProjectSettings(project string) (map[string]interface{}, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	settings, err := db.GetProjectSettings(project)
+	if err != nil {
+		return nil, err
+	}
+	if cfg, err := LoadProjectConfig(project); err == nil && cfg != nil {
+		for k, v := range cfg.Settings {
+			settings[k] = v
+		}
+	}
+	return settings, nil
+}
+
+// projectSetting looks a setting up for the current project: the committed
+// config file first, then the DB-stored project settings, then the global
+// preference of the same key. Safe mode ignores all of them.
+func (a *App) projectSetting(key string) interface{} {
+	return a.projectSettingIn(a.currentProjectRoot(), key)
+}
+
+// projectSettingIn is projectSetting for the project at root, for calls
+// that name their project rather than acting on the open one
+func (a *App) projectSettingIn(root, key string) interface{} {
+	if a.safeMode {
+		return nil
+	}
+	if cfg, err := LoadProjectConfig(root); err == nil && cfg != nil {
+		if v, ok := cfg.Settings[key]; ok {
+			return v
+		}
+	}
+	if db == nil {
+		return nil
+	}
+	if root != "" {
+		if v, err := db.GetProjectSetting(root, key); err == nil && v != nil {
+			return v
+		}
+	}
+	v, _ := db.GetPreference(key)
+	return v
+}
+
+// userSetting is projectSetting without the committed config file, for
+// settings a cloned repository must not choose, such as servers and
+// credentials
+func (a *App) userSetting(key string) interface{} {
+	if db == nil {
+		return nil
+	}
+	if root := a.currentProjectRoot(); root != "" {
+		if v, err := db.GetProjectSetting(root, key); err == nil && v != nil {
+			return v
+		}
+	}
+	v, _ := db.GetPreference(key)
+	return v
+}
+
+// userSettingString is userSetting for string values with a default
+func (a *App) userSettingString(key, fallback string) string {
+	if v, ok := a.userSetting(key).(string); ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
+// currentProjectRoot returns the project root the frontend last selected,
+// or the one given on the command line
+func (a *App) currentProjectRoot() string {
+	if a.projectRoot != "" {
+		return a.projectRoot
+	}
+	rootRaw, _ := a.GetPreference("projectRoot")
+	root, _ := rootRaw.(string)
+	return root
+}
+
 func (a *App) GetDefaultProjectRoot() (string, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -491,14 +581,28 @@ func (a *App) AddGitIcon(svg string) (string, error) {
 	if db == nil {
 		return "", fmt.Errorf("database not initialized")
 	}
+	if err := checkIconSVG(svg); err != nil {
+		return "", err
+	}
 	return db.AddGitIcon(svg)
 }
 
+// GetGitIcons returns the stored icons, leaving out any saved before
+// checkIconSVG was as strict as it is now
 func (a *App) GetGitIcons() (map[string]string, error) {
 	if db == nil {
 		return nil, fmt.Errorf("database not initialized")
 	}
-	return db.GetGitIcons()
+	icons, err := db.GetGitIcons()
+	if err != nil {
+		return nil, err
+	}
+	for id, svg := range icons {
+		if checkIconSVG(svg) != nil {
+			delete(icons, id)
+		}
+	}
+	return icons, nil
 }
 
 func (a *App) DeleteGitIcon(id string) error {
@@ -507,3 +611,14 @@ func (a *App) DeleteGitIcon(id string) error {
 	}
 	return db.DeleteGitIcon(id)
 }
+
+// UpdateGitIcon replaces the SVG of an existing icon
+func (a *App) UpdateGitIcon(id string, svg string) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	if err := checkIconSVG(svg); err != nil {
+		return err
+	}
+	return db.UpdateGitIcon(id, svg)
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// EventArchiveProgress is emitted while ExportProjectArchive runs
+const EventArchiveProgress = "archive:progress"
+
+// ArchiveOptions controls ExportProjectArchive
+type ArchiveOptions struct {
+	ExcludeGit     bool `json:"excludeGit"`
+	ExcludeIgnored bool `json:"excludeIgnored"`
+	// Reproducible stamps every entry with the same timestamp and
+	// permissions, records the source commit in the zip comment and adds
+	// a MANIFEST.json of checksums, so identical sources give identical
+	// bytes
+	Reproducible bool `json:"reproducible"`
+	// StripImageMetadata removes EXIF/GPS data, embedded thumbnails and
+	// comments from JPEG, PNG and WebP images on their way into the zip
+	StripImageMetadata bool `json:"stripImageMetadata"`
+	// Preset names the export preset the archive is made for; when its
+	// audience is public, AsciiDoc files lose their hidden sections and
+	// blocks as in PrepareExportSource
+	Preset string `json:"preset,omitempty"`
+}
+
+// ArchiveProgress is the payload of EventArchiveProgress
+type ArchiveProgress struct {
+	Path    string `json:"path"`
+	Current string `json:"current"`
+	Added   int    `json:"added"`
+	Total   int    `json:"total"`
+	// SHA256 of the finished zip, also written to <zip>.sha256
+	SHA256 string `json:"sha256,omitempty"`
+	Commit string `json:"commit,omitempty"`
+}
+
+// ExportProjectArchive zips a project into destZip. The trash folder is
+// always left out; .git and ignored files optionally. A sha256sum-style
+// checksum of the zip is written alongside it.
+func (a *App) ExportProjectArchive(projectPath string, destZip string, opts ArchiveOptions) (*ArchiveProgress, error) {
+	files, err := archiveFiles(projectPath, destZip, opts)
+	if err != nil {
+		return nil, err
+	}
+	var hidden map[string]bool
+	if opts.Preset != "" {
+		audience, err := presetAudience(projectPath, opts.Preset)
+		if err != nil {
+			return nil, err
+		}
+		if audience == ExportAudiencePublic {
+			hidden = a.hiddenRoles()
+		}
+	}
+	progress := &ArchiveProgress{Path: destZip, Total: len(files)}
+
+	if err := os.MkdirAll(filepath.Dir(destZip), 0755); err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(destZip), "."+filepath.Base(destZip)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	var manifest *ExportManifest
+	if opts.Reproducible {
+		progress.Commit = gitHead(projectPath)
+		manifest = &ExportManifest{Commit: progress.Commit, Created: reproducibleTime()}
+	}
+
+	zw := zip.NewWriter(tmp)
+	for _, rel := range files {
+		progress.Current = rel
+		if err := addToZip(zw, projectPath, rel, opts.StripImageMetadata, hidden, manifest); err != nil {
+			zw.Close()
+			tmp.Close()
+			return progress, fmt.Errorf("adding %s: %w", rel, err)
+		}
+		progress.Added++
+		if a.ctx != nil && progress.Added%copyProgressEvery == 0 {
+			runtime.EventsEmit(a.ctx, EventArchiveProgress, *progress)
+		}
+	}
+	if manifest != nil {
+		if err := addManifest(zw, manifest); err != nil {
+			zw.Close()
+			tmp.Close()
+			return progress, err
+		}
+		if progress.Commit != "" {
+			zw.SetComment("commit " + progress.Commit)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		tmp.Close()
+		return progress, err
+	}
+	if err := tmp.Close(); err != nil {
+		return progress, err
+	}
+	if err := os.Rename(tmpPath, destZip); err != nil {
+		return progress, err
+	}
+	if sum, _, err := fileChecksum(destZip); err == nil {
+		progress.SHA256 = sum
+		if err := writeChecksumFile(destZip, sum); err != nil {
+			return progress, err
+		}
+	}
+
+	progress.Current = ""
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, EventArchiveProgress, *progress)
+	}
+	return progress, nil
+}
+
+// archiveFiles lists the slash-separated relative paths to put in the zip
+func archiveFiles(root, destZip string, opts ArchiveOptions) ([]string, error) {
+	destAbs, _ := filepath.Abs(destZip)
+	matchers := map[string]*ignoreMatcher{".": (&ignoreMatcher{}).withDir(root, "")}
+
+	var files []string
+	// WalkDir visits entries in lexical order, so the list is sorted
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		slashRel := filepath.ToSlash(rel)
+		ignore := matchers[filepath.Dir(rel)]
+
+		if d.IsDir() {
+			switch {
+			case d.Name() == ProjectTrashDir,
+				opts.ExcludeGit && d.Name() == ".git",
+				opts.ExcludeIgnored && ignore.Match(slashRel, true):
+				return filepath.SkipDir
+			}
+			matchers[rel] = ignore.withDir(path, slashRel)
+			return nil
+		}
+
+		if opts.ExcludeIgnored && ignore.Match(slashRel, false) {
+			return nil
+		}
+		if abs, _ := filepath.Abs(path); abs == destAbs || abs == destAbs+".sha256" {
+			return nil
+		}
+		files = append(files, slashRel)
+		return nil
+	})
+	return files, err
+}
+
+// addToZip stores one file. With hidden roles, AsciiDoc files are stripped
+// of them. With a manifest, the entry is normalised for reproducible output
+// and its checksum recorded.
+func addToZip(zw *zip.Writer, root, rel string, stripMetadata bool, hidden map[string]bool, manifest *ExportManifest) error {
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = rel
+	header.Method = zip.Deflate
+	if manifest != nil {
+		header.Modified = manifest.Created
+		header.Extra = nil
+		mode := os.FileMode(0644)
+		if info.Mode()&0111 != 0 {
+			mode = 0755
+		}
+		header.SetMode(info.Mode().Type() | mode)
+	}
+
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		link, err := os.Readlink(path)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, link)
+		return err
+	}
+
+	var src io.Reader
+	if hidden != nil && isPage(path) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		src = strings.NewReader(stripHiddenContent(string(data), hidden).Content)
+	} else if stripMetadata && isImageWithMetadata(path) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		src = bytes.NewReader(stripImageMetadata(path, data))
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		src = f
+	}
+	if manifest == nil {
+		_, err = io.Copy(w, src)
+		return err
+	}
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(w, h), src)
+	if err != nil {
+		return err
+	}
+	manifest.Files = append(manifest.Files, ManifestEntry{Path: rel, Size: n, SHA256: hex.EncodeToString(h.Sum(nil))})
+	return nil
+}
+
+func addManifest(zw *zip.Writer, manifest *ExportManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     ManifestName,
+		Method:   zip.Deflate,
+		Modified: manifest.Created,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
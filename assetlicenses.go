@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// assetExtensions are the files tracked in the asset license report
+var assetExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true,
+	".webp": true, ".bmp": true, ".tif": true, ".tiff": true,
+}
+
+// AssetUsage is an image in the project and the documents that show it
+type AssetUsage struct {
+	Path    string        `json:"path"`
+	UsedIn  []string      `json:"usedIn"`
+	License *AssetLicense `json:"license,omitempty"`
+}
+
+// AssetLicenseReport is the result of GetAssetLicenseReport
+type AssetLicenseReport struct {
+	Assets []AssetUsage `json:"assets"`
+	// Unlicensed are assets with no license recorded
+	Unlicensed []AssetUsage `json:"unlicensed"`
+	// Orphaned are licenses recorded for files that no longer exist
+	Orphaned []AssetLicense `json:"orphaned"`
+}
+
+// SetAssetLicense records the license and source of an asset. Path may be
+// absolute or relative to the project.
+func (a *App) SetAssetLicense(projectPath string, license AssetLicense) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	rel, err := projectRelPath(projectPath, license.Path)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(license.License) == "" {
+		return fmt.Errorf("license must not be empty; use an SPDX identifier such as CC-BY-4.0")
+	}
+	license.Path = rel
+	return db.SetAssetLicense(projectPath, license)
+}
+
+// GetAssetLicenses lists the licenses recorded for a project's assets
+func (a *App) GetAssetLicenses(projectPath string) ([]AssetLicense, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	return db.GetAssetLicenses(projectPath)
+}
+
+// RemoveAssetLicense forgets the license recorded for an asset
+func (a *App) RemoveAssetLicense(projectPath string, assetPath string) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	rel, err := projectRelPath(projectPath, assetPath)
+	if err != nil {
+		return err
+	}
+	return db.DeleteAssetLicense(projectPath, rel)
+}
+
+// GetAssetLicenseReport lists every image in the project with the
+// documents using it and its recorded license, for legal review
+func (a *App) GetAssetLicenseReport(projectPath string) (*AssetLicenseReport, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	licenses, err := db.GetAssetLicenses(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	files, err := globFiles(projectPath, "**/*")
+	if err != nil {
+		return nil, err
+	}
+	cfg, _ := LoadProjectConfig(projectPath)
+	attrs := projectAttributes(projectPath, cfg)
+
+	assets := make(map[string]*AssetUsage)
+	for _, path := range files {
+		if assetExtensions[strings.ToLower(filepath.Ext(path))] {
+			rel, _ := projectRelPath(projectPath, path)
+			assets[rel] = &AssetUsage{Path: rel, UsedIn: []string{}}
+		}
+	}
+
+	for _, path := range files {
+		if strings.ToLower(filepath.Ext(path)) != ".adoc" {
+			continue
+		}
+		content, err := os.ReadFile(longPath(path))
+		if err != nil {
+			continue
+		}
+		doc := &lintDocument{Path: path, Content: string(content)}
+		docRel, _ := projectRelPath(projectPath, path)
+		for _, ref := range parseReferences(doc.Content, attrs) {
+			if ref.Kind != refImage {
+				continue
+			}
+			rel, err := projectRelPath(projectPath, referencePath(doc, ref))
+			if err != nil {
+				continue
+			}
+			if asset, ok := assets[rel]; ok && !slices.Contains(asset.UsedIn, docRel) {
+				asset.UsedIn = append(asset.UsedIn, docRel)
+			}
+		}
+	}
+
+	report := &AssetLicenseReport{Assets: []AssetUsage{}, Unlicensed: []AssetUsage{}, Orphaned: []AssetLicense{}}
+	for i := range licenses {
+		if asset, ok := assets[licenses[i].Path]; ok {
+			asset.License = &licenses[i]
+		} else {
+			report.Orphaned = append(report.Orphaned, licenses[i])
+		}
+	}
+	for _, asset := range assets {
+		report.Assets = append(report.Assets, *asset)
+		if asset.License == nil {
+			report.Unlicensed = append(report.Unlicensed, *asset)
+		}
+	}
+	sort.Slice(report.Assets, func(i, j int) bool { return report.Assets[i].Path < report.Assets[j].Path })
+	sort.Slice(report.Unlicensed, func(i, j int) bool { return report.Unlicensed[i].Path < report.Unlicensed[j].Path })
+	return report, nil
+}
+
+// projectRelPath returns path relative to the project, slash-separated
+func projectRelPath(projectPath, path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(projectPath, path)
+	}
+	if !isWithin(path, projectPath) {
+		return "", fmt.Errorf("%s is outside the project", path)
+	}
+	rel, err := filepath.Rel(projectPath, path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
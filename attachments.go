@@ -0,0 +1,326 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Kinds of AttachmentPreview
+const (
+	AttachmentPDF         = "pdf"
+	AttachmentDocument    = "document"
+	AttachmentSpreadsheet = "spreadsheet"
+	AttachmentSlides      = "slides"
+)
+
+// attachmentTextLimit caps the extracted text returned in a preview
+const attachmentTextLimit = 4000
+
+// AttachmentPreview is a quick look at a PDF or Office file linked from a
+// document
+type AttachmentPreview struct {
+	Path string `json:"path"`
+	Kind string `json:"kind"`
+	Size int64  `json:"size"`
+	// Pages is the page, sheet or slide count, when known
+	Pages int `json:"pages,omitempty"`
+	// Sheets are the sheet names of a spreadsheet
+	Sheets []string `json:"sheets,omitempty"`
+	// Text is the start of the extracted text
+	Text      string `json:"text"`
+	Truncated bool   `json:"truncated,omitempty"`
+	// Thumbnail is a PNG data URL of the first page, when a renderer is
+	// installed (pdftoppm from poppler)
+	Thumbnail string `json:"thumbnail,omitempty"`
+}
+
+// GetAttachmentPreview extracts a text summary of a PDF, DOCX, XLSX or
+// PPTX file, plus a first-page render of PDFs where possible, so linked
+// attachments can be peeked at without opening another app
+func (a *App) GetAttachmentPreview(path string) (*AttachmentPreview, error) {
+	if cloudPlaceholder(path) {
+		return nil, errNotDownloaded("preview", path)
+	}
+	data, err := os.ReadFile(longPath(path))
+	if err != nil {
+		return nil, newFileError("preview", path, err)
+	}
+
+	p := &AttachmentPreview{Path: path, Size: int64(len(data))}
+	var text string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".pdf":
+		p.Kind = AttachmentPDF
+		p.Pages = pdfPageCount(data)
+		text = pdfText(path, data)
+		p.Thumbnail = pdfThumbnail(path)
+	case ".docx":
+		p.Kind = AttachmentDocument
+		text, err = docxText(data)
+	case ".xlsx":
+		p.Kind = AttachmentSpreadsheet
+		text, p.Sheets, err = xlsxText(data)
+		p.Pages = len(p.Sheets)
+	case ".pptx":
+		p.Kind = AttachmentSlides
+		text, p.Pages, err = pptxText(data)
+	default:
+		return nil, fmt.Errorf("no attachment preview for %s", filepath.Base(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	p.Text, p.Truncated = truncateText(strings.TrimSpace(text), attachmentTextLimit)
+	return p, nil
+}
+
+func truncateText(s string, limit int) (string, bool) {
+	if len(s) <= limit {
+		return s, false
+	}
+	for limit > 0 && !utf8.RuneStart(s[limit]) {
+		limit--
+	}
+	return s[:limit], true
+}
+
+// PDF
+
+var (
+	pdfPageExpr   = regexp.MustCompile(`/Type\s*/Page[^s]`)
+	pdfStreamExpr = regexp.MustCompile(`(?s)stream\r?\n(.*?)\r?\nendstream`)
+	pdfTextExpr   = regexp.MustCompile(`(?s)\[(.*?)\]\s*TJ|\((.*?[^\\])\)\s*(?:Tj|'|")|(T\*|ET)`)
+	pdfStringExpr = regexp.MustCompile(`\((.*?[^\\])\)`)
+)
+
+func pdfPageCount(data []byte) int {
+	return len(pdfPageExpr.FindAll(data, -1))
+}
+
+// pdfText uses pdftotext when installed, otherwise pulls the literal
+// strings out of the content streams. The fallback handles simply encoded
+// PDFs; text in custom-encoded fonts comes out empty.
+func pdfText(path string, data []byte) string {
+	if bin, err := exec.LookPath("pdftotext"); err == nil {
+		out, err := exec.Command(bin, "-l", "3", "-enc", "UTF-8", path, "-").Output()
+		if err == nil {
+			return string(out)
+		}
+	}
+
+	var b strings.Builder
+	for _, m := range pdfStreamExpr.FindAllSubmatch(data, -1) {
+		stream := m[1]
+		if r, err := zlib.NewReader(bytes.NewReader(stream)); err == nil {
+			stream, err = io.ReadAll(io.LimitReader(r, 4<<20))
+			r.Close()
+			if err != nil && len(stream) == 0 {
+				continue
+			}
+		}
+		for _, t := range pdfTextExpr.FindAllSubmatch(stream, -1) {
+			switch {
+			case t[1] != nil:
+				for _, s := range pdfStringExpr.FindAllSubmatch(t[1], -1) {
+					b.WriteString(pdfUnescape(s[1]))
+				}
+			case t[2] != nil:
+				b.WriteString(pdfUnescape(t[2]))
+			default:
+				b.WriteString("\n")
+			}
+		}
+		if b.Len() > attachmentTextLimit {
+			break
+		}
+	}
+	return b.String()
+}
+
+func pdfUnescape(s []byte) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch c := s[i]; c {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r', 't':
+			b.WriteByte(' ')
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			j := i
+			for j < len(s) && j < i+3 && s[j] >= '0' && s[j] <= '7' {
+				j++
+			}
+			n, _ := strconv.ParseUint(string(s[i:j]), 8, 8)
+			b.WriteRune(rune(n))
+			i = j - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// pdfThumbnail renders the first page with pdftoppm, if it is installed
+func pdfThumbnail(path string) string {
+	bin, err := exec.LookPath("pdftoppm")
+	if err != nil {
+		return ""
+	}
+	out, err := exec.Command(bin, "-png", "-f", "1", "-l", "1", "-scale-to", "600", "-singlefile", path).Output()
+	if err != nil || len(out) == 0 {
+		return ""
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(out)
+}
+
+// Office Open XML
+
+// ooxmlText collects the character data of every element named textTag,
+// starting a new line at each element named breakTag
+func ooxmlText(f *zip.File, textTag, breakTag string) (string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	var b strings.Builder
+	dec := xml.NewDecoder(io.LimitReader(rc, 32<<20))
+	inText := false
+	for b.Len() <= attachmentTextLimit {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return b.String(), err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			inText = t.Name.Local == textTag
+		case xml.EndElement:
+			inText = false
+			if t.Name.Local == breakTag {
+				b.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				b.Write(t)
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+func openZip(data []byte) (*zip.Reader, error) {
+	return zip.NewReader(bytes.NewReader(data), int64(len(data)))
+}
+
+func zipEntry(zr *zip.Reader, name string) *zip.File {
+	for _, f := range zr.File {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func docxText(data []byte) (string, error) {
+	zr, err := openZip(data)
+	if err != nil {
+		return "", err
+	}
+	doc := zipEntry(zr, "word/document.xml")
+	if doc == nil {
+		return "", fmt.Errorf("word/document.xml missing")
+	}
+	return ooxmlText(doc, "t", "p")
+}
+
+func xlsxText(data []byte) (string, []string, error) {
+	zr, err := openZip(data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var sheets []string
+	if wb := zipEntry(zr, "xl/workbook.xml"); wb != nil {
+		rc, err := wb.Open()
+		if err != nil {
+			return "", nil, err
+		}
+		var workbook struct {
+			Sheets []struct {
+				Name string `xml:"name,attr"`
+			} `xml:"sheets>sheet"`
+		}
+		err = xml.NewDecoder(rc).Decode(&workbook)
+		rc.Close()
+		if err != nil {
+			return "", nil, err
+		}
+		for _, s := range workbook.Sheets {
+			sheets = append(sheets, s.Name)
+		}
+	}
+
+	// Cell text lives in the shared string table
+	text := ""
+	if ss := zipEntry(zr, "xl/sharedStrings.xml"); ss != nil {
+		text, err = ooxmlText(ss, "t", "si")
+	}
+	return text, sheets, err
+}
+
+var pptxSlideExpr = regexp.MustCompile(`^ppt/slides/slide(\d+)\.xml$`)
+
+func pptxText(data []byte) (string, int, error) {
+	zr, err := openZip(data)
+	if err != nil {
+		return "", 0, err
+	}
+
+	type slide struct {
+		n int
+		f *zip.File
+	}
+	var slides []slide
+	for _, f := range zr.File {
+		if m := pptxSlideExpr.FindStringSubmatch(f.Name); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			slides = append(slides, slide{n, f})
+		}
+	}
+	sort.Slice(slides, func(i, j int) bool { return slides[i].n < slides[j].n })
+
+	var b strings.Builder
+	for _, s := range slides {
+		if b.Len() > attachmentTextLimit {
+			break
+		}
+		text, err := ooxmlText(s.f, "t", "p")
+		if err != nil {
+			return b.String(), len(slides), err
+		}
+		fmt.Fprintf(&b, "Slide %d\n%s\n", s.n, text)
+	}
+	return b.String(), len(slides), nil
+}
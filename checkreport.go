@@ -0,0 +1,434 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Check report formats
+const (
+	ReportFormatText  = "text"
+	ReportFormatSARIF = "sarif"
+	ReportFormatJUnit = "junit"
+)
+
+// CheckReportResult is returned by ExportCheckReport
+type CheckReportResult struct {
+	Path     string `json:"path"`
+	Format   string `json:"format"`
+	Files    int    `json:"files"`
+	Errors   int    `json:"errors"`
+	Warnings int    `json:"warnings"`
+	Infos    int    `json:"infos"`
+}
+
+// checkRun is a set of findings ready to be written out
+type checkRun struct {
+	// base is what report paths are relative to: the repository root when
+	// the project is under git, so code scanning can place them
+	base        string
+	files       []string
+	diagnostics []Diagnostic
+}
+
+// LintProject lints every document of the project, updating the problems
+// panel, and returns the findings
+func (a *App) LintProject(projectPath string) ([]Diagnostic, error) {
+	docs, err := globFiles(projectPath, "**/*.adoc")
+	if err != nil {
+		return nil, err
+	}
+	found := []Diagnostic{}
+	for _, path := range docs {
+		diagnostics, err := a.LintFile(path)
+		if err != nil {
+			return nil, newFileError("read", path, err)
+		}
+		found = append(found, diagnostics...)
+	}
+	return found, nil
+}
+
+// ExportCheckReport lints the project and writes the results, along with
+// whatever other sources reported for it, as SARIF (for GitHub code
+// scanning), JUnit XML (for CI dashboards) or plain text
+func (a *App) ExportCheckReport(projectPath string, format string, outputPath string) (*CheckReportResult, error) {
+	if outputPath == "" {
+		return nil, fmt.Errorf("choose where to write the report")
+	}
+	run, err := a.collectChecks(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := run.writeFile(outputPath, format); err != nil {
+		return nil, err
+	}
+	result := run.summary()
+	result.Path, result.Format = outputPath, format
+	return result, nil
+}
+
+// collectChecks lints the project and gathers every stored finding in it
+func (a *App) collectChecks(projectPath string) (*checkRun, error) {
+	run := &checkRun{base: projectPath}
+	if _, wt, err := openRepo(projectPath); err == nil && wt != nil {
+		run.base = wt.Filesystem.Root()
+	}
+	docs, err := globFiles(projectPath, "**/*.adoc")
+	if err != nil {
+		return nil, err
+	}
+	run.files = docs
+	if _, err := a.LintProject(projectPath); err != nil {
+		return nil, err
+	}
+	run.diagnostics = a.GetDiagnostics(projectPath, "")
+	return run, nil
+}
+
+func (r *checkRun) summary() *CheckReportResult {
+	s := &CheckReportResult{Files: len(r.files)}
+	for _, d := range r.diagnostics {
+		switch d.Severity {
+		case SeverityError:
+			s.Errors++
+		case SeverityWarn:
+			s.Warnings++
+		default:
+			s.Infos++
+		}
+	}
+	return s
+}
+
+// relPath is a finding's path as written in reports
+func (r *checkRun) relPath(path string) string {
+	rel, err := filepath.Rel(r.base, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func (r *checkRun) write(w io.Writer, format string) error {
+	switch format {
+	case ReportFormatSARIF:
+		return r.writeSARIF(w)
+	case ReportFormatJUnit:
+		return r.writeJUnit(w)
+	case ReportFormatText, "":
+		return r.writeText(w)
+	}
+	return fmt.Errorf("unknown report format %q; use sarif, junit or text", format)
+}
+
+func (r *checkRun) writeFile(path, format string) error {
+	f, err := os.Create(longPath(path))
+	if err != nil {
+		return newFileError("write", path, err)
+	}
+	if err := r.write(f, format); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return newFileError("write", path, err)
+	}
+	return nil
+}
+
+func (r *checkRun) writeText(w io.Writer) error {
+	for _, d := range r.diagnostics {
+		if _, err := fmt.Fprintf(w, "%s:%d:%d: %s: %s [%s/%s]\n", r.relPath(d.Path), d.Line, d.Column, d.Severity, d.Message, d.Source, d.Rule); err != nil {
+			return err
+		}
+	}
+	s := r.summary()
+	_, err := fmt.Fprintf(w, "%d files checked: %d errors, %d warnings, %d notes\n", s.Files, s.Errors, s.Warnings, s.Infos)
+	return err
+}
+
+// SARIF 2.1.0, trimmed to what code scanning reads
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           *sarifRegion  `json:"region,omitempty"`
+}
+
+type sarifArtifact struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// sarifLevels maps severities onto SARIF result levels
+var sarifLevels = map[string]string{
+	SeverityError: "error",
+	SeverityWarn:  "warning",
+	SeverityInfo:  "note",
+}
+
+// sarifRuleID names rules by source, so rules of different sources can't
+// clash
+func sarifRuleID(d Diagnostic) string {
+	if d.Rule == "" {
+		return d.Source
+	}
+	return d.Source + "/" + d.Rule
+}
+
+func (r *checkRun) writeSARIF(w io.Writer) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "ndxCraft",
+			InformationURI: "https://github.com/ndx-video/ndxCraft",
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+	seen := map[string]bool{}
+	for _, rule := range lintRules {
+		id := DiagnosticSourceLint + "/" + rule.ID
+		seen[id] = true
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: rule.ID}})
+	}
+	for _, d := range r.diagnostics {
+		id := sarifRuleID(d)
+		if !seen[id] {
+			seen[id] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: id}})
+		}
+		level, ok := sarifLevels[d.Severity]
+		if !ok {
+			level = "note"
+		}
+		loc := sarifPhysicalLocation{ArtifactLocation: sarifArtifact{URI: r.relPath(d.Path), URIBaseID: "%SRCROOT%"}}
+		if d.Line > 0 {
+			loc.Region = &sarifRegion{StartLine: d.Line, StartColumn: d.Column}
+		}
+		result := sarifResult{
+			RuleID:    id,
+			Level:     level,
+			Message:   sarifMessage{Text: d.Message},
+			Locations: []sarifLocation{{PhysicalLocation: loc}},
+		}
+		if d.ID != "" {
+			result.PartialFingerprints = map[string]string{"ndxcraft/v1": d.ID}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{run},
+	})
+}
+
+// JUnit XML as read by Jenkins, GitLab and most CI dashboards
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes a suite per source with a test case per file. A file
+// fails on errors and warnings; notes are kept as output.
+func (r *checkRun) writeJUnit(w io.Writer) error {
+	bySource := map[string]map[string][]Diagnostic{DiagnosticSourceLint: {}}
+	for _, path := range r.files {
+		bySource[DiagnosticSourceLint][path] = nil
+	}
+	for _, d := range r.diagnostics {
+		if bySource[d.Source] == nil {
+			bySource[d.Source] = map[string][]Diagnostic{}
+		}
+		bySource[d.Source][d.Path] = append(bySource[d.Source][d.Path], d)
+	}
+	sources := make([]string, 0, len(bySource))
+	for source := range bySource {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	report := junitSuites{Name: "ndxCraft"}
+	for _, source := range sources {
+		suite := junitSuite{Name: source}
+		paths := make([]string, 0, len(bySource[source]))
+		for path := range bySource[source] {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			rel := r.relPath(path)
+			tc := junitCase{Name: rel, ClassName: source}
+			var failing, notes []string
+			for _, d := range bySource[source][path] {
+				line := fmt.Sprintf("%s:%d:%d: %s: %s [%s]", rel, d.Line, d.Column, d.Severity, d.Message, sarifRuleID(d))
+				if d.Severity == SeverityError || d.Severity == SeverityWarn {
+					failing = append(failing, line)
+				} else {
+					notes = append(notes, line)
+				}
+			}
+			if len(failing) > 0 {
+				tc.Failure = &junitFailure{
+					Message: fmt.Sprintf("%d problems", len(failing)),
+					Type:    source,
+					Text:    strings.Join(append(failing, notes...), "\n"),
+				}
+				suite.Failures++
+			} else if len(notes) > 0 {
+				tc.SystemOut = strings.Join(notes, "\n")
+			}
+			suite.Cases = append(suite.Cases, tc)
+		}
+		suite.Tests = len(suite.Cases)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Suites = append(report.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// runCheckCommand handles "ndxcraft check [flags] [project]", which lints
+// a project without opening a window, for CI. It reports whether args
+// asked for it, and the exit code: 1 when findings at or above -fail-on
+// were reported, 2 when the check could not run.
+func runCheckCommand(args []string) (int, bool) {
+	if len(args) == 0 || args[0] != "check" {
+		return 0, false
+	}
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	format := flags.String("format", ReportFormatText, "report format: text, sarif or junit")
+	output := flags.String("output", "", "write the report to this file instead of stdout")
+	failOn := flags.String("fail-on", SeverityError, "lowest severity that fails the check: error, warn, info or never")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2, true
+	}
+	minRank, ok := severityRank[*failOn]
+	if !ok && *failOn != "never" {
+		fmt.Fprintf(os.Stderr, "ndxcraft check: unknown -fail-on %q\n", *failOn)
+		return 2, true
+	}
+	root := "."
+	if flags.NArg() > 0 {
+		root = flags.Arg(0)
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ndxcraft check:", err)
+		return 2, true
+	}
+
+	app := NewApp()
+	app.projectRoot = root
+	run, err := app.collectChecks(root)
+	if err == nil {
+		if *output == "" {
+			err = run.write(os.Stdout, *format)
+		} else {
+			err = run.writeFile(*output, *format)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ndxcraft check:", err)
+		return 2, true
+	}
+
+	if !ok {
+		return 0, true
+	}
+	for _, d := range run.diagnostics {
+		if max(severityRank[d.Severity], 1) >= minRank {
+			return 1, true
+		}
+	}
+	return 0, true
+}
@@ -0,0 +1,380 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"github.com/zalando/go-keyring"
+)
+
+// EventClipFiled is emitted with the note path after a clip is saved
+const EventClipFiled = "clipper:filed"
+
+// EventClipperFailed is emitted with the error message when the clipper
+// can't start with the app
+const EventClipperFailed = "clipper:failed"
+
+const (
+	// clipperTokenSecret is the keychain secret holding the bearer token
+	// browser extensions pair with
+	clipperTokenSecret = "web-clipper-token"
+	// defaultClipperPort is used when no port is given or preferred
+	defaultClipperPort = 38917
+	// maxClipSize bounds a clip request, screenshot included
+	maxClipSize = 20 << 20
+)
+
+// WebClipperInfo describes the running clipper endpoint for pairing
+type WebClipperInfo struct {
+	Running bool   `json:"running"`
+	URL     string `json:"url,omitempty"`
+	// Token is shown so it can be pasted into the extension; it only grants
+	// filing clips into the inbox
+	Token string `json:"token,omitempty"`
+}
+
+// Clip is what a browser extension posts to /clip
+type Clip struct {
+	URL       string `json:"url"`
+	Title     string `json:"title"`
+	Selection string `json:"selection"`
+	// Screenshot is a base64 PNG, JPEG or WebP, optionally as a data URL
+	Screenshot string `json:"screenshot"`
+	Note       string `json:"note"`
+}
+
+var (
+	clipperMu     sync.Mutex
+	clipperServer *http.Server
+	clipperURL    string
+)
+
+// StartWebClipper listens on 127.0.0.1 for clips from a browser extension.
+// Port 0 uses the "webClipperPort" preference or the default. Set the
+// "webClipper" preference to start it with the app.
+func (a *App) StartWebClipper(port int) (*WebClipperInfo, error) {
+	token, err := clipperToken()
+	if err != nil {
+		return nil, err
+	}
+	if port == 0 {
+		port = defaultClipperPort
+		if p, _ := a.GetPreference("webClipperPort"); p != nil {
+			if v, ok := p.(float64); ok && v > 0 {
+				port = int(v)
+			}
+		}
+	}
+
+	clipperMu.Lock()
+	defer clipperMu.Unlock()
+	if clipperServer != nil {
+		return &WebClipperInfo{Running: true, URL: clipperURL, Token: token}, nil
+	}
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start web clipper: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ping", a.clipperHandler(token, a.handlePing))
+	mux.HandleFunc("/clip", a.clipperHandler(token, a.handleClip))
+	clipperServer = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	clipperURL = "http://" + ln.Addr().String()
+	go clipperServer.Serve(ln)
+	return &WebClipperInfo{Running: true, URL: clipperURL, Token: token}, nil
+}
+
+// StopWebClipper shuts the clipper endpoint down
+func (a *App) StopWebClipper() error {
+	clipperMu.Lock()
+	defer clipperMu.Unlock()
+	if clipperServer == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := clipperServer.Shutdown(ctx)
+	clipperServer = nil
+	clipperURL = ""
+	return err
+}
+
+// GetWebClipperInfo reports whether the clipper runs, with its address and
+// pairing token
+func (a *App) GetWebClipperInfo() (*WebClipperInfo, error) {
+	clipperMu.Lock()
+	defer clipperMu.Unlock()
+	if clipperServer == nil {
+		return &WebClipperInfo{}, nil
+	}
+	token, err := clipperToken()
+	if err != nil {
+		return nil, err
+	}
+	return &WebClipperInfo{Running: true, URL: clipperURL, Token: token}, nil
+}
+
+// ResetWebClipperToken replaces the pairing token, cutting off extensions
+// paired with the old one. A running clipper is restarted to pick it up.
+func (a *App) ResetWebClipperToken() (*WebClipperInfo, error) {
+	if err := a.DeleteSecret(clipperTokenSecret); err != nil {
+		return nil, err
+	}
+	info, err := a.GetWebClipperInfo()
+	if err != nil || !info.Running {
+		return info, err
+	}
+	if err := a.StopWebClipper(); err != nil {
+		return nil, err
+	}
+	return a.StartWebClipper(0)
+}
+
+// startWebClipper starts the clipper on launch when the preference is set
+func (a *App) startWebClipper() {
+	if enabled, _ := a.GetPreference("webClipper"); enabled == true {
+		if _, err := a.StartWebClipper(0); err != nil && a.ctx != nil {
+			runtime.EventsEmit(a.ctx, EventClipperFailed, err.Error())
+		}
+	}
+}
+
+// clipperToken returns the pairing token, creating it on first use
+func clipperToken() (string, error) {
+	token, err := keyring.Get(keyringService, clipperTokenSecret)
+	if err == nil {
+		return token, nil
+	}
+	if err != keyring.ErrNotFound {
+		return "", err
+	}
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token = hex.EncodeToString(b)
+	return token, keyring.Set(keyringService, clipperTokenSecret, token)
+}
+
+// clipperHandler answers CORS preflights and checks the bearer token
+func (a *App) clipperHandler(token string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			writeClipperJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid token"})
+			return
+		}
+		next(w, r)
+	}
+}
+
+// handlePing lets an extension check its pairing and the target project
+func (a *App) handlePing(w http.ResponseWriter, r *http.Request) {
+	writeClipperJSON(w, http.StatusOK, map[string]string{
+		"app":     "ndxCraft",
+		"project": filepath.Base(a.currentProjectRoot()),
+	})
+}
+
+// handleClip files a posted clip into the inbox
+func (a *App) handleClip(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeClipperJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "use POST"})
+		return
+	}
+	var clip Clip
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxClipSize)).Decode(&clip); err != nil {
+		writeClipperJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid clip: " + err.Error()})
+		return
+	}
+	path, err := a.fileClip(clip)
+	if err != nil {
+		writeClipperJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+		return
+	}
+	writeClipperJSON(w, http.StatusCreated, map[string]string{"path": path})
+}
+
+func writeClipperJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// noteName names an inbox note after its date and title, e.g.
+// "2026-03-01-release-checklist", slugged like any other file name
+func (a *App) noteName(title string, date time.Time) string {
+	opts := a.slugOptions()
+	opts.Convention = SlugKebab
+	if opts.MaxLength <= 0 || opts.MaxLength > 60 {
+		opts.MaxLength = 60
+	}
+	slug := Slugify(title, opts)
+	if slug == "" {
+		slug = "note"
+	}
+	return date.Format("2006-01-02") + "-" + slug
+}
+
+// fileClip writes a clip as an AsciiDoc note into the "clipper_inbox"
+// project setting folder, with its screenshot next to it
+func (a *App) fileClip(clip Clip) (string, error) {
+	root := a.currentProjectRoot()
+	if root == "" {
+		return "", fmt.Errorf("no project open")
+	}
+	if clip.URL == "" && clip.Selection == "" && clip.Screenshot == "" {
+		return "", fmt.Errorf("clip is empty")
+	}
+	var screenshot []byte
+	var screenshotExt string
+	if clip.Screenshot != "" {
+		var err error
+		if screenshot, screenshotExt, err = decodeScreenshot(clip.Screenshot); err != nil {
+			return "", err
+		}
+	}
+
+	title := strings.TrimSpace(clip.Title)
+	if title == "" {
+		title = clip.URL
+	}
+	if title == "" {
+		title = "Clip"
+	}
+	now := time.Now()
+	name := a.noteName(title, now)
+
+	dir, err := a.projectFolder(root, "clipper_inbox", "inbox")
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, name+".adoc")
+	if exists(path) {
+		path = copyName(path)
+		name = strings.TrimSuffix(filepath.Base(path), ".adoc")
+	}
+
+	op := a.beginOperation("Clip " + title)
+	defer a.commitOperation(op)
+
+	var imageName string
+	if screenshot != nil {
+		imageName = name + screenshotExt
+		imagePath := filepath.Join(dir, imageName)
+		if err := writeFileAtomic(imagePath, screenshot, 0644); err != nil {
+			return "", newFileError("save", imagePath, err)
+		}
+		op.created(imagePath)
+	}
+	if err := writeFileAtomic(path, []byte(clipNote(clip, title, imageName, now)), 0644); err != nil {
+		return "", newFileError("save", path, err)
+	}
+	op.created(path)
+
+	a.emitTreeChanged()
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, EventClipFiled, path)
+	}
+	return path, nil
+}
+
+// clipNote renders a clip as AsciiDoc. Everything in it comes from a web
+// page, so text is escaped and only http(s) URLs are linked.
+func clipNote(clip Clip, title, imageName string, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "= %s\n", adocInline(title))
+	link := clipLink(clip.URL)
+	if link != "" {
+		fmt.Fprintf(&b, ":clipped-from: %s\n", link)
+	}
+	fmt.Fprintf(&b, ":clipped-at: %s\n\n", now.Format(time.RFC3339))
+	if link != "" {
+		fmt.Fprintf(&b, "Source: %s[]\n\n", link)
+	}
+	if note := adocParagraphs(clip.Note); note != "" {
+		b.WriteString(note + "\n\n")
+	}
+	if selection := adocParagraphs(clip.Selection); selection != "" {
+		fmt.Fprintf(&b, "[quote]\n____\n%s\n____\n\n", selection)
+	}
+	if imageName != "" {
+		fmt.Fprintf(&b, "image::%s[Screenshot]\n", imageName)
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// clipLink returns a clip's URL when it is a plain http(s) link that can
+// go into a link macro, and "" otherwise
+func clipLink(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ""
+	}
+	// Escaping the characters AsciiDoc gives meaning to keeps the macro
+	// intact without changing where the link goes
+	return strings.NewReplacer("[", "%5B", "]", "%5D", "{", "%7B", "}", "%7D", " ", "%20", "+", "%2B").Replace(u.String())
+}
+
+var clipParagraphExpr = regexp.MustCompile(`\n\s*\n`)
+
+// adocParagraphs escapes text paragraph by paragraph with adocInline
+func adocParagraphs(s string) string {
+	var paragraphs []string
+	for _, p := range clipParagraphExpr.Split(strings.TrimSpace(s), -1) {
+		if p = adocInline(p); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// decodeScreenshot accepts base64 image data with or without a data: URL
+// prefix and returns it with a file extension
+func decodeScreenshot(s string) ([]byte, string, error) {
+	if strings.HasPrefix(s, "data:") {
+		if _, data, ok := strings.Cut(s, ","); ok {
+			s = data
+		}
+	}
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, "", errors.New("screenshot is not valid base64")
+	}
+	switch http.DetectContentType(data) {
+	case "image/png":
+		return data, ".png", nil
+	case "image/jpeg":
+		return data, ".jpg", nil
+	case "image/webp":
+		return data, ".webp", nil
+	}
+	return nil, "", fmt.Errorf("screenshot must be a PNG, JPEG or WebP image")
+}
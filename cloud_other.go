@@ -0,0 +1,40 @@
+//go:build !windows
+
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	goruntime "runtime"
+	"syscall"
+)
+
+// isCloudPlaceholder reports whether reading info's file would first have
+// to download it. Only evicted iCloud Drive files (see icloudStub) are
+// detected outside Windows; those don't exist under their own name.
+func isCloudPlaceholder(path string, info os.FileInfo) bool {
+	return false
+}
+
+// requestHydration asks the provider to download path
+func requestHydration(path string) error {
+	if goruntime.GOOS != "darwin" {
+		return nil
+	}
+	return exec.Command("brctl", "download", path).Run()
+}
+
+// isCloudFileError reports whether err looks like a disconnected network
+// or FUSE-backed sync folder
+func isCloudFileError(err error) bool {
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return false
+	}
+	switch errno {
+	case syscall.ENOTCONN, syscall.ETIMEDOUT, syscall.EHOSTDOWN, syscall.EIO:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// Attributes the Cloud Files API sets on OneDrive (and other sync
+// provider) placeholders
+const (
+	fileAttributeOffline            = 0x00001000
+	fileAttributeRecallOnOpen       = 0x00040000
+	fileAttributeRecallOnDataAccess = 0x00400000
+)
+
+// isCloudPlaceholder reports whether reading info's file would first have
+// to download it
+func isCloudPlaceholder(path string, info os.FileInfo) bool {
+	data, ok := info.Sys().(*syscall.Win32FileAttributeData)
+	if !ok {
+		return false
+	}
+	return data.FileAttributes&(fileAttributeOffline|fileAttributeRecallOnOpen|fileAttributeRecallOnDataAccess) != 0
+}
+
+// requestHydration asks the provider to download path. On Windows reading
+// the file is what triggers it, which hydrateFile does anyway.
+func requestHydration(path string) error {
+	return nil
+}
+
+// isCloudFileError reports whether err comes from the sync provider, i.e.
+// one of the ERROR_CLOUD_FILE_* codes
+func isCloudFileError(err error) bool {
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return false
+	}
+	return (errno >= 362 && errno <= 404) || errno == 426
+}
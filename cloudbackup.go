@@ -0,0 +1,365 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Cloud backup settings and their defaults
+const (
+	defaultCloudBackupKeep = 10
+	cloudBackupLastRunKey  = "cloud_backup_last"
+	cloudBackupExt         = ".ndxbak"
+	// Keychain secrets; for WebDAV the keys are the username and password
+	cloudBackupAccessKeySecret  = "cloud-backup-access-key"
+	cloudBackupSecretKeySecret  = "cloud-backup-secret-key"
+	cloudBackupPassphraseSecret = "cloud-backup-passphrase"
+)
+
+// cloudBackupMagic starts every encrypted backup, followed by the scrypt
+// salt, the AES-GCM nonce and the sealed zip
+var cloudBackupMagic = []byte("NDXBAK1\n")
+
+// CloudBackup is a backup stored with the provider
+type CloudBackup struct {
+	Name    string `json:"name"`
+	Size    int64  `json:"size"`
+	Created string `json:"created"`
+}
+
+// runCloudBackups uploads a backup on the schedule set by the
+// "cloud_backup_interval_hours" preference until ctx is done
+func (a *App) runCloudBackups(ctx context.Context) {
+	ticker := time.NewTicker(snapshotCheckEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if a.cloudBackupDue() {
+				_, _ = a.RunCloudBackup()
+			}
+		}
+	}
+}
+
+func (a *App) cloudBackupDue() bool {
+	if db == nil || a.prefString("cloud_backup_provider") == "" {
+		return false
+	}
+	hours, ok := a.prefFloat("cloud_backup_interval_hours")
+	if !ok || hours <= 0 {
+		return false
+	}
+	last, _ := db.GetAppState(cloudBackupLastRunKey)
+	t, err := time.Parse(time.RFC3339, last)
+	return err != nil || time.Since(t) >= time.Duration(hours*float64(time.Hour))
+}
+
+// RunCloudBackup uploads settings.db (which holds the shadow drafts) and
+// the snapshot drafts folder to the provider set in the
+// "cloud_backup_provider" preference (s3, b2 or webdav) at
+// "cloud_backup_url". The archive is encrypted with the passphrase kept
+// in the "cloud-backup-passphrase" keychain secret. Only the newest
+// "cloud_backup_keep" backups are kept.
+func (a *App) RunCloudBackup() (*CloudBackup, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	store, err := a.cloudStore()
+	if err != nil {
+		return nil, err
+	}
+	passphrase, err := getSecret(cloudBackupPassphraseSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := a.cloudBackupArchive()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := sealBackup(archive, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	backup := &CloudBackup{
+		Name:    "ndxcraft-" + now.Format("20060102T150405Z") + cloudBackupExt,
+		Size:    int64(len(sealed)),
+		Created: now.Format(time.RFC3339),
+	}
+	ctx := context.Background()
+	if err := store.Put(ctx, backup.Name, sealed); err != nil {
+		return nil, fmt.Errorf("backup upload failed: %w", err)
+	}
+	_ = db.SetAppState(cloudBackupLastRunKey, now.Format(time.RFC3339))
+
+	keep := defaultCloudBackupKeep
+	if v, ok := a.prefFloat("cloud_backup_keep"); ok && v > 0 {
+		keep = int(v)
+	}
+	if backups, err := listCloudBackups(ctx, store); err == nil {
+		for _, old := range backups[min(keep, len(backups)):] {
+			_ = store.Delete(ctx, old.Name)
+		}
+	}
+	return backup, nil
+}
+
+// ListCloudBackups lists the backups stored with the provider, newest first
+func (a *App) ListCloudBackups() ([]CloudBackup, error) {
+	store, err := a.cloudStore()
+	if err != nil {
+		return nil, err
+	}
+	return listCloudBackups(context.Background(), store)
+}
+
+// RestoreFromCloudBackup downloads and decrypts a backup, puts its drafts
+// back into the snapshot folder and replaces settings.db with its copy
+func (a *App) RestoreFromCloudBackup(name string) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	if name != filepath.Base(name) || !strings.HasSuffix(name, cloudBackupExt) {
+		return fmt.Errorf("invalid backup name %q", name)
+	}
+	store, err := a.cloudStore()
+	if err != nil {
+		return err
+	}
+	passphrase, err := getSecret(cloudBackupPassphraseSecret)
+	if err != nil {
+		return err
+	}
+	sealed, err := store.Get(context.Background(), name)
+	if err != nil {
+		return fmt.Errorf("backup download failed: %w", err)
+	}
+	archive, err := openBackup(sealed, passphrase)
+	if err != nil {
+		return err
+	}
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return fmt.Errorf("backup archive is damaged: %w", err)
+	}
+
+	dbCopy := db.path + ".restore"
+	defer os.Remove(dbCopy)
+	drafts := a.snapshotFolder()
+	for _, f := range zr.File {
+		var target string
+		switch {
+		case f.Name == "settings.db":
+			target = dbCopy
+		case strings.HasPrefix(f.Name, "drafts/"):
+			target = filepath.Join(drafts, filepath.FromSlash(strings.TrimPrefix(f.Name, "drafts/")))
+			if !isWithin(target, drafts) {
+				continue
+			}
+		default:
+			continue
+		}
+		if err := restoreZipEntry(f, target); err != nil {
+			return err
+		}
+	}
+	if !exists(dbCopy) {
+		return fmt.Errorf("backup %s has no settings.db", name)
+	}
+	return db.RestoreFrom(dbCopy)
+}
+
+// cloudStore returns the store selected by the "cloud_backup_provider"
+// preference
+func (a *App) cloudStore() (cloudStore, error) {
+	provider := a.prefString("cloud_backup_provider")
+	rawURL := a.prefString("cloud_backup_url")
+	if provider == "" || rawURL == "" {
+		return nil, fmt.Errorf("set cloud_backup_provider and cloud_backup_url to use cloud backups")
+	}
+	accessKey, err := getSecret(cloudBackupAccessKeySecret)
+	if err != nil {
+		return nil, err
+	}
+	secretKey, err := getSecret(cloudBackupSecretKeySecret)
+	if err != nil {
+		return nil, err
+	}
+	switch provider {
+	case "s3", "b2":
+		return newS3Store(rawURL, a.prefString("cloud_backup_region"), accessKey, secretKey)
+	case "webdav":
+		return newWebDAVStore(rawURL, accessKey, secretKey)
+	}
+	return nil, fmt.Errorf("unknown cloud backup provider %q", provider)
+}
+
+func listCloudBackups(ctx context.Context, store cloudStore) ([]CloudBackup, error) {
+	objects, err := store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	backups := []CloudBackup{}
+	for _, o := range objects {
+		if !strings.HasSuffix(o.Name, cloudBackupExt) {
+			continue
+		}
+		created := ""
+		if !o.Modified.IsZero() {
+			created = o.Modified.UTC().Format(time.RFC3339)
+		}
+		backups = append(backups, CloudBackup{Name: o.Name, Size: o.Size, Created: created})
+	}
+	// Names carry a UTC timestamp, so they sort by age
+	sort.Slice(backups, func(i, j int) bool { return backups[i].Name > backups[j].Name })
+	return backups, nil
+}
+
+// cloudBackupArchive zips a consistent copy of settings.db and the
+// snapshot drafts folder
+func (a *App) cloudBackupArchive() ([]byte, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(db.path), "settings-*.db")
+	if err != nil {
+		return nil, err
+	}
+	tmp.Close()
+	os.Remove(tmp.Name())
+	defer os.Remove(tmp.Name())
+	if err := db.SnapshotTo(tmp.Name()); err != nil {
+		return nil, fmt.Errorf("failed to copy settings.db: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	if err := zipFileAs(zw, tmp.Name(), "settings.db"); err != nil {
+		return nil, err
+	}
+
+	drafts := a.snapshotFolder()
+	files, _ := globFiles(drafts, "**/*")
+	for _, path := range files {
+		rel, err := filepath.Rel(drafts, path)
+		if err != nil {
+			continue
+		}
+		if err := zipFileAs(zw, path, "drafts/"+filepath.ToSlash(rel)); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// zipFileAs stores the file at path under name
+func zipFileAs(zw *zip.Writer, path, name string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// restoreZipEntry writes one archive entry to target, replacing it
+func restoreZipEntry(f *zip.File, target string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(target, data, 0644)
+}
+
+// backupKey derives the AES-256 key from the passphrase
+func backupKey(passphrase string, salt []byte) ([]byte, error) {
+	return scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+}
+
+// sealBackup encrypts data with AES-256-GCM under a passphrase
+func sealBackup(data []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key, err := backupKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append([]byte{}, cloudBackupMagic...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, data, cloudBackupMagic), nil
+}
+
+// openBackup reverses sealBackup
+func openBackup(sealed []byte, passphrase string) ([]byte, error) {
+	if !bytes.HasPrefix(sealed, cloudBackupMagic) {
+		return nil, fmt.Errorf("not an ndxCraft backup")
+	}
+	rest := sealed[len(cloudBackupMagic):]
+	if len(rest) < 16+12 {
+		return nil, fmt.Errorf("backup is truncated")
+	}
+	key, err := backupKey(passphrase, rest[:16])
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := rest[16 : 16+gcm.NonceSize()]
+	data, err := gcm.Open(nil, nonce, rest[16+gcm.NonceSize():], cloudBackupMagic)
+	if err != nil {
+		return nil, fmt.Errorf("can't decrypt backup: wrong passphrase or damaged file")
+	}
+	return data, nil
+}
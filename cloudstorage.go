@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// cloudObject is a stored backup file
+type cloudObject struct {
+	Name     string
+	Size     int64
+	Modified time.Time
+}
+
+// cloudStore is implemented by every backup destination
+type cloudStore interface {
+	Put(ctx context.Context, name string, data []byte) error
+	Get(ctx context.Context, name string) ([]byte, error)
+	// List returns the objects under the store's prefix
+	List(ctx context.Context) ([]cloudObject, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// cloudHTTPClient is shared by the stores; uploads of a large settings.db
+// over a slow link need a generous timeout
+var cloudHTTPClient = &http.Client{Timeout: 10 * time.Minute}
+
+// S3
+
+var s3RegionExpr = regexp.MustCompile(`^s3[.-]([a-z0-9-]+)\.`)
+
+// s3Store talks to S3 and S3-compatible services such as Backblaze B2
+// using path-style URLs: https://<endpoint>/<bucket>/<prefix>
+type s3Store struct {
+	endpoint  *url.URL
+	bucket    string
+	prefix    string
+	region    string
+	accessKey string
+	secretKey string
+}
+
+func newS3Store(rawURL, region, accessKey, secretKey string) (*s3Store, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid backup URL %q", rawURL)
+	}
+	bucket, prefix, _ := strings.Cut(strings.Trim(u.Path, "/"), "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("backup URL %q has no bucket; use https://<endpoint>/<bucket>/<prefix>", rawURL)
+	}
+	if region == "" {
+		if m := s3RegionExpr.FindStringSubmatch(u.Host); m != nil {
+			region = m[1]
+		} else {
+			region = "us-east-1"
+		}
+	}
+	if prefix != "" {
+		prefix += "/"
+	}
+	return &s3Store{
+		endpoint:  &url.URL{Scheme: u.Scheme, Host: u.Host},
+		bucket:    bucket,
+		prefix:    prefix,
+		region:    region,
+		accessKey: accessKey,
+		secretKey: secretKey,
+	}, nil
+}
+
+func (s *s3Store) Put(ctx context.Context, name string, data []byte) error {
+	_, err := s.do(ctx, http.MethodPut, s.prefix+name, nil, data)
+	return err
+}
+
+func (s *s3Store) Get(ctx context.Context, name string) ([]byte, error) {
+	return s.do(ctx, http.MethodGet, s.prefix+name, nil, nil)
+}
+
+func (s *s3Store) Delete(ctx context.Context, name string) error {
+	_, err := s.do(ctx, http.MethodDelete, s.prefix+name, nil, nil)
+	return err
+}
+
+func (s *s3Store) List(ctx context.Context) ([]cloudObject, error) {
+	var objects []cloudObject
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {s.prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		body, err := s.do(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		var result struct {
+			Contents []struct {
+				Key          string    `xml:"Key"`
+				Size         int64     `xml:"Size"`
+				LastModified time.Time `xml:"LastModified"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("unexpected bucket listing: %w", err)
+		}
+		for _, c := range result.Contents {
+			name := strings.TrimPrefix(c.Key, s.prefix)
+			if name != "" && !strings.Contains(name, "/") {
+				objects = append(objects, cloudObject{Name: name, Size: c.Size, Modified: c.LastModified})
+			}
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// do sends a request signed with AWS Signature Version 4
+func (s *s3Store) do(ctx context.Context, method, key string, query url.Values, body []byte) ([]byte, error) {
+	canonicalURI := "/" + awsURIEncode(s.bucket, false)
+	if key != "" {
+		canonicalURI += "/" + awsURIEncode(key, true)
+	}
+	canonicalQuery := awsCanonicalQuery(query)
+	target := s.endpoint.Scheme + "://" + s.endpoint.Host + canonicalURI
+	if canonicalQuery != "" {
+		target += "?" + canonicalQuery
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payload := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(payload[:])
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		method,
+		canonicalURI,
+		canonicalQuery,
+		"host:" + req.URL.Host + "\nx-amz-content-sha256:" + payloadHash + "\nx-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	hashed := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hashed[:])
+
+	key4 := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	key4 = hmacSHA256(key4, s.region)
+	key4 = hmacSHA256(key4, "s3")
+	key4 = hmacSHA256(key4, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key4, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+
+	return cloudResponse(cloudHTTPClient.Do(req))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// awsURIEncode percent-encodes everything but unreserved characters, and
+// slashes when keepSlash is set
+func awsURIEncode(s string, keepSlash bool) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && keepSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func awsCanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, awsURIEncode(k, false)+"="+awsURIEncode(query.Get(k), false))
+	}
+	return strings.Join(parts, "&")
+}
+
+// WebDAV
+
+// webdavStore keeps backups in a WebDAV collection, e.g. Nextcloud's
+// https://host/remote.php/dav/files/<user>/Backups/
+type webdavStore struct {
+	base     *url.URL
+	username string
+	password string
+}
+
+func newWebDAVStore(rawURL, username, password string) (*webdavStore, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid backup URL %q", rawURL)
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	return &webdavStore{base: u, username: username, password: password}, nil
+}
+
+func (s *webdavStore) Put(ctx context.Context, name string, data []byte) error {
+	_, err := s.do(ctx, http.MethodPut, name, nil, data)
+	return err
+}
+
+func (s *webdavStore) Get(ctx context.Context, name string) ([]byte, error) {
+	return s.do(ctx, http.MethodGet, name, nil, nil)
+}
+
+func (s *webdavStore) Delete(ctx context.Context, name string) error {
+	_, err := s.do(ctx, http.MethodDelete, name, nil, nil)
+	return err
+}
+
+func (s *webdavStore) List(ctx context.Context) ([]cloudObject, error) {
+	propfind := []byte(`<?xml version="1.0"?><d:propfind xmlns:d="DAV:"><d:prop><d:getcontentlength/><d:getlastmodified/><d:resourcetype/></d:prop></d:propfind>`)
+	body, err := s.do(ctx, "PROPFIND", "", map[string]string{"Depth": "1", "Content-Type": "application/xml"}, propfind)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Responses []struct {
+			Href string `xml:"href"`
+			Prop struct {
+				Length       int64  `xml:"getcontentlength"`
+				LastModified string `xml:"getlastmodified"`
+				ResourceType struct {
+					Collection *struct{} `xml:"collection"`
+				} `xml:"resourcetype"`
+			} `xml:"propstat>prop"`
+		} `xml:"response"`
+	}
+	if err := xml.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("unexpected WebDAV listing: %w", err)
+	}
+	var objects []cloudObject
+	for _, r := range result.Responses {
+		if r.Prop.ResourceType.Collection != nil {
+			continue
+		}
+		href, err := url.PathUnescape(r.Href)
+		if err != nil {
+			href = r.Href
+		}
+		modified, _ := http.ParseTime(r.Prop.LastModified)
+		objects = append(objects, cloudObject{Name: path.Base(href), Size: r.Prop.Length, Modified: modified})
+	}
+	return objects, nil
+}
+
+func (s *webdavStore) do(ctx context.Context, method, name string, headers map[string]string, body []byte) ([]byte, error) {
+	target := s.base
+	if name != "" {
+		target = s.base.JoinPath(name)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if s.username != "" {
+		req.SetBasicAuth(s.username, s.password)
+	}
+	return cloudResponse(cloudHTTPClient.Do(req))
+}
+
+// cloudResponse reads a response body, turning error statuses into errors
+func cloudResponse(resp *http.Response, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("backup storage refused the credentials (%s)", resp.Status)
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("not found in backup storage (%s)", resp.Status)
+	case resp.StatusCode >= 300:
+		msg := strings.TrimSpace(string(body))
+		if len(msg) > 200 {
+			msg = msg[:200]
+		}
+		return nil, fmt.Errorf("backup storage error %s: %s", resp.Status, msg)
+	}
+	return body, nil
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configSchemaJSON describes .ndxcraft/config.yaml. It is served to the UI
+// for form-based editing and drives ValidateProjectConfig.
+const configSchemaJSON = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "ndxCraft project configuration",
+  "type": "object",
+  "additionalProperties": false,
+  "properties": {
+    "attributes": {
+      "description": "Document attributes applied to every file in the project",
+      "type": "object",
+      "additionalProperties": { "type": "string" }
+    },
+    "lint": {
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "rules": {
+          "description": "Severity per rule id, or per diagnostic source such as vale",
+          "type": "object",
+          "additionalProperties": { "type": "string", "enum": ["error", "warn", "info", "ignore"] }
+        },
+        "length": {
+          "description": "Word budgets for pages and sections; 0 turns a limit off",
+          "type": "object",
+          "additionalProperties": false,
+          "properties": {
+            "maxPageWords": { "type": "integer" },
+            "maxSectionWords": { "type": "integer" },
+            "minIntroWords": { "description": "Words a section needs before its first subheading", "type": "integer" }
+          }
+        },
+        "acronyms": {
+          "description": "Acronyms that must be expanded on first use",
+          "type": "object",
+          "additionalProperties": false,
+          "properties": {
+            "scope": { "description": "Check every acronym, or only those in the glossary", "type": "string", "enum": ["all", "glossary"] },
+            "ignore": { "type": "array", "items": { "type": "string" } }
+          }
+        }
+      }
+    },
+    "exportPresets": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["name", "format"],
+        "properties": {
+          "name": { "type": "string" },
+          "format": { "type": "string", "enum": ["html", "pdf", "docbook", "epub"] },
+          "output": { "type": "string" },
+          "attributes": { "type": "object", "additionalProperties": { "type": "string" } },
+          "audience": { "description": "Internal exports keep sections and blocks with a draft or internal role", "type": "string", "enum": ["public", "internal"] },
+          "redirects": { "description": "Redirects written into the output of html presets: meta-refresh pages (html, the default), a Netlify _redirects file, all or none", "type": "string", "enum": ["html", "netlify", "all", "none"] }
+        }
+      }
+    },
+    "publishTargets": {
+      "description": "Deploy destinations; options may reference ${ENV_VAR} or ${secret:name} once the user approves the target's host",
+      "type": "array",
+      "items": {
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["name", "type"],
+        "properties": {
+          "name": { "type": "string" },
+          "type": { "type": "string" },
+          "url": { "type": "string" },
+          "options": { "type": "object", "additionalProperties": { "type": "string" } }
+        }
+      }
+    },
+    "glossary": {
+      "description": "Path of the glossary file, relative to the project root; its \"TERM:: definition\" entries expand acronyms",
+      "type": "string"
+    },
+    "styleProfile": {
+      "description": "Voice and tone the documentation should follow",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "audience": { "type": "string" },
+        "tone": { "type": "string" },
+        "forbiddenPhrases": { "type": "array", "items": { "type": "string" } },
+        "examples": { "type": "array", "items": { "type": "string" } }
+      }
+    },
+    "inclusiveLanguage": {
+      "description": "Wordlist of the inclusive-language lint rule",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "disableDefaults": { "description": "Check only the terms listed here", "type": "boolean" },
+        "terms": {
+          "type": "array",
+          "items": {
+            "type": "object",
+            "additionalProperties": false,
+            "required": ["term"],
+            "properties": {
+              "term": { "type": "string" },
+              "replacements": { "type": "array", "items": { "type": "string" } },
+              "reason": { "type": "string" }
+            }
+          }
+        },
+        "allow": { "description": "Built-in terms not to flag", "type": "array", "items": { "type": "string" } }
+      }
+    },
+    "contentSources": {
+      "description": "Folders of other git repositories to include from, as {<name>-dir}",
+      "type": "array",
+      "items": {
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["name", "url"],
+        "properties": {
+          "name": { "description": "Lowercase letters, digits and -", "type": "string" },
+          "url": { "type": "string" },
+          "ref": { "description": "Branch, tag or commit; the default branch when empty", "type": "string" },
+          "path": { "description": "Folder within the repository", "type": "string" }
+        }
+      }
+    },
+    "settings": {
+      "description": "Project settings that override the ones stored in the app",
+      "type": "object"
+    }
+  }
+}`
+
+// jsonSchema is the subset of JSON Schema the config validator understands
+type jsonSchema struct {
+	Type                 string                 `json:"type"`
+	Description          string                 `json:"description"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	AdditionalProperties json.RawMessage        `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	Required             []string               `json:"required"`
+	Enum                 []string               `json:"enum"`
+}
+
+// ConfigIssue is a single validation finding in a config file
+type ConfigIssue struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+}
+
+var (
+	configSchema      *jsonSchema
+	yamlErrorLineExpr = regexp.MustCompile(`line (\d+)`)
+)
+
+func init() {
+	configSchema = &jsonSchema{}
+	if err := json.Unmarshal([]byte(configSchemaJSON), configSchema); err != nil {
+		panic(fmt.Sprintf("invalid config schema: %v", err))
+	}
+}
+
+// GetConfigSchema returns the JSON schema of the project config file
+func (a *App) GetConfigSchema() (map[string]interface{}, error) {
+	var schema map[string]interface{}
+	err := json.Unmarshal([]byte(configSchemaJSON), &schema)
+	return schema, err
+}
+
+// ValidateProjectConfig checks a project's .ndxcraft/config.yaml against the
+// schema. A project without a config file has no issues.
+func (a *App) ValidateProjectConfig(projectPath string) ([]ConfigIssue, error) {
+	data, err := os.ReadFile(projectConfigPath(projectPath))
+	if os.IsNotExist(err) {
+		return []ConfigIssue{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return validateConfigData(data), nil
+}
+
+func validateConfigData(data []byte) []ConfigIssue {
+	issues := []ConfigIssue{}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		issue := ConfigIssue{Message: err.Error()}
+		if m := yamlErrorLineExpr.FindStringSubmatch(err.Error()); m != nil {
+			issue.Line, _ = strconv.Atoi(m[1])
+		}
+		return append(issues, issue)
+	}
+	if len(doc.Content) == 0 {
+		return issues
+	}
+
+	validateNode(doc.Content[0], configSchema, "", &issues)
+	return issues
+}
+
+func validateNode(node *yaml.Node, schema *jsonSchema, path string, issues *[]ConfigIssue) {
+	report := func(n *yaml.Node, p string, format string, args ...interface{}) {
+		*issues = append(*issues, ConfigIssue{
+			Path:    p,
+			Message: fmt.Sprintf(format, args...),
+			Line:    n.Line,
+			Column:  n.Column,
+		})
+	}
+
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	if schema.Type != "" && !yamlNodeHasType(node, schema.Type) {
+		report(node, path, "expected %s", schema.Type)
+		return
+	}
+
+	if len(schema.Enum) > 0 && node.Kind == yaml.ScalarNode {
+		found := false
+		for _, v := range schema.Enum {
+			if node.Value == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			report(node, path, "must be one of %s", strings.Join(schema.Enum, ", "))
+		}
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		seen := make(map[string]bool)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valueNode := node.Content[i], node.Content[i+1]
+			key := keyNode.Value
+			seen[key] = true
+			childPath := joinConfigPath(path, key)
+
+			if propSchema, ok := schema.Properties[key]; ok {
+				validateNode(valueNode, propSchema, childPath, issues)
+				continue
+			}
+			additional, allowed := schema.additional()
+			if !allowed {
+				report(keyNode, childPath, "unknown property %q", key)
+				continue
+			}
+			if additional != nil {
+				validateNode(valueNode, additional, childPath, issues)
+			}
+		}
+
+		required := append([]string(nil), schema.Required...)
+		sort.Strings(required)
+		for _, key := range required {
+			if !seen[key] {
+				report(node, path, "missing required property %q", key)
+			}
+		}
+	case yaml.SequenceNode:
+		if schema.Items != nil {
+			for i, item := range node.Content {
+				validateNode(item, schema.Items, fmt.Sprintf("%s[%d]", path, i), issues)
+			}
+		}
+	}
+}
+
+// additional reports the schema for unlisted properties and whether they are
+// allowed at all
+func (s *jsonSchema) additional() (*jsonSchema, bool) {
+	raw := strings.TrimSpace(string(s.AdditionalProperties))
+	switch raw {
+	case "", "true":
+		return nil, true
+	case "false":
+		return nil, false
+	}
+	var sub jsonSchema
+	if err := json.Unmarshal(s.AdditionalProperties, &sub); err != nil {
+		return nil, true
+	}
+	return &sub, true
+}
+
+func yamlNodeHasType(node *yaml.Node, typ string) bool {
+	switch typ {
+	case "object":
+		return node.Kind == yaml.MappingNode
+	case "array":
+		return node.Kind == yaml.SequenceNode
+	case "string":
+		return node.Kind == yaml.ScalarNode && node.Tag == "!!str"
+	case "boolean":
+		return node.Kind == yaml.ScalarNode && node.Tag == "!!bool"
+	case "integer":
+		return node.Kind == yaml.ScalarNode && node.Tag == "!!int"
+	case "number":
+		return node.Kind == yaml.ScalarNode && (node.Tag == "!!int" || node.Tag == "!!float")
+	}
+	return true
+}
+
+func joinConfigPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
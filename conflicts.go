@@ -0,0 +1,90 @@
+package main
+
+import (
+	"crypto/sha256"
+	"errors"
+	"os"
+	"time"
+)
+
+// ErrSaveConflict is returned by SaveFile when the file changed on disk
+// after it was loaded
+var ErrSaveConflict = errors.New("file changed on disk since it was loaded")
+
+// fileStamp identifies the version of a file the editor is working from
+type fileStamp struct {
+	modTime time.Time
+	hash    [sha256.Size]byte
+}
+
+// SaveConflict holds both sides of an external modification
+type SaveConflict struct {
+	Path          string    `json:"path"`
+	Ours          string    `json:"ours"`
+	Theirs        string    `json:"theirs"`
+	LoadedModTime time.Time `json:"loadedModTime"`
+	DiskModTime   time.Time `json:"diskModTime"`
+}
+
+// CheckSaveConflict reports whether saving content to path would clobber
+// changes made outside the app. It returns nil when it's safe to save.
+func (a *App) CheckSaveConflict(path string, content string) (*SaveConflict, error) {
+	a.loadedMu.Lock()
+	stamp, tracked := a.loaded[path]
+	a.loadedMu.Unlock()
+	if !tracked {
+		return nil, nil
+	}
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		// Deleted underneath us; saving simply recreates it
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if info.ModTime().Equal(stamp.modTime) {
+		return nil, nil
+	}
+
+	// mtime alone is noisy (touch, sync tools), so compare contents too
+	disk, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if sha256.Sum256(disk) == stamp.hash {
+		return nil, nil
+	}
+
+	return &SaveConflict{
+		Path:          path,
+		Ours:          content,
+		Theirs:        string(disk),
+		LoadedModTime: stamp.modTime,
+		DiskModTime:   info.ModTime(),
+	}, nil
+}
+
+// rememberStamp records the on-disk version of path the editor now holds
+func (a *App) rememberStamp(path string, content []byte) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	a.loadedMu.Lock()
+	a.loaded[path] = fileStamp{modTime: info.ModTime(), hash: sha256.Sum256(content)}
+	a.loadedMu.Unlock()
+}
+
+// rebaseStamps moves stamps along with a renamed file or folder
+func (a *App) rebaseStamps(oldPath, newPath string) {
+	a.loadedMu.Lock()
+	defer a.loadedMu.Unlock()
+	for p, stamp := range a.loaded {
+		if np, ok := rebasePath(p, oldPath, newPath); ok {
+			delete(a.loaded, p)
+			a.loaded[np] = stamp
+		}
+	}
+}
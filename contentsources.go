@@ -0,0 +1,251 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// ContentSource is a folder of another git repository whose files can be
+// included, e.g. shared partials maintained by another team. Its local
+// checkout is available as the {<name>-dir} attribute:
+//
+//	include::{shared-dir}/partials/support.adoc[]
+type ContentSource struct {
+	Name string `yaml:"name" json:"name"`
+	URL  string `yaml:"url" json:"url"`
+	// Ref is a branch, tag or commit; the remote's default branch when empty
+	Ref string `yaml:"ref" json:"ref"`
+	// Path is the folder within the repository; the root when empty
+	Path string `yaml:"path" json:"path"`
+}
+
+// ContentSourceStatus is a content source and its local checkout
+type ContentSourceStatus struct {
+	ContentSource
+	Attribute string `json:"attribute"`
+	// Dir is where the source's Path is checked out; empty until synced
+	Dir    string `json:"dir,omitempty"`
+	Commit string `json:"commit,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+var (
+	includeAttrExpr       = regexp.MustCompile(`\{([\w-]+)\}`)
+	contentSourceNameExpr = regexp.MustCompile(`^[a-z0-9-]+$`)
+)
+
+// GetContentSources lists the project's content sources and what is
+// cached of them, without fetching
+func (a *App) GetContentSources(projectPath string) ([]ContentSourceStatus, error) {
+	sources, err := projectContentSources(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	list := []ContentSourceStatus{}
+	for _, src := range sources {
+		status := ContentSourceStatus{ContentSource: src, Attribute: contentSourceAttribute(src)}
+		if repo, err := git.PlainOpen(contentSourceCheckout(src)); err == nil {
+			if head, err := repo.Head(); err == nil {
+				status.Dir = contentSourceDir(src)
+				status.Commit = head.Hash().String()
+			}
+		}
+		list = append(list, status)
+	}
+	return list, nil
+}
+
+// SyncContentSources clones or updates every content source of the project
+// to its configured ref. A source that fails keeps its previous checkout
+// and reports the error in its status.
+func (a *App) SyncContentSources(projectPath string) ([]ContentSourceStatus, error) {
+	sources, err := projectContentSources(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	list := []ContentSourceStatus{}
+	for _, src := range sources {
+		status := ContentSourceStatus{ContentSource: src, Attribute: contentSourceAttribute(src)}
+		if commit, err := a.syncContentSource(src); err != nil {
+			status.Error = err.Error()
+		} else {
+			status.Dir = contentSourceDir(src)
+			status.Commit = commit
+		}
+		list = append(list, status)
+	}
+	return list, nil
+}
+
+// ResolveInclude returns the content of an include:: target as written in
+// fromPath, for the preview. Content source attributes are expanded. Sources
+// are only fetched by SyncContentSources, when the user asks, since their
+// URLs come from the committed config.
+func (a *App) ResolveInclude(fromPath string, target string) (string, error) {
+	root := a.currentProjectRoot()
+	sources, err := projectContentSources(root)
+	if err != nil {
+		return "", err
+	}
+	for _, src := range sources {
+		if strings.Contains(target, "{"+contentSourceAttribute(src)+"}") && !exists(contentSourceCheckout(src)) {
+			return "", fmt.Errorf("content source %s is not synced yet; sync the content sources first", src.Name)
+		}
+	}
+	cfg, _ := LoadProjectConfig(root)
+	path := filepath.FromSlash(expandAttributes(target, projectAttributes(root, cfg)))
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(fromPath), path)
+	}
+	content, err := os.ReadFile(longPath(path))
+	if err != nil {
+		return "", newFileError("read", path, err)
+	}
+	return string(content), nil
+}
+
+// syncContentSource brings a source's checkout to its ref and returns the
+// commit checked out
+func (a *App) syncContentSource(src ContentSource) (string, error) {
+	dir := contentSourceCheckout(src)
+	auth, err := a.gitAuth(src.URL)
+	if err != nil {
+		return "", newGitError("fetch", src.Name, err)
+	}
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+			return "", err
+		}
+		_ = os.RemoveAll(dir)
+		repo, err = git.PlainClone(dir, false, &git.CloneOptions{URL: src.URL, Auth: auth, NoCheckout: true, Tags: git.AllTags})
+		if err != nil {
+			_ = os.RemoveAll(dir)
+			return "", newGitError("clone", src.Name, err)
+		}
+	} else {
+		err = repo.Fetch(&git.FetchOptions{Auth: auth, Tags: git.AllTags, Force: true})
+		if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+			return "", newGitError("fetch", src.Name, err)
+		}
+	}
+
+	hash, err := resolveSourceRef(repo, src.Ref)
+	if err != nil {
+		return "", fmt.Errorf("content source %s: %w", src.Name, err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+	if err := wt.Checkout(&git.CheckoutOptions{Hash: hash, Force: true}); err != nil {
+		return "", err
+	}
+	return hash.String(), nil
+}
+
+// resolveSourceRef finds the commit of a branch (as fetched from origin),
+// tag or commit hash
+func resolveSourceRef(repo *git.Repository, ref string) (plumbing.Hash, error) {
+	if ref == "" {
+		head, err := repo.Reference(plumbing.NewRemoteHEADReferenceName(git.DefaultRemoteName), true)
+		if err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("the remote has no default branch; set a ref")
+		}
+		return head.Hash(), nil
+	}
+	for _, rev := range []string{git.DefaultRemoteName + "/" + ref, ref} {
+		if hash, err := repo.ResolveRevision(plumbing.Revision(rev)); err == nil {
+			return *hash, nil
+		}
+	}
+	return plumbing.ZeroHash, fmt.Errorf("ref %q not found", ref)
+}
+
+// projectContentSources reads and checks the contentSources of a project
+func projectContentSources(projectPath string) ([]ContentSource, error) {
+	cfg, err := LoadProjectConfig(projectPath)
+	if err != nil || cfg == nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	for _, src := range cfg.ContentSources {
+		if src.Name == "" || src.URL == "" {
+			return nil, fmt.Errorf("content sources need a name and a url")
+		}
+		if !contentSourceNameExpr.MatchString(src.Name) {
+			return nil, fmt.Errorf("content source name %q may only use a-z, 0-9 and -", src.Name)
+		}
+		if seen[src.Name] {
+			return nil, fmt.Errorf("content source %s is declared twice", src.Name)
+		}
+		seen[src.Name] = true
+		if !isWithin(filepath.Join("checkout", filepath.FromSlash(src.Path)), "checkout") {
+			return nil, fmt.Errorf("content source %s: path %q leaves the repository", src.Name, src.Path)
+		}
+	}
+	return cfg.ContentSources, nil
+}
+
+// contentSourceAttribute is the attribute holding a source's directory
+func contentSourceAttribute(src ContentSource) string {
+	return src.Name + "-dir"
+}
+
+// contentSourceCheckout is where a source's repository is cached, next to
+// the database. Projects using the same URL and ref share it.
+func contentSourceCheckout(src ContentSource) string {
+	sum := sha1.Sum([]byte(src.URL + "\x00" + src.Ref))
+	base := os.TempDir()
+	if db != nil {
+		base = filepath.Dir(db.path)
+	}
+	return filepath.Join(base, "sources", src.Name+"-"+hex.EncodeToString(sum[:6]))
+}
+
+// contentSourceDir is the checked-out folder the source's attribute
+// points to
+func contentSourceDir(src ContentSource) string {
+	return filepath.Join(contentSourceCheckout(src), filepath.FromSlash(src.Path))
+}
+
+// projectAttributes are the attributes every document of a project sees:
+// the content source directories and those set in the config, which may
+// be nil
+func projectAttributes(projectPath string, cfg *ProjectConfig) map[string]string {
+	attrs := map[string]string{}
+	if cfg == nil {
+		return attrs
+	}
+	sources, _ := projectContentSources(projectPath)
+	for _, src := range sources {
+		attrs[contentSourceAttribute(src)] = filepath.ToSlash(contentSourceDir(src))
+	}
+	for name, value := range cfg.Attributes {
+		attrs[name] = value
+	}
+	return attrs
+}
+
+// expandAttributes replaces the attribute references in an include or
+// image target; unknown ones are left as written
+func expandAttributes(target string, attrs map[string]string) string {
+	if !strings.Contains(target, "{") {
+		return target
+	}
+	return includeAttrExpr.ReplaceAllStringFunc(target, func(ref string) string {
+		if value, ok := attrs[ref[1:len(ref)-1]]; ok {
+			return value
+		}
+		return ref
+	})
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contradictionCandidates is how many passages are compared per topic
+const contradictionCandidates = 12
+
+// Contradiction is a pair of passages that disagree, for human review
+type Contradiction struct {
+	A           Passage `json:"a"`
+	B           Passage `json:"b"`
+	StatementA  string  `json:"statementA"`
+	StatementB  string  `json:"statementB"`
+	Explanation string  `json:"explanation"`
+}
+
+// FindContradictions looks up the passages of a project closest to topic in
+// the embeddings index and asks the AI provider which of them conflict
+func (a *App) FindContradictions(projectPath string, topic string) ([]Contradiction, error) {
+	// On network drives re-reading every file per query is too slow; use
+	// the existing index and let the user refresh it explicitly
+	if !a.degraded(projectPath) || !hasEmbeddings(projectPath) {
+		if _, err := a.IndexProjectEmbeddings(projectPath); err != nil {
+			return nil, err
+		}
+	}
+	passages, err := a.SearchPassages(projectPath, topic, contradictionCandidates)
+	if err != nil {
+		return nil, err
+	}
+	if len(passages) < 2 {
+		return []Contradiction{}, nil
+	}
+
+	var b strings.Builder
+	for i, p := range passages {
+		fmt.Fprintf(&b, "[%d] %s (%s)\n%s\n\n", i, p.Title, p.Path, p.Content)
+	}
+
+	prompt := fmt.Sprintf(`You are reviewing documentation for consistency about: %s
+
+Below are numbered passages from different documents. Find pairs that make
+conflicting statements (e.g. different default values, limits, steps or
+requirements). Ignore passages that merely cover different aspects.
+Respond ONLY with a JSON array of the form
+[{"a": 0, "b": 3, "statementA": "...", "statementB": "...", "explanation": "..."}]
+or [] if nothing conflicts.
+
+%s`, topic, b.String())
+
+	var found []struct {
+		A           int    `json:"a"`
+		B           int    `json:"b"`
+		StatementA  string `json:"statementA"`
+		StatementB  string `json:"statementB"`
+		Explanation string `json:"explanation"`
+	}
+	if err := a.generateJSON(aiRequest{Model: a.aiModel(), Prompt: prompt, Action: "findContradictions"}, &found); err != nil {
+		return nil, err
+	}
+
+	result := []Contradiction{}
+	for _, f := range found {
+		if f.A < 0 || f.B < 0 || f.A >= len(passages) || f.B >= len(passages) || f.A == f.B {
+			continue
+		}
+		result = append(result, Contradiction{
+			A:           passages[f.A],
+			B:           passages[f.B],
+			StatementA:  f.StatementA,
+			StatementB:  f.StatementB,
+			Explanation: f.Explanation,
+		})
+	}
+	return result, nil
+}
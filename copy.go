@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Overwrite policies for CopyPath
+const (
+	OverwriteError  = "error"
+	OverwriteSkip   = "skip"
+	OverwriteAlways = "overwrite"
+)
+
+// EventCopyProgress is emitted while CopyPath works through a tree
+const EventCopyProgress = "fileops:copy:progress"
+
+// copyProgressEvery limits how often progress events are emitted
+const copyProgressEvery = 25
+
+// CopyOptions controls CopyPath
+type CopyOptions struct {
+	// Overwrite is one of "error" (default), "skip" or "overwrite"
+	Overwrite string `json:"overwrite"`
+	// FollowSymlinks copies link targets instead of recreating the links
+	FollowSymlinks bool `json:"followSymlinks"`
+}
+
+// CopyProgress is the payload of EventCopyProgress
+type CopyProgress struct {
+	Src     string `json:"src"`
+	Current string `json:"current"`
+	Copied  int    `json:"copied"`
+	Skipped int    `json:"skipped"`
+	Total   int    `json:"total"`
+}
+
+// CopyPath copies a file or a whole directory tree from src to dst
+func (a *App) CopyPath(src string, dst string, opts CopyOptions) (*CopyProgress, error) {
+	if opts.Overwrite == "" {
+		opts.Overwrite = OverwriteError
+	}
+	if _, inside := rebasePath(dst, src, src); inside {
+		return nil, fmt.Errorf("cannot copy %s into itself", src)
+	}
+
+	total := 0
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			total++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Copies merged into an existing destination can't be cleanly undone,
+	// so only fresh copies are journaled
+	op := a.beginOperation("Copy " + filepath.Base(src))
+	if _, err := os.Lstat(dst); err == nil {
+		op = nil
+	}
+
+	progress := &CopyProgress{Src: src, Total: total}
+	err = a.copyTree(src, dst, opts, progress, map[string]bool{})
+	op.created(dst)
+	a.commitOperation(op)
+	if err != nil {
+		return progress, err
+	}
+
+	progress.Current = ""
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, EventCopyProgress, *progress)
+	}
+	a.emitTreeChanged()
+	return progress, nil
+}
+
+// copyTree walks src into dst. visited holds the resolved directories
+// already copied, so followed symlinks can't loop forever.
+func (a *App) copyTree(src, dst string, opts CopyOptions, progress *CopyProgress, visited map[string]bool) error {
+	// Walk the resolved path; WalkDir won't descend into a symlinked root
+	if real, err := filepath.EvalSymlinks(src); err == nil {
+		if visited[real] {
+			return nil
+		}
+		visited[real] = true
+		src = real
+	}
+
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if d.IsDir() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			return os.MkdirAll(target, info.Mode().Perm())
+		}
+
+		if d.Type()&fs.ModeSymlink != 0 && opts.FollowSymlinks {
+			// WalkDir doesn't descend into linked directories
+			if info, err := os.Stat(path); err == nil && info.IsDir() {
+				return a.copyTree(path, target, opts, progress, visited)
+			}
+		}
+
+		progress.Current = path
+		copied, err := copyEntry(path, target, d, opts)
+		if err != nil {
+			return err
+		}
+		if copied {
+			progress.Copied++
+		} else {
+			progress.Skipped++
+		}
+
+		if a.ctx != nil && (progress.Copied+progress.Skipped)%copyProgressEvery == 0 {
+			runtime.EventsEmit(a.ctx, EventCopyProgress, *progress)
+		}
+		return nil
+	})
+}
+
+// copyEntry copies a single non-directory entry, honouring the overwrite
+// policy. It reports false when the entry was skipped.
+func copyEntry(path, target string, d fs.DirEntry, opts CopyOptions) (bool, error) {
+	if _, err := os.Lstat(target); err == nil {
+		switch opts.Overwrite {
+		case OverwriteSkip:
+			return false, nil
+		case OverwriteAlways:
+			if err := os.Remove(target); err != nil {
+				return false, err
+			}
+		default:
+			return false, fmt.Errorf("%s already exists", target)
+		}
+	}
+
+	if d.Type()&fs.ModeSymlink != 0 && !opts.FollowSymlinks {
+		link, err := os.Readlink(path)
+		if err != nil {
+			return false, err
+		}
+		return true, os.Symlink(link, target)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	if err := copyFile(path, target); err != nil {
+		return false, err
+	}
+	return true, os.Chmod(target, info.Mode().Perm())
+}
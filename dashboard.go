@@ -0,0 +1,285 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+const (
+	// defaultStaleDays is how long a page goes unchanged before it is
+	// listed as stale; see the dashboard_stale_days setting
+	defaultStaleDays = 180
+	// dashboardTrendDays is how many daily diagnostics counts are kept
+	dashboardTrendDays = 30
+	// dashboardListSize caps the stale and recent lists
+	dashboardListSize = 10
+)
+
+// ProjectDashboard is the data behind the project home screen
+type ProjectDashboard struct {
+	Project   string `json:"project"`
+	Generated string `json:"generated"`
+	Pages     int    `json:"pages"`
+	Words     int    `json:"words"`
+	// Diagnostics are the current counts, Trend one entry per day the
+	// dashboard was loaded, oldest first
+	Diagnostics DashboardCounts   `json:"diagnostics"`
+	Trend       []DashboardCounts `json:"trend"`
+	// Status counts pages by their "status" attribute; pages without one
+	// are counted under ""
+	Status         map[string]int  `json:"status"`
+	OverdueReviews int             `json:"overdueReviews"`
+	StalePages     []DashboardPage `json:"stalePages"`
+	RecentlyEdited []DashboardPage `json:"recentlyEdited"`
+	RecentCommits  []GitLogEntry   `json:"recentCommits"`
+}
+
+// DashboardCounts are diagnostics counts by severity
+type DashboardCounts struct {
+	Date     string `json:"date,omitempty"`
+	Errors   int    `json:"errors"`
+	Warnings int    `json:"warnings"`
+	Infos    int    `json:"infos"`
+}
+
+// DashboardPage is a page in one of the dashboard's lists
+type DashboardPage struct {
+	Path     string `json:"path"`
+	Title    string `json:"title"`
+	Words    int    `json:"words"`
+	Modified string `json:"modified"`
+}
+
+// GetProjectDashboard aggregates page and word counts, diagnostics and
+// their trend, pages by status, stale pages and recent activity of a
+// project. It lints the project, so the problems panel is refreshed too.
+// Pages count as stale after dashboard_stale_days (180) without changes;
+// a page is dated by its last commit, or its modification time when it
+// has uncommitted changes.
+func (a *App) GetProjectDashboard(project string) (*ProjectDashboard, error) {
+	run, err := a.collectChecks(project)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	summary := run.summary()
+	dash := &ProjectDashboard{
+		Project:   project,
+		Generated: now.Format(time.RFC3339),
+		Pages:     len(run.files),
+		Diagnostics: DashboardCounts{
+			Date:     now.Format(reviewDateLayout),
+			Errors:   summary.Errors,
+			Warnings: summary.Warnings,
+			Infos:    summary.Infos,
+		},
+		Status:         map[string]int{},
+		StalePages:     []DashboardPage{},
+		RecentlyEdited: []DashboardPage{},
+		RecentCommits:  []GitLogEntry{},
+	}
+
+	staleDays := float64(defaultStaleDays)
+	if v, ok := a.projectSetting("dashboard_stale_days").(float64); ok && v > 0 {
+		staleDays = v
+	}
+	staleBefore := now.Add(-time.Duration(staleDays * float64(24*time.Hour)))
+
+	committed := lastCommitDates(project, run.files)
+	var pages []DashboardPage
+	var modified []time.Time
+	for _, path := range run.files {
+		content, err := os.ReadFile(longPath(path))
+		if err != nil {
+			continue
+		}
+		changed, ok := committed[path]
+		if !ok {
+			info, err := os.Stat(longPath(path))
+			if err != nil {
+				continue
+			}
+			changed = info.ModTime()
+		}
+		meta := documentMetadata(content)
+		page := DashboardPage{
+			Path:     path,
+			Title:    meta["doctitle"],
+			Words:    countWords(string(content)),
+			Modified: changed.Format(time.RFC3339),
+		}
+		if page.Title == "" {
+			page.Title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		dash.Words += page.Words
+		dash.Status[strings.ToLower(strings.TrimSpace(meta["status"]))]++
+		if due, ok := reviewDue(meta); ok && due.Format(reviewDateLayout) < now.Format(reviewDateLayout) {
+			dash.OverdueReviews++
+		}
+		pages = append(pages, page)
+		modified = append(modified, changed)
+	}
+
+	order := make([]int, len(pages))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return modified[order[i]].Before(modified[order[j]]) })
+	for _, i := range order {
+		if len(dash.StalePages) == dashboardListSize || !modified[i].Before(staleBefore) {
+			break
+		}
+		dash.StalePages = append(dash.StalePages, pages[i])
+	}
+	for k := len(order) - 1; k >= 0 && len(dash.RecentlyEdited) < dashboardListSize; k-- {
+		dash.RecentlyEdited = append(dash.RecentlyEdited, pages[order[k]])
+	}
+
+	if commits, err := recentCommits(project, dashboardListSize); err == nil {
+		dash.RecentCommits = commits
+	}
+	dash.Trend = recordDashboardTrend(project, dash.Diagnostics)
+	return dash, nil
+}
+
+// lastCommitDates returns when each of paths was last changed by a commit,
+// walking the history once. Files with uncommitted changes, untracked
+// files and projects outside a repository are left out, so their
+// modification time is used instead.
+func lastCommitDates(project string, paths []string) map[string]time.Time {
+	dates := map[string]time.Time{}
+	repo, wt, err := openRepo(project)
+	if err != nil || wt == nil {
+		return dates
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return dates
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return dates
+	}
+	root := wt.Filesystem.Root()
+	wanted := map[string]string{}
+	for _, path := range paths {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		if fs, ok := status[rel]; ok && (fs.Worktree != git.Unmodified || fs.Staging != git.Unmodified) {
+			continue
+		}
+		wanted[rel] = path
+	}
+	if len(wanted) == 0 {
+		return dates
+	}
+
+	commits, err := repo.Log(&git.LogOptions{From: head.Hash()})
+	if err != nil {
+		return dates
+	}
+	defer commits.Close()
+	_ = commits.ForEach(func(c *object.Commit) error {
+		tree, err := c.Tree()
+		if err != nil {
+			return err
+		}
+		// Compared with the first parent, as in git log --first-parent
+		var parent *object.Tree
+		if p, err := c.Parent(0); err == nil {
+			if parent, err = p.Tree(); err != nil {
+				return err
+			}
+		}
+		changes, err := object.DiffTree(parent, tree)
+		if err != nil {
+			return err
+		}
+		for _, ch := range changes {
+			for _, name := range []string{ch.From.Name, ch.To.Name} {
+				if path, ok := wanted[name]; ok {
+					dates[path] = c.Author.When
+					delete(wanted, name)
+				}
+			}
+		}
+		if len(wanted) == 0 {
+			return storer.ErrStop
+		}
+		return nil
+	})
+	return dates
+}
+
+// recentCommits lists the latest commits of the repository holding the
+// project, newest first
+func recentCommits(project string, limit int) ([]GitLogEntry, error) {
+	repo, _, err := openRepo(project)
+	if err != nil {
+		return nil, err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	commits, err := repo.Log(&git.LogOptions{From: head.Hash()})
+	if err != nil {
+		return nil, err
+	}
+	defer commits.Close()
+	entries := []GitLogEntry{}
+	err = commits.ForEach(func(c *object.Commit) error {
+		subject, _, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+		entries = append(entries, GitLogEntry{
+			Hash:    c.Hash.String(),
+			Author:  c.Author.Name,
+			Email:   c.Author.Email,
+			Date:    c.Author.When.Format(time.RFC3339),
+			Subject: strings.TrimSpace(subject),
+		})
+		if len(entries) >= limit {
+			return storer.ErrStop
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, storer.ErrStop) {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// recordDashboardTrend stores today's counts in the app state, replacing
+// an earlier entry from the same day, and returns the kept history
+func recordDashboardTrend(project string, today DashboardCounts) []DashboardCounts {
+	trend := []DashboardCounts{}
+	if db == nil {
+		return append(trend, today)
+	}
+	key := "dashboard_trend:" + project
+	if raw, err := db.GetAppState(key); err == nil && raw != "" {
+		_ = json.Unmarshal([]byte(raw), &trend)
+	}
+	if n := len(trend); n > 0 && trend[n-1].Date == today.Date {
+		trend = trend[:n-1]
+	}
+	trend = append(trend, today)
+	if len(trend) > dashboardTrendDays {
+		trend = trend[len(trend)-dashboardTrendDays:]
+	}
+	if data, err := json.Marshal(trend); err == nil {
+		_ = db.SetAppState(key, string(data))
+	}
+	return trend
+}
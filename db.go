@@ -2,11 +2,14 @@ package main
 
 import (
 	"database/sql"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -70,7 +73,19 @@ func (d *Database) RestoreBackup() error {
 	if !exists(backupPath) {
 		return fmt.Errorf("no backup found")
 	}
+	return d.RestoreFrom(backupPath)
+}
+
+// SnapshotTo writes a consistent copy of the database to path, which must
+// not exist yet
+func (d *Database) SnapshotTo(path string) error {
+	_, err := d.conn.Exec(`VACUUM INTO ?`, path)
+	return err
+}
 
+// RestoreFrom replaces the database with the copy at backupPath and
+// reopens it
+func (d *Database) RestoreFrom(backupPath string) error {
 	// Close current connection
 	d.conn.Close()
 
@@ -124,6 +139,92 @@ func (d *Database) initTables() error {
 			id TEXT PRIMARY KEY,
 			svg TEXT
 		);`,
+		`CREATE TABLE IF NOT EXISTS ai_templates (
+			id TEXT PRIMARY KEY,
+			name TEXT,
+			prompt TEXT
+		);`,
+		`CREATE TABLE IF NOT EXISTS ai_review_queue (
+			id TEXT PRIMARY KEY,
+			batch_id TEXT,
+			path TEXT,
+			original TEXT,
+			proposed TEXT,
+			status TEXT,
+			error TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS embeddings (
+			project TEXT,
+			path TEXT,
+			section_id TEXT,
+			title TEXT,
+			line INTEGER,
+			content TEXT,
+			hash TEXT,
+			vector BLOB,
+			PRIMARY KEY (project, path, section_id)
+		);`,
+		`CREATE TABLE IF NOT EXISTS ai_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			project TEXT,
+			action TEXT,
+			document TEXT,
+			model TEXT,
+			prompt TEXT,
+			response TEXT,
+			error TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS file_journal (
+			seq INTEGER PRIMARY KEY AUTOINCREMENT,
+			id TEXT UNIQUE,
+			stack TEXT,
+			label TEXT,
+			steps TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS trash (
+			id TEXT PRIMARY KEY,
+			project TEXT,
+			original_path TEXT,
+			trash_path TEXT,
+			is_dir BOOLEAN,
+			deleted_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE TABLE IF NOT EXISTS asset_licenses (
+			project TEXT,
+			path TEXT,
+			license TEXT,
+			source_url TEXT,
+			author TEXT,
+			notes TEXT,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (project, path)
+		);`,
+		`CREATE TABLE IF NOT EXISTS git_client_icons (
+			client TEXT PRIMARY KEY,
+			icon_id TEXT
+		);`,
+		`CREATE TABLE IF NOT EXISTS project_settings (
+			project TEXT,
+			key TEXT,
+			value TEXT,
+			type TEXT,
+			PRIMARY KEY (project, key)
+		);`,
+		`CREATE TABLE IF NOT EXISTS doc_releases (
+			project TEXT,
+			version TEXT,
+			tag TEXT,
+			commit_hash TEXT,
+			folder TEXT,
+			errors INTEGER,
+			warnings INTEGER,
+			outputs TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (project, version)
+		);`,
 	}
 
 	for _, query := range queries {
@@ -132,7 +233,32 @@ func (d *Database) initTables() error {
 			return err
 		}
 	}
-	return nil
+
+	// Columns added after a table was first shipped
+	return d.addColumn("projects", "roots", "TEXT")
+}
+
+// addColumn adds a column to an existing table unless it is already there
+func (d *Database) addColumn(table, column, typ string) error {
+	rows, err := d.conn.Query(fmt.Sprintf(`PRAGMA table_info(%s)`, table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &pk); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	rows.Close()
+	_, err = d.conn.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, typ))
+	return err
 }
 
 func (d *Database) Close() error {
@@ -142,30 +268,12 @@ func (d *Database) Close() error {
 // Preferences
 
 func (d *Database) SetPreference(key string, value interface{}) error {
-	var valStr string
-	var typeStr string
-
-	switch v := value.(type) {
-	case string:
-		valStr = v
-		typeStr = "string"
-	case int, int64, float64:
-		valStr = fmt.Sprintf("%v", v)
-		typeStr = "number"
-	case bool:
-		valStr = fmt.Sprintf("%v", v)
-		typeStr = "boolean"
-	default:
-		// Assume JSON for complex types
-		bytes, err := json.Marshal(v)
-		if err != nil {
-			return err
-		}
-		valStr = string(bytes)
-		typeStr = "json"
+	valStr, typeStr, err := encodeValue(value)
+	if err != nil {
+		return err
 	}
 
-	_, err := d.conn.Exec(`INSERT OR REPLACE INTO preferences (key, value, type) VALUES (?, ?, ?)`, key, valStr, typeStr)
+	_, err = d.conn.Exec(`INSERT OR REPLACE INTO preferences (key, value, type) VALUES (?, ?, ?)`, key, valStr, typeStr)
 	return err
 }
 
@@ -178,27 +286,7 @@ func (d *Database) GetPreference(key string) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-
-	switch typeStr {
-	case "string":
-		return valStr, nil
-	case "number":
-		// We don't know if it's int or float, returning float64 is safest for JSON compat
-		var f float64
-		fmt.Sscanf(valStr, "%f", &f)
-		return f, nil
-	case "boolean":
-		return valStr == "true", nil
-	case "json":
-		var v interface{}
-		err := json.Unmarshal([]byte(valStr), &v)
-		if err != nil {
-			return nil, err
-		}
-		return v, nil
-	default:
-		return valStr, nil
-	}
+	return decodeValue(valStr, typeStr)
 }
 
 func (d *Database) GetAllPreferences() (map[string]interface{}, error) {
@@ -274,20 +362,210 @@ func (d *Database) GetShadowFile(path string) (string, bool, error) {
 	return content, isDirty, nil
 }
 
+type ShadowFile struct {
+	Path      string
+	Content   string
+	UpdatedAt time.Time
+}
+
+func (d *Database) GetDirtyShadowFiles() ([]ShadowFile, error) {
+	rows, err := d.conn.Query(`SELECT path, content, updated_at FROM shadow_files WHERE is_dirty = 1 ORDER BY path`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var files []ShadowFile
+	for rows.Next() {
+		var f ShadowFile
+		if err := rows.Scan(&f.Path, &f.Content, &f.UpdatedAt); err != nil {
+			continue
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
 func (d *Database) ClearShadowFile(path string) error {
 	_, err := d.conn.Exec(`DELETE FROM shadow_files WHERE path = ?`, path)
 	return err
 }
 
+// ClearShadowPaths drops shadow files for path and anything below it
+func (d *Database) ClearShadowPaths(path string) error {
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	shadowPaths, err := queryStrings(tx, `SELECT path FROM shadow_files`)
+	if err != nil {
+		return err
+	}
+	for _, p := range shadowPaths {
+		if _, ok := rebasePath(p, path, path); ok {
+			if _, err := tx.Exec(`DELETE FROM shadow_files WHERE path = ?`, p); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// RenamePath re-points shadow files, asset licenses and app state values
+// that refer to oldPath (or anything below it) at newPath
+func (d *Database) RenamePath(oldPath, newPath string) error {
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := rebaseStoredPaths(tx, oldPath, newPath); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// rebaseStoredPaths is RenamePath within tx
+func rebaseStoredPaths(tx *sql.Tx, oldPath, newPath string) error {
+	if err := renameAssetLicenses(tx, oldPath, newPath); err != nil {
+		return err
+	}
+
+	shadowPaths, err := queryStrings(tx, `SELECT path FROM shadow_files`)
+	if err != nil {
+		return err
+	}
+	for _, p := range shadowPaths {
+		if np, ok := rebasePath(p, oldPath, newPath); ok {
+			if _, err := tx.Exec(`UPDATE shadow_files SET path = ? WHERE path = ?`, np, p); err != nil {
+				return err
+			}
+		}
+	}
+
+	rows, err := tx.Query(`SELECT key, value FROM app_state`)
+	if err != nil {
+		return err
+	}
+	state := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			continue
+		}
+		state[key] = value
+	}
+	rows.Close()
+
+	for key, value := range state {
+		if value == "" {
+			continue
+		}
+		if nv, ok := rebasePath(value, oldPath, newPath); ok {
+			if _, err := tx.Exec(`UPDATE app_state SET value = ? WHERE key = ?`, nv, key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// renameAssetLicenses moves the licenses of assets below oldPath along
+// with them. Assets moved out of their project keep the old path and show
+// up as orphaned; projects below oldPath move as a whole.
+func renameAssetLicenses(tx *sql.Tx, oldPath, newPath string) error {
+	rows, err := tx.Query(`SELECT project, path FROM asset_licenses`)
+	if err != nil {
+		return err
+	}
+	type assetKey struct{ project, path string }
+	var keys []assetKey
+	for rows.Next() {
+		var k assetKey
+		if err := rows.Scan(&k.project, &k.path); err != nil {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	rows.Close()
+
+	for _, k := range keys {
+		if np, ok := rebasePath(k.project, oldPath, newPath); ok {
+			if _, err := tx.Exec(`UPDATE OR REPLACE asset_licenses SET project = ? WHERE project = ? AND path = ?`, np, k.project, k.path); err != nil {
+				return err
+			}
+			continue
+		}
+		abs, ok := rebasePath(filepath.Join(k.project, filepath.FromSlash(k.path)), oldPath, newPath)
+		if !ok {
+			continue
+		}
+		rel, err := filepath.Rel(k.project, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if _, err := tx.Exec(`UPDATE OR REPLACE asset_licenses SET path = ? WHERE project = ? AND path = ?`, filepath.ToSlash(rel), k.project, k.path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Helpers
 
+// encodeValue flattens a preference-style value into its stored text and type
+func encodeValue(value interface{}) (string, string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, "string", nil
+	case int, int64, float64:
+		return fmt.Sprintf("%v", v), "number", nil
+	case bool:
+		return fmt.Sprintf("%v", v), "boolean", nil
+	default:
+		// Assume JSON for complex types
+		bytes, err := json.Marshal(v)
+		if err != nil {
+			return "", "", err
+		}
+		return string(bytes), "json", nil
+	}
+}
+
+// decodeValue is the inverse of encodeValue
+func decodeValue(valStr, typeStr string) (interface{}, error) {
+	switch typeStr {
+	case "string":
+		return valStr, nil
+	case "number":
+		// We don't know if it's int or float, returning float64 is safest for JSON compat
+		var f float64
+		fmt.Sscanf(valStr, "%f", &f)
+		return f, nil
+	case "boolean":
+		return valStr == "true", nil
+	case "json":
+		var v interface{}
+		err := json.Unmarshal([]byte(valStr), &v)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	default:
+		return valStr, nil
+	}
+}
+
 func exists(path string) bool {
 	_, err := os.Stat(path)
 	return !os.IsNotExist(err)
 }
 
 func copyFile(src, dst string) error {
-	sourceFileStat, err := os.Stat(src)
+	sourceFileStat, err := os.Stat(longPath(src))
 	if err != nil {
 		return err
 	}
@@ -296,13 +574,13 @@ func copyFile(src, dst string) error {
 		return fmt.Errorf("%s is not a regular file", src)
 	}
 
-	source, err := os.Open(src)
+	source, err := os.Open(longPath(src))
 	if err != nil {
 		return err
 	}
 	defer source.Close()
 
-	destination, err := os.Create(dst)
+	destination, err := os.Create(longPath(dst))
 	if err != nil {
 		return err
 	}
@@ -312,6 +590,24 @@ func copyFile(src, dst string) error {
 	return err
 }
 
+func queryStrings(tx *sql.Tx, query string, args ...interface{}) ([]string, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			continue
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
+
 func isCorrupt(path string) bool {
 	// Simple check: try to open and run integrity_check
 	db, err := sql.Open("sqlite", path)
@@ -334,16 +630,26 @@ type Project struct {
 	Path       string    `json:"path"`
 	Name       string    `json:"name"`
 	LastOpened time.Time `json:"lastOpened"`
+	// Roots lists the folders of a multi-root workspace, in display
+	// order. Empty means the project is just Path.
+	Roots []ProjectRoot `json:"roots,omitempty"`
+}
+
+// ProjectRoot is one folder of a multi-root workspace
+type ProjectRoot struct {
+	Path  string `json:"path"`
+	Label string `json:"label"`
 }
 
 func (d *Database) AddProject(path string) error {
 	name := filepath.Base(path)
-	_, err := d.conn.Exec(`INSERT OR REPLACE INTO projects (path, name, last_opened) VALUES (?, ?, ?)`, path, name, time.Now())
+	_, err := d.conn.Exec(`INSERT INTO projects (path, name, last_opened) VALUES (?, ?, ?)
+		ON CONFLICT(path) DO UPDATE SET name = excluded.name, last_opened = excluded.last_opened`, path, name, time.Now())
 	return err
 }
 
 func (d *Database) GetProjects() ([]Project, error) {
-	rows, err := d.conn.Query(`SELECT path, name, last_opened FROM projects ORDER BY last_opened DESC`)
+	rows, err := d.conn.Query(`SELECT path, name, last_opened, roots FROM projects ORDER BY last_opened DESC`)
 	if err != nil {
 		return nil, err
 	}
@@ -353,71 +659,218 @@ func (d *Database) GetProjects() ([]Project, error) {
 	for rows.Next() {
 		var p Project
 		var lastOpened time.Time
-		if err := rows.Scan(&p.Path, &p.Name, &lastOpened); err != nil {
+		var roots sql.NullString
+		if err := rows.Scan(&p.Path, &p.Name, &lastOpened, &roots); err != nil {
 			continue
 		}
 		p.LastOpened = lastOpened
+		if roots.Valid && roots.String != "" {
+			_ = json.Unmarshal([]byte(roots.String), &p.Roots)
+		}
 		projects = append(projects, p)
 	}
 	return projects, nil
 }
 
-func (d *Database) RemoveProject(path string) error {
-	_, err := d.conn.Exec(`DELETE FROM projects WHERE path = ?`, path)
-	return err
+// GetProjectRoots returns the workspace roots stored for a project, or nil
+// when it has a single root
+func (d *Database) GetProjectRoots(path string) ([]ProjectRoot, error) {
+	var roots sql.NullString
+	err := d.conn.QueryRow(`SELECT roots FROM projects WHERE path = ?`, path).Scan(&roots)
+	if err == sql.ErrNoRows || !roots.Valid || roots.String == "" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []ProjectRoot
+	err = json.Unmarshal([]byte(roots.String), &list)
+	return list, err
 }
 
-func (d *Database) UpdateProjectLastOpened(path string) error {
-	_, err := d.conn.Exec(`UPDATE projects SET last_opened = ? WHERE path = ?`, time.Now(), path)
+// SetProjectRoots stores the ordered workspace roots of a project, adding
+// the project if needed. An empty list makes it a single-root project.
+func (d *Database) SetProjectRoots(path string, roots []ProjectRoot) error {
+	value := ""
+	if len(roots) > 0 {
+		data, err := json.Marshal(roots)
+		if err != nil {
+			return err
+		}
+		value = string(data)
+	}
+	_, err := d.conn.Exec(`INSERT INTO projects (path, name, last_opened, roots) VALUES (?, ?, ?, ?)
+		ON CONFLICT(path) DO UPDATE SET roots = excluded.roots`, path, filepath.Base(path), time.Now(), value)
 	return err
 }
 
-// Git Icons
+func (d *Database) RemoveProject(path string) error {
+	_, err := d.conn.Exec(`DELETE FROM projects WHERE path = ?`, path)
+	return err
+}
 
-func (d *Database) InitGitIcons() error {
-	// Check if table is empty
-	var count int
-	err := d.conn.QueryRow("SELECT COUNT(*) FROM git_icons").Scan(&count)
+// RelocateProject moves a project entry and its settings to newPath, and
+// re-points paths below oldPath as RenamePath does, in one transaction
+func (d *Database) RelocateProject(oldPath, newPath string) error {
+	tx, err := d.conn.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	if count > 0 {
-		return nil
+	if err := rebaseStoredPaths(tx, oldPath, newPath); err != nil {
+		return err
 	}
 
-	// Insert defaults
-	defaults := map[string]string{
-		"default": `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"><path d="M15 22v-4a4.8 4.8 0 0 0-1-3.5c3 0 6-2 6-5.5.08-1.25-.27-2.48-1-3.5.28-1.15.28-2.35 0-3.5 0 0-1 0-3 1.5-2.64-.5-5.36-.5-8 0C6 2 5 2 5 2c-.3 1.15-.3 2.35 0 3.5A5.403 5.403 0 0 0 4 9c0 3.5 3 5.5 6 5.5-.39.49-.68 1.05-.85 1.65-.17.6-.22 1.23-.15 1.85v4"/><path d="M9 18c-4.51 2-5-2-7-2"/></svg>`,
-		"tower":   `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"><path d="M12 2L2 22h20L12 2zm0 6l5 10H7l5-10z"/></svg>`, // Placeholder for Tower
-		"gitlab":  `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"><path d="M22.65 14.39L12 22.13 1.35 14.39a.84.84 0 0 1-.3-.94l1.22-3.78 2.44-7.51A.42.42 0 0 1 4.82 2a.43.43 0 0 1 .58 0 .42.42 0 0 1 .11.18l2.44 7.49h8.1l2.44-7.51a.42.42 0 0 1 .11-.18.43.43 0 0 1 .58 0 .42.42 0 0 1 .11.18l2.44 7.51L23 13.45a.84.84 0 0 1-.35.94z"/></svg>`,
+	if _, err := tx.Exec(`DELETE FROM projects WHERE path = ?`, newPath); err != nil {
+		return err
 	}
-
-	for id, svg := range defaults {
-		_, err := d.conn.Exec(`INSERT INTO git_icons (id, svg) VALUES (?, ?)`, id, svg)
-		if err != nil {
-			return err
-		}
+	if _, err := tx.Exec(`UPDATE projects SET path = ?, name = ? WHERE path = ?`, newPath, filepath.Base(newPath), oldPath); err != nil {
+		return err
 	}
-	return nil
-}
-
-func (d *Database) AddGitIcon(svg string) (string, error) {
-	if len(svg) > 10*1024 { // 10KB limit
-		return "", fmt.Errorf("icon too large (max 10KB)")
+	if _, err := tx.Exec(`UPDATE OR REPLACE project_settings SET project = ? WHERE project = ?`, newPath, oldPath); err != nil {
+		return err
 	}
-	id := uuid.New().String()
-	_, err := d.conn.Exec(`INSERT INTO git_icons (id, svg) VALUES (?, ?)`, id, svg)
-	if err != nil {
-		return "", err
+	if _, err := tx.Exec(`UPDATE OR REPLACE asset_licenses SET project = ? WHERE project = ?`, newPath, oldPath); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE OR REPLACE doc_releases SET project = ? WHERE project = ?`, newPath, oldPath); err != nil {
+		return err
 	}
-	return id, nil
-}
 
-func (d *Database) GetGitIcons() (map[string]string, error) {
-	rows, err := d.conn.Query(`SELECT id, svg FROM git_icons`)
+	// Workspace roots of any project may point into the moved folder
+	rows, err := tx.Query(`SELECT path, roots FROM projects WHERE roots IS NOT NULL AND roots != ''`)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	updated := make(map[string]string)
+	for rows.Next() {
+		var project, data string
+		if err := rows.Scan(&project, &data); err != nil {
+			continue
+		}
+		var roots []ProjectRoot
+		if json.Unmarshal([]byte(data), &roots) != nil {
+			continue
+		}
+		changed := false
+		for i, r := range roots {
+			if np, ok := rebasePath(r.Path, oldPath, newPath); ok {
+				roots[i].Path = np
+				changed = true
+			}
+		}
+		if changed {
+			encoded, _ := json.Marshal(roots)
+			updated[project] = string(encoded)
+		}
+	}
+	rows.Close()
+	for project, roots := range updated {
+		if _, err := tx.Exec(`UPDATE projects SET roots = ? WHERE path = ?`, roots, project); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (d *Database) UpdateProjectLastOpened(path string) error {
+	_, err := d.conn.Exec(`UPDATE projects SET last_opened = ? WHERE path = ?`, time.Now(), path)
+	return err
+}
+
+// Project Settings
+
+func (d *Database) SetProjectSetting(project, key string, value interface{}) error {
+	valStr, typeStr, err := encodeValue(value)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.conn.Exec(`INSERT OR REPLACE INTO project_settings (project, key, value, type) VALUES (?, ?, ?, ?)`, project, key, valStr, typeStr)
+	return err
+}
+
+func (d *Database) GetProjectSetting(project, key string) (interface{}, error) {
+	var valStr, typeStr string
+	err := d.conn.QueryRow(`SELECT value, type FROM project_settings WHERE project = ? AND key = ?`, project, key).Scan(&valStr, &typeStr)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeValue(valStr, typeStr)
+}
+
+func (d *Database) GetProjectSettings(project string) (map[string]interface{}, error) {
+	rows, err := d.conn.Query(`SELECT key, value, type FROM project_settings WHERE project = ?`, project)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	settings := make(map[string]interface{})
+	for rows.Next() {
+		var key, valStr, typeStr string
+		if err := rows.Scan(&key, &valStr, &typeStr); err != nil {
+			continue
+		}
+		if v, err := decodeValue(valStr, typeStr); err == nil {
+			settings[key] = v
+		} else {
+			settings[key] = valStr // Fallback
+		}
+	}
+	return settings, nil
+}
+
+// Git Icons
+
+func (d *Database) InitGitIcons() error {
+	// Check if table is empty
+	var count int
+	err := d.conn.QueryRow("SELECT COUNT(*) FROM git_icons").Scan(&count)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	// Insert defaults
+	defaults := map[string]string{
+		"default": `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"><path d="M15 22v-4a4.8 4.8 0 0 0-1-3.5c3 0 6-2 6-5.5.08-1.25-.27-2.48-1-3.5.28-1.15.28-2.35 0-3.5 0 0-1 0-3 1.5-2.64-.5-5.36-.5-8 0C6 2 5 2 5 2c-.3 1.15-.3 2.35 0 3.5A5.403 5.403 0 0 0 4 9c0 3.5 3 5.5 6 5.5-.39.49-.68 1.05-.85 1.65-.17.6-.22 1.23-.15 1.85v4"/><path d="M9 18c-4.51 2-5-2-7-2"/></svg>`,
+		"tower":   `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"><path d="M12 2L2 22h20L12 2zm0 6l5 10H7l5-10z"/></svg>`, // Placeholder for Tower
+		"gitlab":  `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2" stroke-linecap="round" stroke-linejoin="round"><path d="M22.65 14.39L12 22.13 1.35 14.39a.84.84 0 0 1-.3-.94l1.22-3.78 2.44-7.51A.42.42 0 0 1 4.82 2a.43.43 0 0 1 .58 0 .42.42 0 0 1 .11.18l2.44 7.49h8.1l2.44-7.51a.42.42 0 0 1 .11-.18.43.43 0 0 1 .58 0 .42.42 0 0 1 .11.18l2.44 7.51L23 13.45a.84.84 0 0 1-.35.94z"/></svg>`,
+	}
+
+	for id, svg := range defaults {
+		_, err := d.conn.Exec(`INSERT INTO git_icons (id, svg) VALUES (?, ?)`, id, svg)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Database) AddGitIcon(svg string) (string, error) {
+	if len(svg) > 10*1024 { // 10KB limit
+		return "", fmt.Errorf("icon too large (max 10KB)")
+	}
+	id := uuid.New().String()
+	_, err := d.conn.Exec(`INSERT INTO git_icons (id, svg) VALUES (?, ?)`, id, svg)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (d *Database) GetGitIcons() (map[string]string, error) {
+	rows, err := d.conn.Query(`SELECT id, svg FROM git_icons`)
+	if err != nil {
+		return nil, err
 	}
 	defer rows.Close()
 
@@ -433,6 +886,502 @@ func (d *Database) GetGitIcons() (map[string]string, error) {
 }
 
 func (d *Database) DeleteGitIcon(id string) error {
+	if _, err := d.conn.Exec(`DELETE FROM git_client_icons WHERE icon_id = ?`, id); err != nil {
+		return err
+	}
 	_, err := d.conn.Exec(`DELETE FROM git_icons WHERE id = ?`, id)
 	return err
 }
+
+func (d *Database) UpdateGitIcon(id, svg string) error {
+	if len(svg) > 10*1024 {
+		return fmt.Errorf("icon too large (max 10KB)")
+	}
+	res, err := d.conn.Exec(`UPDATE git_icons SET svg = ? WHERE id = ?`, svg, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("icon %q not found", id)
+	}
+	return nil
+}
+
+// SetGitIcon stores an icon under a known id, replacing any existing one
+func (d *Database) SetGitIcon(id, svg string) error {
+	if len(svg) > 10*1024 {
+		return fmt.Errorf("icon too large (max 10KB)")
+	}
+	_, err := d.conn.Exec(`INSERT OR REPLACE INTO git_icons (id, svg) VALUES (?, ?)`, id, svg)
+	return err
+}
+
+func (d *Database) GetGitClientIcons() (map[string]string, error) {
+	rows, err := d.conn.Query(`SELECT client, icon_id FROM git_client_icons`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mapping := make(map[string]string)
+	for rows.Next() {
+		var client, iconID string
+		if err := rows.Scan(&client, &iconID); err != nil {
+			continue
+		}
+		mapping[client] = iconID
+	}
+	return mapping, nil
+}
+
+func (d *Database) SetGitClientIcon(client, iconID string) error {
+	_, err := d.conn.Exec(`INSERT OR REPLACE INTO git_client_icons (client, icon_id) VALUES (?, ?)`, client, iconID)
+	return err
+}
+
+func (d *Database) DeleteGitClientIcon(client string) error {
+	_, err := d.conn.Exec(`DELETE FROM git_client_icons WHERE client = ?`, client)
+	return err
+}
+
+// AI Templates
+
+type AITemplate struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Prompt string `json:"prompt"`
+}
+
+func (d *Database) SaveAITemplate(t AITemplate) (string, error) {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	_, err := d.conn.Exec(`INSERT OR REPLACE INTO ai_templates (id, name, prompt) VALUES (?, ?, ?)`, t.ID, t.Name, t.Prompt)
+	if err != nil {
+		return "", err
+	}
+	return t.ID, nil
+}
+
+func (d *Database) GetAITemplate(id string) (*AITemplate, error) {
+	var t AITemplate
+	err := d.conn.QueryRow(`SELECT id, name, prompt FROM ai_templates WHERE id = ?`, id).Scan(&t.ID, &t.Name, &t.Prompt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("template %q not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (d *Database) GetAITemplates() ([]AITemplate, error) {
+	rows, err := d.conn.Query(`SELECT id, name, prompt FROM ai_templates ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []AITemplate{}
+	for rows.Next() {
+		var t AITemplate
+		if err := rows.Scan(&t.ID, &t.Name, &t.Prompt); err != nil {
+			continue
+		}
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
+
+func (d *Database) DeleteAITemplate(id string) error {
+	_, err := d.conn.Exec(`DELETE FROM ai_templates WHERE id = ?`, id)
+	return err
+}
+
+// AI Review Queue
+
+type ReviewItem struct {
+	ID        string    `json:"id"`
+	BatchID   string    `json:"batchId"`
+	Path      string    `json:"path"`
+	Original  string    `json:"original"`
+	Proposed  string    `json:"proposed"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (d *Database) AddReviewItem(item ReviewItem) error {
+	if item.ID == "" {
+		item.ID = uuid.New().String()
+	}
+	_, err := d.conn.Exec(`INSERT INTO ai_review_queue (id, batch_id, path, original, proposed, status, error, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		item.ID, item.BatchID, item.Path, item.Original, item.Proposed, item.Status, item.Error, time.Now())
+	return err
+}
+
+func (d *Database) GetReviewItem(id string) (*ReviewItem, error) {
+	var item ReviewItem
+	err := d.conn.QueryRow(`SELECT id, batch_id, path, original, proposed, status, error, created_at FROM ai_review_queue WHERE id = ?`, id).
+		Scan(&item.ID, &item.BatchID, &item.Path, &item.Original, &item.Proposed, &item.Status, &item.Error, &item.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("review item %q not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// GetReviewItems lists the queue, optionally narrowed to one batch
+func (d *Database) GetReviewItems(batchID string) ([]ReviewItem, error) {
+	query := `SELECT id, batch_id, path, original, proposed, status, error, created_at FROM ai_review_queue`
+	var args []interface{}
+	if batchID != "" {
+		query += ` WHERE batch_id = ?`
+		args = append(args, batchID)
+	}
+	query += ` ORDER BY created_at, path`
+
+	rows, err := d.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []ReviewItem{}
+	for rows.Next() {
+		var item ReviewItem
+		if err := rows.Scan(&item.ID, &item.BatchID, &item.Path, &item.Original, &item.Proposed, &item.Status, &item.Error, &item.CreatedAt); err != nil {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (d *Database) SetReviewStatus(id, status string) error {
+	_, err := d.conn.Exec(`UPDATE ai_review_queue SET status = ? WHERE id = ?`, status, id)
+	return err
+}
+
+func (d *Database) ClearReviewItems(batchID string) error {
+	_, err := d.conn.Exec(`DELETE FROM ai_review_queue WHERE batch_id = ?`, batchID)
+	return err
+}
+
+// AI History
+
+type AIHistoryEntry struct {
+	ID        int64     `json:"id"`
+	Project   string    `json:"project"`
+	Action    string    `json:"action"`
+	Document  string    `json:"document"`
+	Model     string    `json:"model"`
+	Prompt    string    `json:"prompt"`
+	Response  string    `json:"response"`
+	Error     string    `json:"error"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (d *Database) AddAIHistory(e AIHistoryEntry) error {
+	_, err := d.conn.Exec(`INSERT INTO ai_history (project, action, document, model, prompt, response, error, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.Project, e.Action, e.Document, e.Model, e.Prompt, e.Response, e.Error, time.Now())
+	return err
+}
+
+// GetAIHistory lists a project's AI interactions, oldest first
+func (d *Database) GetAIHistory(project string) ([]AIHistoryEntry, error) {
+	rows, err := d.conn.Query(`SELECT id, project, action, document, model, prompt, response, error, created_at FROM ai_history WHERE project = ? ORDER BY created_at, id`, project)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []AIHistoryEntry{}
+	for rows.Next() {
+		var e AIHistoryEntry
+		if err := rows.Scan(&e.ID, &e.Project, &e.Action, &e.Document, &e.Model, &e.Prompt, &e.Response, &e.Error, &e.CreatedAt); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// File Journal
+
+func (d *Database) AddJournalEntry(stack string, e JournalEntry) error {
+	steps, err := json.Marshal(e.Steps)
+	if err != nil {
+		return err
+	}
+	_, err = d.conn.Exec(`INSERT INTO file_journal (id, stack, label, steps, created_at) VALUES (?, ?, ?, ?, ?)`,
+		e.ID, stack, e.Label, string(steps), e.CreatedAt)
+	return err
+}
+
+// GetJournalEntries lists a stack, newest first
+func (d *Database) GetJournalEntries(stack string) ([]JournalEntry, error) {
+	rows, err := d.conn.Query(`SELECT id, label, steps, created_at FROM file_journal WHERE stack = ? ORDER BY seq DESC`, stack)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []JournalEntry{}
+	for rows.Next() {
+		var e JournalEntry
+		var steps string
+		if err := rows.Scan(&e.ID, &e.Label, &steps, &e.CreatedAt); err != nil {
+			continue
+		}
+		if err := json.Unmarshal([]byte(steps), &e.Steps); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// PopJournalEntry removes and returns the newest entry of a stack, or nil
+func (d *Database) PopJournalEntry(stack string) (*JournalEntry, error) {
+	entries, err := d.GetJournalEntries(stack)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	e := entries[0]
+	if _, err := d.conn.Exec(`DELETE FROM file_journal WHERE id = ?`, e.ID); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// ClearJournal empties a stack and returns the ids it held
+func (d *Database) ClearJournal(stack string) ([]string, error) {
+	return d.TrimJournal(stack, 0)
+}
+
+// TrimJournal keeps the newest keep entries of a stack and returns the ids
+// of the ones removed
+func (d *Database) TrimJournal(stack string, keep int) ([]string, error) {
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ids, err := queryStrings(tx, `SELECT id FROM file_journal WHERE stack = ? ORDER BY seq DESC LIMIT -1 OFFSET ?`, stack, keep)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if _, err := tx.Exec(`DELETE FROM file_journal WHERE id = ?`, id); err != nil {
+			return nil, err
+		}
+	}
+	return ids, tx.Commit()
+}
+
+// Trash
+
+type TrashItem struct {
+	ID           string    `json:"id"`
+	Project      string    `json:"project"`
+	OriginalPath string    `json:"originalPath"`
+	TrashPath    string    `json:"trashPath"`
+	IsDir        bool      `json:"isDir"`
+	DeletedAt    time.Time `json:"deletedAt"`
+}
+
+func (d *Database) AddTrashItem(t TrashItem) error {
+	_, err := d.conn.Exec(`INSERT INTO trash (id, project, original_path, trash_path, is_dir, deleted_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		t.ID, t.Project, t.OriginalPath, t.TrashPath, t.IsDir, t.DeletedAt)
+	return err
+}
+
+func (d *Database) GetTrashItem(id string) (*TrashItem, error) {
+	var t TrashItem
+	err := d.conn.QueryRow(`SELECT id, project, original_path, trash_path, is_dir, deleted_at FROM trash WHERE id = ?`, id).
+		Scan(&t.ID, &t.Project, &t.OriginalPath, &t.TrashPath, &t.IsDir, &t.DeletedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("trash item %q not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// GetTrashItems lists a project's trash, newest first
+func (d *Database) GetTrashItems(project string) ([]TrashItem, error) {
+	rows, err := d.conn.Query(`SELECT id, project, original_path, trash_path, is_dir, deleted_at FROM trash WHERE project = ? ORDER BY deleted_at DESC`, project)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []TrashItem{}
+	for rows.Next() {
+		var t TrashItem
+		if err := rows.Scan(&t.ID, &t.Project, &t.OriginalPath, &t.TrashPath, &t.IsDir, &t.DeletedAt); err != nil {
+			continue
+		}
+		items = append(items, t)
+	}
+	return items, nil
+}
+
+func (d *Database) DeleteTrashItem(id string) error {
+	_, err := d.conn.Exec(`DELETE FROM trash WHERE id = ?`, id)
+	return err
+}
+
+// Asset Licenses
+
+// AssetLicense records where an asset came from and under which license.
+// Path is relative to the project root, with forward slashes.
+type AssetLicense struct {
+	Path      string    `json:"path"`
+	License   string    `json:"license"`
+	SourceURL string    `json:"sourceUrl"`
+	Author    string    `json:"author"`
+	Notes     string    `json:"notes"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (d *Database) SetAssetLicense(project string, l AssetLicense) error {
+	_, err := d.conn.Exec(`INSERT OR REPLACE INTO asset_licenses (project, path, license, source_url, author, notes, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		project, l.Path, l.License, l.SourceURL, l.Author, l.Notes, time.Now())
+	return err
+}
+
+func (d *Database) GetAssetLicenses(project string) ([]AssetLicense, error) {
+	rows, err := d.conn.Query(`SELECT path, license, source_url, author, notes, updated_at FROM asset_licenses WHERE project = ? ORDER BY path`, project)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	licenses := []AssetLicense{}
+	for rows.Next() {
+		var l AssetLicense
+		if err := rows.Scan(&l.Path, &l.License, &l.SourceURL, &l.Author, &l.Notes, &l.UpdatedAt); err != nil {
+			continue
+		}
+		licenses = append(licenses, l)
+	}
+	return licenses, nil
+}
+
+func (d *Database) DeleteAssetLicense(project, path string) error {
+	_, err := d.conn.Exec(`DELETE FROM asset_licenses WHERE project = ? AND path = ?`, project, path)
+	return err
+}
+
+// Releases
+
+// DocsRelease is a recorded docs release. Folder holds the snapshot of the
+// export outputs, which are listed relative to it.
+type DocsRelease struct {
+	Version   string    `json:"version"`
+	Tag       string    `json:"tag"`
+	Commit    string    `json:"commit"`
+	Folder    string    `json:"folder"`
+	Errors    int       `json:"errors"`
+	Warnings  int       `json:"warnings"`
+	Outputs   []string  `json:"outputs"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (d *Database) AddRelease(project string, r DocsRelease) error {
+	outputs, err := json.Marshal(r.Outputs)
+	if err != nil {
+		return err
+	}
+	_, err = d.conn.Exec(`INSERT INTO doc_releases (project, version, tag, commit_hash, folder, errors, warnings, outputs, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		project, r.Version, r.Tag, r.Commit, r.Folder, r.Errors, r.Warnings, string(outputs), r.CreatedAt)
+	return err
+}
+
+// GetReleases lists a project's releases, newest first
+func (d *Database) GetReleases(project string) ([]DocsRelease, error) {
+	rows, err := d.conn.Query(`SELECT version, tag, commit_hash, folder, errors, warnings, outputs, created_at FROM doc_releases WHERE project = ? ORDER BY created_at DESC`, project)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	releases := []DocsRelease{}
+	for rows.Next() {
+		var r DocsRelease
+		var outputs string
+		if err := rows.Scan(&r.Version, &r.Tag, &r.Commit, &r.Folder, &r.Errors, &r.Warnings, &outputs, &r.CreatedAt); err != nil {
+			continue
+		}
+		if json.Unmarshal([]byte(outputs), &r.Outputs) != nil || r.Outputs == nil {
+			r.Outputs = []string{}
+		}
+		releases = append(releases, r)
+	}
+	return releases, nil
+}
+
+// Embeddings
+
+type EmbeddingRow struct {
+	Path      string
+	SectionID string
+	Title     string
+	Line      int
+	Content   string
+	Hash      string
+	Vector    []float32
+}
+
+func (d *Database) GetEmbeddings(project string) ([]EmbeddingRow, error) {
+	rows, err := d.conn.Query(`SELECT path, section_id, title, line, content, hash, vector FROM embeddings WHERE project = ?`, project)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []EmbeddingRow
+	for rows.Next() {
+		var r EmbeddingRow
+		var blob []byte
+		if err := rows.Scan(&r.Path, &r.SectionID, &r.Title, &r.Line, &r.Content, &r.Hash, &blob); err != nil {
+			continue
+		}
+		r.Vector = decodeVector(blob)
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+func (d *Database) SaveEmbedding(project string, r EmbeddingRow) error {
+	_, err := d.conn.Exec(`INSERT OR REPLACE INTO embeddings (project, path, section_id, title, line, content, hash, vector) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		project, r.Path, r.SectionID, r.Title, r.Line, r.Content, r.Hash, encodeVector(r.Vector))
+	return err
+}
+
+func (d *Database) DeleteEmbedding(project, path, sectionID string) error {
+	_, err := d.conn.Exec(`DELETE FROM embeddings WHERE project = ? AND path = ? AND section_id = ?`, project, path, sectionID)
+	return err
+}
+
+func encodeVector(v []float32) []byte {
+	buf := make([]byte, 4*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(f))
+	}
+	return buf
+}
+
+func decodeVector(buf []byte) []float32 {
+	v := make([]float32, len(buf)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+	return v
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Diagnostic is a finding reported against a document, e.g. a converter
+// warning like "unterminated listing block"
+type Diagnostic struct {
+	// ID is assigned when the diagnostic is stored; see GetDiagnostics
+	ID       string `json:"id,omitempty"`
+	Path     string `json:"path"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Source   string `json:"source"`
+	Rule     string `json:"rule"`
+}
+
+// DiagnosticExplanation is a plain-language take on a Diagnostic
+type DiagnosticExplanation struct {
+	Explanation string `json:"explanation"`
+	Fix         string `json:"fix"`
+}
+
+// diagnosticContextLines is how much source either side of the reported
+// line is sent along with a diagnostic
+const diagnosticContextLines = 8
+
+// ExplainDiagnostic asks the AI provider what a diagnostic means and how
+// to fix it, using the source around the reported line
+func (a *App) ExplainDiagnostic(d Diagnostic) (*DiagnosticExplanation, error) {
+	excerpt := ""
+	if d.Path != "" {
+		content, err := os.ReadFile(d.Path)
+		if err != nil {
+			return nil, err
+		}
+		excerpt = sourceExcerpt(string(content), d.Line, diagnosticContextLines)
+	}
+
+	prompt := fmt.Sprintf(`You are an AsciiDoc expert helping a technical writer.
+The %s tool reported this %s:
+
+%s
+
+Source around line %d (line numbers prefixed, ">" marks the reported line):
+%s
+
+Explain in plain language what is wrong and how to fix it.
+Respond ONLY with JSON of the form {"explanation": "...", "fix": "..."}.
+"fix" should show the corrected AsciiDoc where possible.`,
+		orDefault(d.Source, "converter"), orDefault(d.Severity, "warning"), d.Message, d.Line, excerpt)
+
+	var result DiagnosticExplanation
+	err := a.generateJSON(aiRequest{Model: a.aiModel(), Prompt: prompt, Action: "explainDiagnostic", Document: d.Path}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// sourceExcerpt returns the lines around line (1-based) with line numbers
+func sourceExcerpt(content string, line, radius int) string {
+	lines := strings.Split(content, "\n")
+	start := max(line-radius, 1)
+	end := min(line+radius, len(lines))
+
+	var b strings.Builder
+	for i := start; i <= end; i++ {
+		marker := " "
+		if i == line {
+			marker = ">"
+		}
+		fmt.Fprintf(&b, "%s%4d | %s\n", marker, i, strings.TrimRight(lines[i-1], "\r"))
+	}
+	return b.String()
+}
+
+func orDefault(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
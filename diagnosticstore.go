@@ -0,0 +1,154 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// EventDiagnosticsChanged is emitted with a DiagnosticsChange whenever the
+// findings of one source for one file are replaced
+const EventDiagnosticsChanged = "diagnostics:changed"
+
+// Diagnostic sources known to the problems panel. Others are accepted as
+// they are.
+const (
+	DiagnosticSourceLint      = "lint"
+	DiagnosticSourceConverter = "converter"
+	DiagnosticSourceLinks     = "links"
+	DiagnosticSourceSpelling  = "spelling"
+	DiagnosticSourceVale      = "vale"
+)
+
+// DiagnosticsChange is the payload of EventDiagnosticsChanged
+type DiagnosticsChange struct {
+	Source string `json:"source"`
+	Path   string `json:"path"`
+	Count  int    `json:"count"`
+}
+
+// diagnosticKey identifies one report: a source's findings for a file
+type diagnosticKey struct {
+	source, path string
+}
+
+// severityRank orders severities for filtering; unknown ones rank as info
+var severityRank = map[string]int{
+	SeverityError: 3,
+	SeverityWarn:  2,
+	SeverityInfo:  1,
+}
+
+// ReportDiagnostics replaces everything source previously reported for
+// path. Sources that run in the frontend (the converter, link checks,
+// spelling, Vale) hand in their findings here; an empty list clears them.
+// Severity overrides and suppression comments apply as for LintFile.
+func (a *App) ReportDiagnostics(source string, path string, diagnostics []Diagnostic) error {
+	if source == "" {
+		return fmt.Errorf("diagnostic source must not be empty")
+	}
+	a.storeDiagnostics(source, path, diagnostics)
+	return nil
+}
+
+// GetDiagnostics returns the stored findings of every source, sorted by
+// file and position. scope limits them to a file or folder (empty for the
+// whole workspace) and severity to findings at least that severe (empty
+// for all).
+func (a *App) GetDiagnostics(scope string, severity string) []Diagnostic {
+	minRank := severityRank[severity]
+	a.diagnosticsMu.Lock()
+	found := []Diagnostic{}
+	for key, list := range a.diagnostics {
+		if scope != "" && key.path != scope && !isWithin(key.path, scope) {
+			continue
+		}
+		for _, d := range list {
+			if max(severityRank[d.Severity], 1) >= minRank {
+				found = append(found, d)
+			}
+		}
+	}
+	a.diagnosticsMu.Unlock()
+
+	sort.SliceStable(found, func(i, j int) bool {
+		x, y := found[i], found[j]
+		if x.Path != y.Path {
+			return x.Path < y.Path
+		}
+		if x.Line != y.Line {
+			return x.Line < y.Line
+		}
+		return x.Column < y.Column
+	})
+	return found
+}
+
+// ClearDiagnostics drops the findings of source (every source when empty)
+// for path (every file when empty)
+func (a *App) ClearDiagnostics(source string, path string) {
+	a.diagnosticsMu.Lock()
+	var cleared []diagnosticKey
+	for key := range a.diagnostics {
+		if (source == "" || key.source == source) && (path == "" || key.path == path) {
+			delete(a.diagnostics, key)
+			cleared = append(cleared, key)
+		}
+	}
+	a.diagnosticsMu.Unlock()
+	for _, key := range cleared {
+		a.emitDiagnosticsChanged(key, 0)
+	}
+}
+
+// storeDiagnostics fills in source, path and id, applies the project's
+// severity overrides, suppression comments and lint baseline, replaces the
+// report and returns the stored findings
+func (a *App) storeDiagnostics(source, path string, diagnostics []Diagnostic) []Diagnostic {
+	if path != "" {
+		path = filepath.Clean(path)
+	}
+	key := diagnosticKey{source: source, path: path}
+	list := make([]Diagnostic, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		d.Source = source
+		if d.Path == "" {
+			d.Path = path
+		}
+		if d.Severity == "" {
+			d.Severity = SeverityWarn
+		}
+		list = append(list, d)
+	}
+	list = a.filterDiagnostics(path, list, a.lintBaseline())
+	for i := range list {
+		list[i].ID = diagnosticID(list[i])
+	}
+
+	a.diagnosticsMu.Lock()
+	if len(list) == 0 {
+		delete(a.diagnostics, key)
+	} else {
+		a.diagnostics[key] = list
+	}
+	a.diagnosticsMu.Unlock()
+	a.emitDiagnosticsChanged(key, len(list))
+	return list
+}
+
+func (a *App) emitDiagnosticsChanged(key diagnosticKey, count int) {
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, EventDiagnosticsChanged, DiagnosticsChange{Source: key.source, Path: key.path, Count: count})
+	}
+}
+
+// diagnosticID is stable across runs as long as the finding doesn't move,
+// so the frontend can keep its selection when a file is re-checked
+func diagnosticID(d Diagnostic) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s\x00%s\x00%s\x00%d\x00%d\x00%s", d.Source, d.Path, d.Rule, d.Line, d.Column, d.Message)))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Sources of a DocTemplate
+const (
+	TemplateBuiltin = "builtin"
+	TemplateProject = "project"
+)
+
+// DocTemplate is a starting point for a new document
+type DocTemplate struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	Path   string `json:"path,omitempty"`
+}
+
+// builtinTemplates are always available. Project templates with the same
+// id take precedence.
+var builtinTemplates = map[string]struct {
+	name    string
+	content string
+}{
+	"article": {"Article", `= {title}
+:author: {author}
+:revdate: {date}
+:toc:
+
+== Introduction
+
+== Summary
+`},
+	"book-chapter": {"Book chapter", `[#{id}]
+= {title}
+
+[abstract]
+What the reader will learn in this chapter.
+
+== First section
+`},
+	"adr": {"Architecture decision record", `= ADR: {title}
+:revdate: {date}
+:author: {author}
+
+== Status
+
+Proposed
+
+== Context
+
+== Decision
+
+== Consequences
+`},
+	"release-notes": {"Release notes", `= Release notes: {title}
+:revdate: {date}
+
+== New features
+
+== Improvements
+
+== Fixed issues
+
+== Known issues
+`},
+}
+
+// templateVarExpr matches {name} attribute references
+var templateVarExpr = regexp.MustCompile(`\{([A-Za-z0-9_-]+)\}`)
+
+// templatesDir is the project's template folder, set by the
+// "templates_folder" setting
+func (a *App) templatesDir() string {
+	root := a.currentProjectRoot()
+	if root == "" {
+		return ""
+	}
+	return filepath.Join(root, a.projectSettingString("templates_folder", "templates"))
+}
+
+// ListTemplates returns the built-in templates and those in the project's
+// templates folder
+func (a *App) ListTemplates() ([]DocTemplate, error) {
+	byID := make(map[string]DocTemplate)
+	for id, t := range builtinTemplates {
+		byID[id] = DocTemplate{ID: id, Name: t.name, Source: TemplateBuiltin}
+	}
+
+	if dir := a.templatesDir(); dir != "" {
+		entries, err := os.ReadDir(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+			id := strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))
+			byID[id] = DocTemplate{
+				ID:     id,
+				Name:   id,
+				Source: TemplateProject,
+				Path:   filepath.Join(dir, e.Name()),
+			}
+		}
+	}
+
+	templates := make([]DocTemplate, 0, len(byID))
+	for _, t := range byID {
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].ID < templates[j].ID })
+	return templates, nil
+}
+
+// CreateFromTemplate writes a new document at destPath from a template,
+// substituting {title}, {author}, {date}, {id} and any entries of vars.
+// References to other attributes are left for the converter. When destPath
+// is a folder the file is named after the title, with the project's slug
+// settings.
+func (a *App) CreateFromTemplate(templateID string, destPath string, vars map[string]string) (string, error) {
+	content, err := a.templateContent(templateID)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(destPath); err == nil && info.IsDir() {
+		name := a.SlugifyTitle(vars["title"], "")
+		if name == "" {
+			return "", fmt.Errorf("enter a title to name the new document")
+		}
+		destPath = filepath.Join(destPath, name+".adoc")
+	}
+	if exists(destPath) {
+		return "", fmt.Errorf("%s already exists", destPath)
+	}
+
+	values := a.templateDefaults(destPath)
+	for k, v := range vars {
+		values[k] = v
+	}
+	content = templateVarExpr.ReplaceAllStringFunc(content, func(m string) string {
+		if v, ok := values[m[1:len(m)-1]]; ok {
+			return v
+		}
+		return m
+	})
+
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return "", err
+	}
+	data := normalizeLineEndings([]byte(content), a.lineEndingStyle())
+	if err := writeFileAtomic(destPath, data, 0644); err != nil {
+		return "", newFileError("create", destPath, err)
+	}
+
+	op := a.beginOperation("New " + filepath.Base(destPath))
+	op.created(destPath)
+	a.commitOperation(op)
+	a.emitTreeChanged()
+	return destPath, nil
+}
+
+func (a *App) templateContent(id string) (string, error) {
+	templates, err := a.ListTemplates()
+	if err != nil {
+		return "", err
+	}
+	for _, t := range templates {
+		if t.ID != id {
+			continue
+		}
+		if t.Source == TemplateBuiltin {
+			return builtinTemplates[id].content, nil
+		}
+		data, err := os.ReadFile(t.Path)
+		return string(data), err
+	}
+	return "", fmt.Errorf("template %q not found", id)
+}
+
+// templateDefaults derives variables from the destination and preferences
+func (a *App) templateDefaults(destPath string) map[string]string {
+	name := strings.TrimSuffix(filepath.Base(destPath), filepath.Ext(destPath))
+	title := strings.ReplaceAll(strings.ReplaceAll(name, "-", " "), "_", " ")
+	if r, size := utf8.DecodeRuneInString(title); size > 0 {
+		title = string(unicode.ToUpper(r)) + title[size:]
+	}
+	author, _ := a.projectSetting("author").(string)
+	return map[string]string{
+		"title":  title,
+		"author": author,
+		"date":   time.Now().Format("2006-01-02"),
+		"id":     a.SlugifyTitle(title, ""),
+	}
+}
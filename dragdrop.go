@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// EventFilesImported is emitted after files dropped on the window have been
+// copied into the project
+const EventFilesImported = "files:imported"
+
+// FileDropResult is the payload of EventFilesImported
+type FileDropResult struct {
+	X        int      `json:"x"`
+	Y        int      `json:"y"`
+	Imported []string `json:"imported"`
+	Skipped  []string `json:"skipped"`
+	Error    string   `json:"error,omitempty"`
+}
+
+var (
+	importDocumentExts = map[string]bool{".adoc": true, ".asciidoc": true, ".md": true, ".txt": true}
+	importImageExts    = map[string]bool{".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".webp": true}
+)
+
+// handleFileDrop is registered with the Wails runtime on startup
+func (a *App) handleFileDrop(x, y int, paths []string) {
+	result := FileDropResult{X: x, Y: y}
+	imported, skipped, err := a.importFiles(paths)
+	result.Imported = imported
+	result.Skipped = skipped
+	if err != nil {
+		result.Error = err.Error()
+	}
+	runtime.EventsEmit(a.ctx, EventFilesImported, result)
+}
+
+// ImportFiles copies documents and images into the current project and
+// returns the paths they were copied to
+func (a *App) ImportFiles(paths []string) ([]string, error) {
+	imported, _, err := a.importFiles(paths)
+	return imported, err
+}
+
+// importFiles copies documents into the "import_folder" project setting and
+// images into "import_image_folder". Other files are skipped.
+func (a *App) importFiles(paths []string) ([]string, []string, error) {
+	root := a.currentProjectRoot()
+	if root == "" {
+		return nil, paths, fmt.Errorf("no project open")
+	}
+
+	docDir := filepath.Join(root, a.projectSettingString("import_folder", ""))
+	imageDir := filepath.Join(root, a.projectSettingString("import_image_folder", "images"))
+
+	op := a.beginOperation("Import files")
+	defer a.commitOperation(op)
+
+	imported := []string{}
+	skipped := []string{}
+	for _, src := range paths {
+		ext := strings.ToLower(filepath.Ext(src))
+		var dir string
+		switch {
+		case importDocumentExts[ext]:
+			dir = docDir
+		case importImageExts[ext]:
+			dir = imageDir
+		default:
+			skipped = append(skipped, src)
+			continue
+		}
+
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return imported, skipped, err
+		}
+		dst := filepath.Join(dir, filepath.Base(src))
+		if exists(dst) {
+			dst = copyName(dst)
+		}
+		if err := copyFile(src, dst); err != nil {
+			return imported, skipped, err
+		}
+		op.created(dst)
+		imported = append(imported, dst)
+	}
+
+	if len(imported) > 0 {
+		a.emitTreeChanged()
+	}
+	return imported, skipped, nil
+}
+
+// projectSettingString is projectSetting for string values with a default
+func (a *App) projectSettingString(key, fallback string) string {
+	return a.projectSettingStringIn(a.currentProjectRoot(), key, fallback)
+}
+
+// projectSettingStringIn is projectSettingIn for string values with a
+// default
+func (a *App) projectSettingStringIn(root, key, fallback string) string {
+	if v, ok := a.projectSettingIn(root, key).(string); ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
+// projectFolder resolves a folder setting against root, refusing folders
+// outside the project
+func (a *App) projectFolder(root, key, fallback string) (string, error) {
+	rel := filepath.FromSlash(a.projectSettingStringIn(root, key, fallback))
+	dir := filepath.Join(root, rel)
+	if filepath.IsAbs(rel) || !isWithin(dir, root) {
+		return "", fmt.Errorf("the %s setting must be a folder inside the project", key)
+	}
+	return dir, nil
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+	_ "github.com/emersion/go-message/charset"
+	"github.com/emersion/go-message/mail"
+)
+
+// Email inbox settings and their defaults
+const (
+	defaultEmailInboxInterval = 15 * time.Minute
+	emailPasswordSecret       = "email-inbox-password"
+	emailInboxLastRunKey      = "email_inbox_last"
+	// maxEmailMessages bounds one poll so a flooded folder can't stall it
+	maxEmailMessages = 50
+)
+
+// EmailInboxResult describes a poll of the email inbox
+type EmailInboxResult struct {
+	Filed  []string `json:"filed"`
+	Errors []string `json:"errors"`
+}
+
+// runEmailInbox polls the mailbox on the schedule set by the
+// "email_inbox_*" settings until ctx is done
+func (a *App) runEmailInbox(ctx context.Context) {
+	ticker := time.NewTicker(snapshotCheckEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if a.emailInboxDue() {
+				_, _ = a.PollEmailInbox()
+			}
+		}
+	}
+}
+
+func (a *App) emailInboxDue() bool {
+	if db == nil {
+		return false
+	}
+	if enabled, _ := a.userSetting("email_inbox_enabled").(bool); !enabled {
+		return false
+	}
+	interval := defaultEmailInboxInterval
+	if v, ok := a.userSetting("email_inbox_interval_minutes").(float64); ok && v > 0 {
+		interval = time.Duration(v) * time.Minute
+	}
+	last, _ := db.GetAppState(emailInboxLastRunKey)
+	t, err := time.Parse(time.RFC3339, last)
+	return err != nil || time.Since(t) >= interval
+}
+
+// PollEmailInbox files unread messages from the "email_inbox_mailbox"
+// folder (INBOX by default) of "email_inbox_server" as AsciiDoc notes in
+// the "email_inbox_folder" project folder and marks them read. The IMAP
+// password is the "email-inbox-password" keychain secret. The account
+// settings are read from the user's own settings only, never from the
+// project config. Image attachments go to "import_image_folder", others
+// next to the note.
+func (a *App) PollEmailInbox() (*EmailInboxResult, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	root := a.currentProjectRoot()
+	if root == "" {
+		return nil, fmt.Errorf("no project open")
+	}
+	server := a.userSettingString("email_inbox_server", "")
+	username := a.userSettingString("email_inbox_username", "")
+	if server == "" || username == "" {
+		return nil, fmt.Errorf("set email_inbox_server and email_inbox_username to use the email inbox")
+	}
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "993")
+	}
+	password, err := getSecret(emailPasswordSecret)
+	if err != nil {
+		return nil, err
+	}
+	_ = db.SetAppState(emailInboxLastRunKey, time.Now().Format(time.RFC3339))
+
+	c, err := client.DialTLS(server, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", server, err)
+	}
+	defer c.Logout()
+	if err := c.Login(username, password); err != nil {
+		return nil, fmt.Errorf("email login failed: %w", err)
+	}
+	mailbox := a.userSettingString("email_inbox_mailbox", "INBOX")
+	if _, err := c.Select(mailbox, false); err != nil {
+		return nil, fmt.Errorf("failed to open mailbox %s: %w", mailbox, err)
+	}
+
+	criteria := imap.NewSearchCriteria()
+	criteria.WithoutFlags = []string{imap.SeenFlag}
+	uids, err := c.UidSearch(criteria)
+	if err != nil {
+		return nil, err
+	}
+	result := &EmailInboxResult{Filed: []string{}, Errors: []string{}}
+	if len(uids) == 0 {
+		return result, nil
+	}
+	if len(uids) > maxEmailMessages {
+		uids = uids[:maxEmailMessages]
+	}
+
+	fetch := new(imap.SeqSet)
+	fetch.AddNum(uids...)
+	section := &imap.BodySectionName{Peek: true}
+	messages := make(chan *imap.Message, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.UidFetch(fetch, []imap.FetchItem{section.FetchItem(), imap.FetchUid}, messages)
+	}()
+
+	op := a.beginOperation("File email")
+	filed := new(imap.SeqSet)
+	for msg := range messages {
+		body := msg.GetBody(section)
+		if body == nil {
+			continue
+		}
+		path, err := a.fileEmail(root, body, op)
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		result.Filed = append(result.Filed, path)
+		filed.AddNum(msg.Uid)
+	}
+	a.commitOperation(op)
+	if err := <-done; err != nil {
+		return result, err
+	}
+	if len(result.Filed) > 0 {
+		a.emitTreeChanged()
+		flags := []interface{}{imap.SeenFlag}
+		if err := c.UidStore(filed, imap.FormatFlagsOp(imap.AddFlags, true), flags, nil); err != nil {
+			return result, fmt.Errorf("filed %d messages but failed to mark them read: %w", len(result.Filed), err)
+		}
+	}
+	return result, nil
+}
+
+// fileEmail converts one message into a note with its attachments
+func (a *App) fileEmail(root string, r io.Reader, op *journalOp) (string, error) {
+	mr, err := mail.CreateReader(r)
+	if err != nil {
+		return "", fmt.Errorf("unreadable message: %w", err)
+	}
+	subject, _ := mr.Header.Subject()
+	if strings.TrimSpace(subject) == "" {
+		subject = "Email"
+	}
+	from := ""
+	if addrs, err := mr.Header.AddressList("From"); err == nil && len(addrs) > 0 {
+		from = addrs[0].String()
+	}
+	date, err := mr.Header.Date()
+	if err != nil || date.IsZero() {
+		date = time.Now()
+	}
+
+	dir, err := a.projectFolder(root, "email_inbox_folder", "inbox")
+	if err != nil {
+		return "", err
+	}
+	imageDir, err := a.projectFolder(root, "import_image_folder", "images")
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, a.noteName(subject, date)+".adoc")
+	if exists(path) {
+		path = copyName(path)
+	}
+
+	var text, html string
+	var images, files []string
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("unreadable message %q: %w", subject, err)
+		}
+		switch h := part.Header.(type) {
+		case *mail.InlineHeader:
+			contentType, _, _ := h.ContentType()
+			data, _ := io.ReadAll(part.Body)
+			switch {
+			case contentType == "text/plain" && text == "":
+				text = string(data)
+			case contentType == "text/html" && html == "":
+				html = string(data)
+			}
+		case *mail.AttachmentHeader:
+			name, _ := h.Filename()
+			name = attachmentNameExpr.ReplaceAllString(filepath.Base(strings.ReplaceAll(name, "\\", "/")), "-")
+			if strings.Trim(name, ".-") == "" {
+				contentType, _, _ := h.ContentType()
+				name = "attachment"
+				if exts, _ := mime.ExtensionsByType(contentType); len(exts) > 0 {
+					name += exts[0]
+				}
+			}
+			target := dir
+			isImage := importImageExts[strings.ToLower(filepath.Ext(name))]
+			if isImage {
+				target = imageDir
+			}
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return "", err
+			}
+			dst := filepath.Join(target, name)
+			if exists(dst) {
+				dst = copyName(dst)
+			}
+			data, err := io.ReadAll(part.Body)
+			if err != nil {
+				return "", err
+			}
+			if err := writeFileAtomic(dst, data, 0644); err != nil {
+				return "", newFileError("save", dst, err)
+			}
+			op.created(dst)
+			rel, _ := filepath.Rel(dir, dst)
+			if isImage {
+				images = append(images, filepath.ToSlash(rel))
+			} else {
+				files = append(files, filepath.ToSlash(rel))
+			}
+		}
+	}
+	if text == "" && html != "" {
+		text = htmlToText(html)
+	}
+
+	// Mail comes from anyone, so none of it is read as markup
+	var b strings.Builder
+	fmt.Fprintf(&b, "= %s\n", adocInline(subject))
+	if from != "" {
+		fmt.Fprintf(&b, ":email-from: %s\n", adocInline(from))
+	}
+	fmt.Fprintf(&b, ":email-date: %s\n\n", date.Format(time.RFC3339))
+	if body := strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n")); body != "" {
+		b.WriteString(adocLiteral(body) + "\n")
+	}
+	for _, img := range images {
+		fmt.Fprintf(&b, "image::%s[]\n\n", img)
+	}
+	if len(files) > 0 {
+		b.WriteString("== Attachments\n\n")
+		for _, f := range files {
+			fmt.Fprintf(&b, "* link:%s[%s]\n", f, filepath.Base(f))
+		}
+	}
+	if err := writeFileAtomic(path, []byte(strings.TrimRight(b.String(), "\n")+"\n"), 0644); err != nil {
+		return "", newFileError("save", path, err)
+	}
+	op.created(path)
+	return path, nil
+}
+
+// attachmentNameExpr matches what may not appear in an attachment's file
+// name, which ends up in image and link macros
+var attachmentNameExpr = regexp.MustCompile(`[^\pL\pN._-]+`)
+
+var (
+	htmlBreakExpr = regexp.MustCompile(`(?i)<br\s*/?>|</li>`)
+	htmlBlockExpr = regexp.MustCompile(`(?i)</p>|</div>|</h[1-6]>`)
+	htmlTagExpr   = regexp.MustCompile(`(?is)<(style|script)[^>]*>.*?</(style|script)>|<[^>]*>`)
+	blankRunExpr  = regexp.MustCompile(`\n{3,}`)
+)
+
+// htmlToText reduces an HTML mail body to plain paragraphs
+func htmlToText(html string) string {
+	text := htmlBlockExpr.ReplaceAllString(html, "\n\n")
+	text = htmlBreakExpr.ReplaceAllString(text, "\n")
+	text = htmlTagExpr.ReplaceAllString(text, "")
+	replacer := strings.NewReplacer("&nbsp;", " ", "&amp;", "&", "&lt;", "<", "&gt;", ">", "&quot;", `"`, "&#39;", "'")
+	return blankRunExpr.ReplaceAllString(replacer.Replace(text), "\n\n")
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+)
+
+// EmbeddingIndexResult summarizes an IndexProjectEmbeddings run
+type EmbeddingIndexResult struct {
+	Passages int `json:"passages"`
+	Updated  int `json:"updated"`
+	Removed  int `json:"removed"`
+}
+
+// Passage is an indexed section returned by similarity searches
+type Passage struct {
+	Path      string  `json:"path"`
+	SectionID string  `json:"sectionId"`
+	Title     string  `json:"title"`
+	Line      int     `json:"line"`
+	Content   string  `json:"content"`
+	Score     float64 `json:"score"`
+}
+
+// maxPassageLength keeps single passages within embedding input limits
+const maxPassageLength = 6000
+
+// IndexProjectEmbeddings embeds every section of every .adoc file in the
+// project. Sections whose text and embedding model haven't changed keep
+// their stored vectors.
+func (a *App) IndexProjectEmbeddings(projectPath string) (*EmbeddingIndexResult, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+
+	existing, err := db.GetEmbeddings(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	stored := make(map[string]EmbeddingRow, len(existing))
+	for _, r := range existing {
+		stored[r.Path+"#"+r.SectionID] = r
+	}
+
+	docs, err := globFiles(projectPath, "**/*.adoc")
+	if err != nil {
+		return nil, err
+	}
+	provider, err := a.newAIProvider()
+	if err != nil {
+		return nil, err
+	}
+	// Vectors of another model can't be compared, so the model is part
+	// of the hash and switching models re-embeds everything
+	model := provider.EmbeddingModel()
+
+	var changed []EmbeddingRow
+	seen := make(map[string]bool)
+	slug := a.slugOptions()
+	for _, doc := range docs {
+		content, err := os.ReadFile(doc)
+		if err != nil {
+			continue
+		}
+		for _, s := range parseSections(string(content), slug) {
+			if s.Body == "" {
+				continue
+			}
+			text, _ := truncateText(s.Title+"\n\n"+s.Body, maxPassageLength)
+			sum := sha256.Sum256([]byte(model + "\x00" + text))
+			row := EmbeddingRow{
+				Path:      doc,
+				SectionID: s.ID,
+				Title:     s.Title,
+				Line:      s.Line,
+				Content:   text,
+				Hash:      hex.EncodeToString(sum[:]),
+			}
+			key := row.Path + "#" + row.SectionID
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if old, ok := stored[key]; !ok || old.Hash != row.Hash || old.Line != row.Line {
+				changed = append(changed, row)
+			}
+		}
+	}
+
+	result := &EmbeddingIndexResult{Passages: len(seen), Updated: len(changed)}
+	if len(changed) > 0 {
+		texts := make([]string, len(changed))
+		for i, r := range changed {
+			texts[i] = r.Content
+		}
+		vectors, err := provider.Embed(a.ctx, texts)
+		if err != nil {
+			return nil, err
+		}
+		for i := range changed {
+			changed[i].Vector = vectors[i]
+			if err := db.SaveEmbedding(projectPath, changed[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for key, r := range stored {
+		if !seen[key] {
+			_ = db.DeleteEmbedding(projectPath, r.Path, r.SectionID)
+			result.Removed++
+		}
+	}
+	return result, nil
+}
+
+// SearchPassages returns the limit indexed passages closest to query
+func (a *App) SearchPassages(projectPath string, query string, limit int) ([]Passage, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+
+	provider, err := a.newAIProvider()
+	if err != nil {
+		return nil, err
+	}
+	vectors, err := provider.Embed(a.ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.GetEmbeddings(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	passages := make([]Passage, 0, len(rows))
+	for _, r := range rows {
+		passages = append(passages, Passage{
+			Path:      r.Path,
+			SectionID: r.SectionID,
+			Title:     r.Title,
+			Line:      r.Line,
+			Content:   r.Content,
+			Score:     cosineSimilarity(vectors[0], r.Vector),
+		})
+	}
+	sort.Slice(passages, func(i, j int) bool { return passages[i].Score > passages[j].Score })
+	if len(passages) > limit {
+		passages = passages[:limit]
+	}
+	return passages, nil
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
@@ -0,0 +1,431 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Export audiences
+const (
+	ExportAudiencePublic   = "public"
+	ExportAudienceInternal = "internal"
+)
+
+// defaultHiddenRoles mark the sections and blocks left out of public
+// exports, unless the "export_hidden_roles" setting lists others
+var defaultHiddenRoles = []string{"draft", "internal"}
+
+var (
+	adocAttrListExpr   = regexp.MustCompile(`^\[([^\[\]].*)\]$`)
+	adocBlockTitleExpr = regexp.MustCompile(`^\.[^.\s]`)
+	adocRoleAttrExpr   = regexp.MustCompile(`(?:^|,)\s*role\s*=\s*"?([^",]*)"?`)
+)
+
+// ExportSource is a document's source as an audience gets to see it
+type ExportSource struct {
+	Content string           `json:"content"`
+	Removed []RemovedContent `json:"removed"`
+}
+
+// RemovedContent is a section or block left out of a public export
+type RemovedContent struct {
+	// File is the included file it was in, empty for the document itself
+	File string `json:"file,omitempty"`
+	// Line is where its attribute list is in the original source
+	Line  int    `json:"line"`
+	Kind  string `json:"kind"`
+	Title string `json:"title,omitempty"`
+	Role  string `json:"role"`
+}
+
+// PrepareExportSource returns the source to hand the converter when
+// exporting a document for audience. Public exports (the default) lose
+// every section and block with a hidden role, such as
+//
+//	[.draft]
+//	== Upcoming features
+//
+// so nothing depends on authors remembering ifdef::internal[]. Included
+// files are stripped as well and inlined, so the converter never reads
+// them itself. Internal exports get the source unchanged.
+func (a *App) PrepareExportSource(path string, audience string) (*ExportSource, error) {
+	content, err := os.ReadFile(longPath(path))
+	if err != nil {
+		return nil, newFileError("read", path, err)
+	}
+	if audience == ExportAudienceInternal {
+		return &ExportSource{Content: string(content), Removed: []RemovedContent{}}, nil
+	}
+	root := a.currentProjectRoot()
+	cfg, _ := LoadProjectConfig(root)
+	attrs := headerAttributes(string(content))
+	for name, value := range projectAttributes(root, cfg) {
+		attrs[name] = value
+	}
+	return stripHiddenIncludes(path, string(content), attrs, a.hiddenRoles(), 0)
+}
+
+// maxIncludeDepth is how deep includes are followed, as in Asciidoctor
+const maxIncludeDepth = 64
+
+var adocIncludeAttrsExpr = regexp.MustCompile(`^include::([^\[]+)\[(.*)\]\s*$`)
+
+// stripHiddenIncludes strips the document at path and replaces its
+// include directives with the stripped content of the files they name.
+// Includes that select lines or tags are kept as they are unless the file
+// has hidden content, which can't be left out of a selection reliably.
+func stripHiddenIncludes(path, content string, attrs map[string]string, hidden map[string]bool, depth int) (*ExportSource, error) {
+	result := stripHiddenContent(content, hidden)
+	if depth >= maxIncludeDepth {
+		return result, nil
+	}
+	lines := strings.Split(result.Content, "\n")
+	for i, line := range lines {
+		m := adocIncludeAttrsExpr.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if m == nil {
+			continue
+		}
+		target := expandAttributes(m[1], attrs)
+		if strings.Contains(target, "{") || strings.Contains(target, "://") {
+			continue
+		}
+		file := filepath.FromSlash(target)
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(filepath.Dir(path), file)
+		}
+		data, err := os.ReadFile(longPath(file))
+		if err != nil {
+			// Left for the converter to report
+			continue
+		}
+		included, err := stripHiddenIncludes(file, string(data), attrs, hidden, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range included.Removed {
+			if r.File == "" {
+				r.File = file
+			}
+			result.Removed = append(result.Removed, r)
+		}
+
+		offset, whole := includeLevelOffset(m[2])
+		switch {
+		case !whole && len(included.Removed) > 0:
+			return nil, fmt.Errorf("%s is included with [%s] and has hidden content; include it whole or move the hidden content out", target, m[2])
+		case !whole:
+			continue
+		case offset == "":
+			lines[i] = strings.TrimSuffix(included.Content, "\n")
+		default:
+			lines[i] = ":leveloffset: " + offset + "\n" + strings.TrimSuffix(included.Content, "\n") + "\n\n:leveloffset!:"
+		}
+	}
+	result.Content = strings.Join(lines, "\n")
+	return result, nil
+}
+
+// includeLevelOffset reads an include's attribute list. whole is false
+// when it selects part of the file or changes how it is read; offset is
+// its leveloffset, if any.
+func includeLevelOffset(list string) (offset string, whole bool) {
+	for _, attr := range strings.Split(list, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(attr), "=")
+		switch strings.TrimSpace(name) {
+		case "":
+		case "leveloffset":
+			offset = strings.Trim(strings.TrimSpace(value), `"`)
+		case "opts", "options":
+			if strings.Trim(strings.TrimSpace(value), `"`) != "optional" {
+				return "", false
+			}
+		default:
+			return "", false
+		}
+	}
+	return offset, true
+}
+
+// presetAudience returns the audience of the named export preset, public
+// unless it says internal
+func presetAudience(projectPath, name string) (string, error) {
+	cfg, err := LoadProjectConfig(projectPath)
+	if err != nil {
+		return "", err
+	}
+	if cfg != nil {
+		for _, p := range cfg.ExportPresets {
+			if p.Name == name {
+				if p.Audience == ExportAudienceInternal {
+					return ExportAudienceInternal, nil
+				}
+				return ExportAudiencePublic, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("export preset %q not found", name)
+}
+
+// stripHiddenOutput removes hidden content from an exported file for a
+// public audience: sections and blocks of AsciiDoc sources, and the
+// elements of HTML pages whose class names a hidden role. Other files are
+// left alone.
+func stripHiddenOutput(path string, hidden map[string]bool) error {
+	var strip func([]byte) ([]byte, error)
+	switch ext := strings.ToLower(filepath.Ext(path)); {
+	case isPage(path):
+		strip = func(data []byte) ([]byte, error) {
+			return []byte(stripHiddenContent(string(data), hidden).Content), nil
+		}
+	case ext == ".html" || ext == ".htm":
+		strip = func(data []byte) ([]byte, error) { return stripHiddenHTML(data, hidden) }
+	default:
+		return nil
+	}
+	data, err := os.ReadFile(longPath(path))
+	if err != nil {
+		return newFileError("read", path, err)
+	}
+	stripped, err := strip(data)
+	if err != nil {
+		return fmt.Errorf("failed to strip %s: %w", path, err)
+	}
+	if bytes.Equal(stripped, data) {
+		return nil
+	}
+	if err := writeFileAtomic(path, stripped, 0644); err != nil {
+		return newFileError("write", path, err)
+	}
+	return nil
+}
+
+// stripHiddenTree runs stripHiddenOutput on every file under dir
+func stripHiddenTree(dir string, hidden map[string]bool) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		return stripHiddenOutput(path, hidden)
+	})
+}
+
+// stripHiddenHTML removes the elements Asciidoctor rendered from hidden
+// sections and blocks; their roles become class names, as in
+// <div class="sect1 draft">
+func stripHiddenHTML(data []byte, hidden map[string]bool) ([]byte, error) {
+	doc, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	removed := false
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; {
+			next := c.NextSibling
+			if c.Type == html.ElementNode && hasHiddenClass(c, hidden) {
+				n.RemoveChild(c)
+				removed = true
+			} else {
+				walk(c)
+			}
+			c = next
+		}
+	}
+	walk(doc)
+	if !removed {
+		return data, nil
+	}
+	var b bytes.Buffer
+	if err := html.Render(&b, doc); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func hasHiddenClass(n *html.Node, hidden map[string]bool) bool {
+	for _, attr := range n.Attr {
+		if attr.Key != "class" {
+			continue
+		}
+		for _, class := range strings.Fields(attr.Val) {
+			if hidden[class] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hiddenRoles reads the "export_hidden_roles" setting
+func (a *App) hiddenRoles() map[string]bool {
+	roles := map[string]bool{}
+	if list, ok := a.projectSetting("export_hidden_roles").([]interface{}); ok {
+		for _, v := range list {
+			if s, ok := v.(string); ok && s != "" {
+				roles[s] = true
+			}
+		}
+	}
+	if len(roles) == 0 {
+		for _, r := range defaultHiddenRoles {
+			roles[r] = true
+		}
+	}
+	return roles
+}
+
+// stripHiddenContent removes the sections (with their subsections),
+// delimited blocks and paragraphs whose attribute list has a hidden role
+func stripHiddenContent(content string, hidden map[string]bool) *ExportSource {
+	lines := strings.Split(content, "\n")
+	result := &ExportSource{Removed: []RemovedContent{}}
+	kept := make([]string, 0, len(lines))
+	delimiter := ""
+	// meta is where the attribute lists and titles before the current
+	// line start, role the hidden role among them
+	meta, role := -1, ""
+
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if delimiter != "" {
+			if trimmed == delimiter {
+				delimiter = ""
+			}
+			kept = append(kept, lines[i])
+			continue
+		}
+
+		if isBlockMetadata(trimmed) {
+			if meta < 0 {
+				meta, role = len(kept), ""
+			}
+			if r := hiddenRole(trimmed, hidden); r != "" && role == "" {
+				role = r
+			}
+			kept = append(kept, lines[i])
+			continue
+		}
+
+		if role != "" {
+			removed := RemovedContent{Line: i - (len(kept) - meta) + 1, Role: role}
+			for _, line := range kept[meta:] {
+				if t := strings.TrimSpace(line); adocBlockTitleExpr.MatchString(t) {
+					removed.Title = t[1:]
+				}
+			}
+			end := hiddenContentEnd(lines, i, &removed)
+			result.Removed = append(result.Removed, removed)
+			kept = kept[:meta]
+			meta, role = -1, ""
+			i = end - 1
+			continue
+		}
+		meta = -1
+
+		if isBlockDelimiter(trimmed) {
+			delimiter = trimmed
+			if strings.HasPrefix(trimmed, "```") {
+				delimiter = "```"
+			}
+		}
+		kept = append(kept, lines[i])
+	}
+	result.Content = strings.Join(kept, "\n")
+	return result
+}
+
+// hiddenContentEnd returns the index of the first line after the section,
+// block or paragraph starting at lines[start]
+func hiddenContentEnd(lines []string, start int, removed *RemovedContent) int {
+	first := strings.TrimSpace(lines[start])
+	if m := adocHeadingExpr.FindStringSubmatch(lines[start]); m != nil {
+		removed.Kind, removed.Title = "section", m[2]
+		level := len(m[1])
+		delimiter := ""
+		for i := start + 1; i < len(lines); i++ {
+			trimmed := strings.TrimSpace(lines[i])
+			switch {
+			case delimiter != "":
+				if trimmed == delimiter {
+					delimiter = ""
+				}
+			case isBlockDelimiter(trimmed):
+				delimiter = trimmed
+				if strings.HasPrefix(trimmed, "```") {
+					delimiter = "```"
+				}
+			default:
+				if m := adocHeadingExpr.FindStringSubmatch(lines[i]); m != nil && len(m[1]) <= level {
+					// Keep the next section's own attribute lists
+					for i > start+1 && isBlockMetadata(strings.TrimSpace(lines[i-1])) {
+						i--
+					}
+					return i
+				}
+			}
+		}
+		return len(lines)
+	}
+
+	removed.Kind = "block"
+	if isBlockDelimiter(first) {
+		delimiter := first
+		if strings.HasPrefix(first, "```") {
+			delimiter = "```"
+		}
+		for i := start + 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == delimiter {
+				return i + 1
+			}
+		}
+		return len(lines)
+	}
+	for i := start; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "" {
+			return i
+		}
+	}
+	return len(lines)
+}
+
+// isBlockMetadata reports whether a line is an attribute list, anchor or
+// block title, which belong to the block that follows
+func isBlockMetadata(trimmed string) bool {
+	return adocAttrListExpr.MatchString(trimmed) || adocAnchorExpr.MatchString(trimmed) || adocBlockTitleExpr.MatchString(trimmed)
+}
+
+// hiddenRole returns the first hidden role of an attribute list, given as
+// [.draft], [NOTE.internal] or [role="draft"]
+func hiddenRole(trimmed string, hidden map[string]bool) string {
+	m := adocAttrListExpr.FindStringSubmatch(trimmed)
+	if m == nil {
+		return ""
+	}
+	attrs := m[1]
+	var roles []string
+	if rm := adocRoleAttrExpr.FindStringSubmatch(attrs); rm != nil {
+		roles = append(roles, strings.Fields(rm[1])...)
+	}
+	first, _, _ := strings.Cut(attrs, ",")
+	if !strings.Contains(first, "=") {
+		for _, part := range strings.Split(first, ".")[1:] {
+			// Drop options and ids following the role, as in .draft%collapsible
+			part, _, _ = strings.Cut(part, "%")
+			part, _, _ = strings.Cut(part, "#")
+			roles = append(roles, part)
+		}
+	}
+	for _, r := range roles {
+		if hidden[r] {
+			return r
+		}
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// faqCorpusLimit caps how much source text is sent to the model
+const faqCorpusLimit = 60000
+
+// FAQDraft is a generated FAQ page
+type FAQDraft struct {
+	Content string   `json:"content"`
+	Sources []string `json:"sources"`
+}
+
+// GenerateFAQ drafts an AsciiDoc FAQ page from the documents at path (a
+// project folder or a single file). If the project's "faq_tickets_folder"
+// setting points at a folder of exported support tickets, their questions
+// are mined too. Answers link back to the sections they came from.
+func (a *App) GenerateFAQ(path string) (*FAQDraft, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	root := path
+	if !info.IsDir() {
+		root = filepath.Dir(path)
+	}
+
+	var docs []string
+	if info.IsDir() {
+		docs, err = globFiles(path, "**/*.adoc")
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		docs = []string{path}
+	}
+
+	var corpus strings.Builder
+	slug := a.slugOptions()
+	draft := &FAQDraft{Sources: []string{}}
+	for _, doc := range docs {
+		content, err := os.ReadFile(doc)
+		if err != nil {
+			continue
+		}
+		rel, _ := filepath.Rel(root, doc)
+		rel = filepath.ToSlash(rel)
+		sent := false
+		for _, s := range parseSections(string(content), slug) {
+			if s.Body == "" {
+				continue
+			}
+			entry := fmt.Sprintf("SOURCE xref:%s#%s[%s]\n%s\n\n", rel, s.ID, s.Title, s.Body)
+			if corpus.Len()+len(entry) > faqCorpusLimit {
+				break
+			}
+			corpus.WriteString(entry)
+			sent = true
+		}
+		// Only documents the model actually saw are sources
+		if sent {
+			draft.Sources = append(draft.Sources, doc)
+		}
+	}
+
+	project := a.currentProjectRoot()
+	if project == "" || !isWithin(path, project) {
+		project = root
+	}
+	tickets, err := a.faqTickets(project)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt := fmt.Sprintf(`You are a technical writer building an FAQ page.
+From the documentation excerpts below%s, identify the questions readers are
+most likely to ask and answer each one briefly using only the documentation.
+End every answer with the xref of the SOURCE it came from.
+Output ONLY an AsciiDoc page: a level-0 title "= Frequently Asked Questions",
+then one level-1 section per question, phrased as the question.
+
+Documentation:
+%s%s`, func() string {
+		if tickets != "" {
+			return " and the support tickets that follow them"
+		}
+		return ""
+	}(), corpus.String(), tickets)
+
+	draft.Content, err = a.generate(aiRequest{Model: a.aiModel(), Prompt: prompt, Action: "generateFAQ", Document: path})
+	if err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// faqTickets reads exported support tickets from the "faq_tickets_folder"
+// setting, a folder inside the project at projectPath
+func (a *App) faqTickets(projectPath string) (string, error) {
+	if a.projectSettingStringIn(projectPath, "faq_tickets_folder", "") == "" {
+		return "", nil
+	}
+	folder, err := a.projectFolder(projectPath, "faq_tickets_folder", "")
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("\nSupport tickets:\n")
+	_ = filepath.WalkDir(folder, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || b.Len() > faqCorpusLimit/3 {
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".txt", ".md", ".eml", ".csv", ".json":
+		default:
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		fmt.Fprintf(&b, "TICKET %s\n%s\n\n", d.Name(), content)
+		return nil
+	})
+	return b.String(), nil
+}
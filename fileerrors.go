@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// File error codes the frontend can act on
+const (
+	FileErrReadOnly         = "readOnly"
+	FileErrPermissionDenied = "permissionDenied"
+	FileErrNotFound         = "notFound"
+	FileErrConflict         = "conflict"
+	FileErrNotDownloaded    = "notDownloaded"
+	FileErrPathTooLong      = "pathTooLong"
+	FileErrIO               = "io"
+)
+
+// FileError is a file operation failure with a machine-readable code. It
+// reaches the frontend as an object (see errorFormatter in main.go) so the
+// UI can offer "Save As" or an elevation hint instead of a generic message.
+type FileError struct {
+	Op      string `json:"op"`
+	Path    string `json:"path"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+	// Conflict holds both versions when Code is FileErrConflict, so the
+	// UI can offer a merge without reading the file again
+	Conflict *SaveConflict `json:"conflict,omitempty"`
+	err      error
+}
+
+func (e *FileError) Error() string { return e.Message }
+
+func (e *FileError) Unwrap() error { return e.err }
+
+// newFileError classifies err into a FileError
+func newFileError(op, path string, err error) *FileError {
+	var fe *FileError
+	if errors.As(err, &fe) {
+		return fe
+	}
+
+	e := &FileError{Op: op, Path: path, Code: FileErrIO, Message: err.Error(), err: err}
+	switch {
+	case errors.Is(err, ErrSaveConflict):
+		e.Code = FileErrConflict
+		e.Hint = "Reload the file or overwrite it with your version"
+	case os.IsPermission(err):
+		e.Code = FileErrPermissionDenied
+		e.Hint = "You don't have permission to write here. Use Save As, or change the file's permissions"
+	case isPathTooLong(err):
+		e.Code = FileErrPathTooLong
+		e.Hint = "The path is too long for this drive. Move the project closer to the drive root or shorten folder names"
+	case os.IsNotExist(err):
+		e.Code = FileErrNotFound
+	}
+	return e
+}
+
+// errReadOnly is returned when saving over a read-only file
+func errReadOnly(op, path string) *FileError {
+	return &FileError{
+		Op:      op,
+		Path:    path,
+		Code:    FileErrReadOnly,
+		Message: path + " is read-only",
+		Hint:    "Use Save As to keep your changes in another file",
+	}
+}
+
+// IsWritable reports whether path can be saved: an existing file must not
+// be read-only, and a new file needs a writable parent directory
+func (a *App) IsWritable(path string) bool {
+	info, err := os.Stat(path)
+	if err == nil {
+		return !isReadOnly(path, info)
+	}
+	if !os.IsNotExist(err) {
+		return false
+	}
+
+	probe, err := os.CreateTemp(filepath.Dir(path), ".ndxcraft-probe-*")
+	if err != nil {
+		return false
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+	return true
+}
+
+// errorFormatter shapes errors returned by bound methods. FileErrors,
+// GitErrors, PreCommitErrors and ReleaseErrors are sent as objects,
+// everything else as its message.
+func errorFormatter(err error) any {
+	var fe *FileError
+	if errors.As(err, &fe) {
+		return fe
+	}
+	var ge *GitError
+	if errors.As(err, &ge) {
+		return ge
+	}
+	var pe *PreCommitError
+	if errors.As(err, &pe) {
+		return pe
+	}
+	var re *ReleaseError
+	if errors.As(err, &re) {
+		return re
+	}
+	return err.Error()
+}
@@ -0,0 +1,426 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// EventFileTreeChanged is emitted whenever a file operation changes the tree
+const EventFileTreeChanged = "filetree:changed"
+
+// FileMetadata describes a file on disk
+type FileMetadata struct {
+	Path        string    `json:"path"`
+	Size        int64     `json:"size"`
+	ModTime     time.Time `json:"modTime"`
+	Permissions string    `json:"permissions"`
+	IsDir       bool      `json:"isDir"`
+	ReadOnly    bool      `json:"readOnly"`
+	// Placeholder is set for cloud files that still need downloading
+	Placeholder bool `json:"placeholder"`
+}
+
+// GetFileMetadata returns size, modification time and permissions of path
+func (a *App) GetFileMetadata(path string) (*FileMetadata, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	return &FileMetadata{
+		Path:        path,
+		Size:        info.Size(),
+		ModTime:     info.ModTime(),
+		Permissions: info.Mode().Perm().String(),
+		IsDir:       info.IsDir(),
+		ReadOnly:    isReadOnly(path, info),
+		Placeholder: isCloudPlaceholder(path, info),
+	}, nil
+}
+
+// isReadOnly reports whether the current user can't write to path
+func isReadOnly(path string, info os.FileInfo) bool {
+	if info.Mode().Perm()&0200 == 0 {
+		return true
+	}
+	if info.IsDir() {
+		return false
+	}
+	// The mode bits don't tell the whole story (ACLs, other owners), so
+	// try opening for writing without truncating
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return os.IsPermission(err)
+	}
+	f.Close()
+	return false
+}
+
+// Base64File is a binary file encoded for transport to the frontend
+type Base64File struct {
+	Path     string `json:"path"`
+	MimeType string `json:"mimeType"`
+	Data     string `json:"data"`
+	Size     int64  `json:"size"`
+}
+
+// ReadFileBase64 reads a binary file (typically an image:: target) and
+// returns it base64 encoded along with its MIME type. A relative path is
+// taken as an image target of the document at documentPath and resolved
+// against its folder and imagesdir, as Asciidoctor does.
+func (a *App) ReadFileBase64(path string, documentPath string) (*Base64File, error) {
+	if !filepath.IsAbs(path) && documentPath != "" {
+		path = a.imageTargetPath(documentPath, path)
+	}
+	if cloudPlaceholder(path) {
+		return nil, errNotDownloaded("read", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Base64File{
+		Path:     path,
+		MimeType: detectMimeType(path, data),
+		Data:     base64.StdEncoding.EncodeToString(data),
+		Size:     int64(len(data)),
+	}, nil
+}
+
+// imageTargetPath resolves an image target of a document, using the
+// imagesdir set in the document or the project config
+func (a *App) imageTargetPath(documentPath, target string) string {
+	content, _ := os.ReadFile(longPath(documentPath))
+	root := a.currentProjectRoot()
+	cfg, _ := LoadProjectConfig(root)
+	doc := &lintDocument{Path: documentPath, Content: string(content), Attributes: projectAttributes(root, cfg)}
+	return referencePath(doc, adocReference{Kind: refImage, Target: target})
+}
+
+// detectMimeType prefers the extension (sniffing can't tell SVG from XML)
+// and falls back to content sniffing
+func detectMimeType(path string, data []byte) string {
+	if t := mime.TypeByExtension(strings.ToLower(filepath.Ext(path))); t != "" {
+		return t
+	}
+	return http.DetectContentType(data)
+}
+
+// RenameFile renames a file or directory and re-points any shadow copies
+// and session state at the new path. Renamed pages get redirects from
+// their old path.
+func (a *App) RenameFile(oldPath string, newPath string) error {
+	if err := a.renamePath(oldPath, newPath); err != nil {
+		return err
+	}
+	op := a.beginOperation("Rename " + filepath.Base(oldPath))
+	op.renamed(oldPath, newPath)
+	a.recordRenameRedirects(op, oldPath, newPath)
+	a.commitOperation(op)
+	return nil
+}
+
+// renamePath is RenameFile without recording it in the journal
+func (a *App) renamePath(oldPath string, newPath string) error {
+	if oldPath == "" || newPath == "" {
+		return fmt.Errorf("path must not be empty")
+	}
+	if _, err := os.Stat(oldPath); err != nil {
+		return err
+	}
+	caseOnly := isCaseOnlyRename(oldPath, newPath)
+	if exists(newPath) && !caseOnly {
+		return fmt.Errorf("%s already exists", newPath)
+	}
+
+	if caseOnly {
+		// Some case-insensitive file systems treat a direct rename as a
+		// no-op, so go through a temporary name
+		tmp := longPath(oldPath + ".ndxcraft-rename")
+		if err := os.Rename(longPath(oldPath), tmp); err != nil {
+			return newFileError("rename", oldPath, err)
+		}
+		if err := os.Rename(tmp, longPath(newPath)); err != nil {
+			os.Rename(tmp, longPath(oldPath))
+			return newFileError("rename", newPath, err)
+		}
+	} else if err := os.Rename(longPath(oldPath), longPath(newPath)); err != nil {
+		return newFileError("rename", newPath, err)
+	}
+	a.rebaseStamps(oldPath, newPath)
+
+	if db != nil {
+		if err := db.RenamePath(oldPath, newPath); err != nil {
+			return err
+		}
+	}
+	a.emitTreeChanged()
+	return nil
+}
+
+// isCaseOnlyRename reports whether newPath differs from oldPath only in
+// case and, on a case-insensitive file system, names the same file
+func isCaseOnlyRename(oldPath, newPath string) bool {
+	if oldPath == newPath || !strings.EqualFold(oldPath, newPath) {
+		return false
+	}
+	oldInfo, err := os.Stat(oldPath)
+	if err != nil {
+		return false
+	}
+	newInfo, err := os.Stat(newPath)
+	if os.IsNotExist(err) {
+		// Case-sensitive file system: an ordinary rename
+		return false
+	}
+	return err == nil && os.SameFile(oldInfo, newInfo)
+}
+
+// MoveFile moves a file or directory into dstDir, keeping its name. Moved
+// pages get redirects from their old path.
+func (a *App) MoveFile(src string, dstDir string) (string, error) {
+	info, err := os.Stat(dstDir)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", dstDir)
+	}
+
+	// Moving a folder into itself would orphan it
+	if _, inside := rebasePath(dstDir, src, src); inside {
+		return "", fmt.Errorf("cannot move %s into itself", src)
+	}
+
+	newPath := filepath.Join(dstDir, filepath.Base(src))
+	if err := a.renamePath(src, newPath); err != nil {
+		return "", err
+	}
+	op := a.beginOperation("Move " + filepath.Base(src))
+	op.renamed(src, newPath)
+	a.recordRenameRedirects(op, src, newPath)
+	a.commitOperation(op)
+	return newPath, nil
+}
+
+// DuplicateFile copies a document next to the original with a "copy" suffix
+// and returns the new path. preserveTimes carries over the modification time.
+func (a *App) DuplicateFile(path string, preserveTimes bool) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s is a directory", path)
+	}
+
+	newPath := copyName(path)
+	if err := copyFile(path, newPath); err != nil {
+		return "", err
+	}
+	op := a.beginOperation("Duplicate " + filepath.Base(path))
+	op.created(newPath)
+	defer a.commitOperation(op)
+
+	if err := os.Chmod(newPath, info.Mode().Perm()); err != nil {
+		return "", err
+	}
+	if preserveTimes {
+		if err := os.Chtimes(newPath, info.ModTime(), info.ModTime()); err != nil {
+			return "", err
+		}
+	}
+
+	a.emitTreeChanged()
+	return newPath, nil
+}
+
+// copyName finds a free "name copy.ext" / "name copy N.ext" next to path
+func copyName(path string) string {
+	dir := filepath.Dir(path)
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(filepath.Base(path), ext)
+
+	candidate := filepath.Join(dir, base+" copy"+ext)
+	for i := 2; exists(candidate); i++ {
+		candidate = filepath.Join(dir, fmt.Sprintf("%s copy %d%s", base, i, ext))
+	}
+	return candidate
+}
+
+// writeFileAtomic writes data to a temp file next to path and renames it over
+// the target, so a crash mid-write never leaves a truncated document. An
+// existing file keeps its mode; new files get perm.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	path = longPath(path)
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	// Only does anything if we bail out before the rename
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// ErrTrashUnavailable is returned when no system trash could be used.
+// The frontend can offer a forced (permanent) delete instead.
+var ErrTrashUnavailable = errors.New("system trash unavailable")
+
+// DeleteFile moves a file or directory to the project's trash, or to the
+// system trash when it is outside the open project. With force set the
+// path is removed permanently instead.
+func (a *App) DeleteFile(path string, force bool) error {
+	if path == "" {
+		return fmt.Errorf("path must not be empty")
+	}
+	if _, err := os.Lstat(path); err != nil {
+		return err
+	}
+
+	// A project trash on another device can't take a rename; fall back to
+	// the system trash then
+	if root := a.trashRootFor(path); root != "" && !force {
+		if err := a.moveToProjectTrash(root, path); err == nil {
+			return nil
+		}
+	}
+
+	// The backup is what makes the delete undoable, trash or not
+	op := a.beginOperation("Delete " + filepath.Base(path))
+	if err := op.deleting(path); err != nil {
+		return err
+	}
+
+	if force {
+		if err := os.RemoveAll(longPath(path)); err != nil {
+			op.discard()
+			return err
+		}
+	} else if err := moveToTrash(path); err != nil {
+		op.discard()
+		return fmt.Errorf("%w: %v", ErrTrashUnavailable, err)
+	}
+
+	if db != nil {
+		_ = db.ClearShadowPaths(path)
+	}
+	a.commitOperation(op)
+
+	a.emitTreeChanged()
+	return nil
+}
+
+// moveToTrash hands path to the platform's recycle bin
+func moveToTrash(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	switch goruntime.GOOS {
+	case "windows":
+		method := "DeleteFile"
+		if info, err := os.Stat(abs); err == nil && info.IsDir() {
+			method = "DeleteDirectory"
+		}
+		script := fmt.Sprintf(`Add-Type -AssemblyName Microsoft.VisualBasic; [Microsoft.VisualBasic.FileIO.FileSystem]::%s('%s', 'OnlyErrorDialogs', 'SendToRecycleBin')`,
+			method, strings.ReplaceAll(abs, "'", "''"))
+		return exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script).Run()
+	case "darwin":
+		script := fmt.Sprintf(`tell application "Finder" to delete POSIX file %q`, abs)
+		return exec.Command("osascript", "-e", script).Run()
+	default:
+		// gio ships with GLib on most desktops, trash-cli covers the rest
+		if _, err := exec.LookPath("gio"); err == nil {
+			return exec.Command("gio", "trash", abs).Run()
+		}
+		if _, err := exec.LookPath("trash-put"); err == nil {
+			return exec.Command("trash-put", abs).Run()
+		}
+		return fmt.Errorf("neither gio nor trash-put found")
+	}
+}
+
+// RevealInFileManager opens the platform file manager with path selected
+func (a *App) RevealInFileManager(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(abs); err != nil {
+		return err
+	}
+
+	switch goruntime.GOOS {
+	case "windows":
+		// explorer returns a non-zero exit code even on success
+		_ = exec.Command("explorer", "/select,", abs).Run()
+		return nil
+	case "darwin":
+		return exec.Command("open", "-R", abs).Start()
+	default:
+		// Most Linux file managers (Nautilus, Dolphin, Nemo) implement the
+		// FileManager1 D-Bus interface, which can select the item
+		uri := "file://" + filepath.ToSlash(abs)
+		err := exec.Command("dbus-send", "--session", "--print-reply",
+			"--dest=org.freedesktop.FileManager1", "/org/freedesktop/FileManager1",
+			"org.freedesktop.FileManager1.ShowItems", "array:string:"+uri, "string:").Run()
+		if err == nil {
+			return nil
+		}
+		// Fall back to just opening the containing folder
+		return exec.Command("xdg-open", filepath.Dir(abs)).Start()
+	}
+}
+
+func (a *App) emitTreeChanged() {
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, EventFileTreeChanged)
+	}
+}
+
+// rebasePath returns p with the oldRoot prefix replaced by newRoot.
+// The bool reports whether p was oldRoot itself or lived underneath it.
+func rebasePath(p, oldRoot, newRoot string) (string, bool) {
+	cleanP := filepath.Clean(p)
+	cleanOld := filepath.Clean(oldRoot)
+
+	if cleanP == cleanOld {
+		return newRoot, true
+	}
+	prefix := cleanOld + string(filepath.Separator)
+	if strings.HasPrefix(cleanP, prefix) {
+		return filepath.Join(newRoot, strings.TrimPrefix(cleanP, prefix)), true
+	}
+	return p, false
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Events emitted by StreamFile
+const (
+	EventFileChunk      = "file:chunk"
+	EventFileStreamDone = "file:stream:done"
+)
+
+// Chunk sizes for ranged and streamed reads
+const (
+	defaultChunkSize = 256 * 1024
+	maxChunkSize     = 4 * 1024 * 1024
+)
+
+// FileChunk is a piece of a file. Chunks always end on a UTF-8 boundary, so
+// Next may be slightly less than Offset plus the requested length.
+type FileChunk struct {
+	StreamID string `json:"streamId,omitempty"`
+	Path     string `json:"path"`
+	Offset   int64  `json:"offset"`
+	Next     int64  `json:"next"`
+	Size     int64  `json:"size"`
+	Data     string `json:"data"`
+	EOF      bool   `json:"eof"`
+}
+
+// FileStreamDone is the payload of EventFileStreamDone
+type FileStreamDone struct {
+	StreamID string `json:"streamId"`
+	Path     string `json:"path"`
+	Error    string `json:"error,omitempty"`
+}
+
+var (
+	streamsMu sync.Mutex
+	streams   = make(map[string]context.CancelFunc)
+)
+
+// ReadFileRange reads up to length bytes of path starting at offset,
+// without loading the rest of the file
+func (a *App) ReadFileRange(path string, offset int64, length int) (*FileChunk, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return readChunk(f, path, info.Size(), offset, length)
+}
+
+func readChunk(f *os.File, path string, size, offset int64, length int) (*FileChunk, error) {
+	if offset < 0 || offset > size {
+		return nil, fmt.Errorf("offset %d out of range (file is %d bytes)", offset, size)
+	}
+	if length <= 0 {
+		length = defaultChunkSize
+	}
+	length = min(length, maxChunkSize)
+
+	buf := make([]byte, length)
+	n, err := f.ReadAt(buf, offset)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	buf = buf[:n]
+
+	// Leave a rune split by the chunk boundary for the next chunk, unless
+	// the chunk is too small to hold it at all
+	if offset+int64(n) < size {
+		if cut := utf8Boundary(buf); cut > 0 {
+			buf = buf[:cut]
+		}
+	}
+
+	next := offset + int64(len(buf))
+	return &FileChunk{
+		Path:   path,
+		Offset: offset,
+		Next:   next,
+		Size:   size,
+		Data:   string(buf),
+		EOF:    next >= size,
+	}, nil
+}
+
+// utf8Boundary returns the length of buf without a trailing partial rune
+func utf8Boundary(buf []byte) int {
+	for i := len(buf) - 1; i >= 0 && i >= len(buf)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(buf[i]) {
+			if utf8.FullRune(buf[i:]) {
+				return len(buf)
+			}
+			return i
+		}
+	}
+	return len(buf)
+}
+
+// StreamFile reads path in the background and emits it as EventFileChunk
+// events, followed by EventFileStreamDone. The returned id can be passed
+// to CancelStream.
+func (a *App) StreamFile(path string, chunkSize int) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return "", err
+	}
+
+	id := uuid.New().String()
+	ctx, cancel := context.WithCancel(a.ctx)
+	streamsMu.Lock()
+	streams[id] = cancel
+	streamsMu.Unlock()
+
+	go func() {
+		defer f.Close()
+		defer func() {
+			streamsMu.Lock()
+			delete(streams, id)
+			streamsMu.Unlock()
+			cancel()
+		}()
+
+		done := FileStreamDone{StreamID: id, Path: path}
+		for offset := int64(0); ; {
+			if err := ctx.Err(); err != nil {
+				done.Error = err.Error()
+				break
+			}
+			chunk, err := readChunk(f, path, info.Size(), offset, chunkSize)
+			if err != nil {
+				done.Error = err.Error()
+				break
+			}
+			chunk.StreamID = id
+			runtime.EventsEmit(a.ctx, EventFileChunk, chunk)
+			if chunk.EOF || chunk.Next == offset {
+				break
+			}
+			offset = chunk.Next
+		}
+		runtime.EventsEmit(a.ctx, EventFileStreamDone, done)
+	}()
+	return id, nil
+}
+
+// CancelStream stops a running StreamFile
+func (a *App) CancelStream(streamID string) {
+	streamsMu.Lock()
+	cancel, ok := streams[streamID]
+	streamsMu.Unlock()
+	if ok {
+		cancel()
+	}
+}
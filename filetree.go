@@ -0,0 +1,436 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FileNode represents a file or directory in the file system
+type FileNode struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	IsDir   bool   `json:"isDir"`
+	Ignored bool   `json:"ignored,omitempty"`
+	// Unloaded marks a directory whose children were not read because of
+	// the depth limit; fetch them with GetDirectoryChildren
+	Unloaded bool `json:"unloaded,omitempty"`
+	// Error says why a directory's children are missing or incomplete
+	Error     string `json:"error,omitempty"`
+	IsSymlink bool   `json:"isSymlink,omitempty"`
+	// Target is the resolved path of a symlink
+	Target string `json:"target,omitempty"`
+	// IsRoot marks the top-level nodes of a multi-root workspace; Name is
+	// the root's label
+	IsRoot bool `json:"isRoot,omitempty"`
+	// Stats is set on directories when the fileTreeFolderStats preference
+	// is on; see GetFolderStats
+	Stats *FolderStats `json:"stats,omitempty"`
+	// Submodule marks a git submodule; see GitListSubmodules for its status
+	Submodule bool        `json:"submodule,omitempty"`
+	Children  []*FileNode `json:"children,omitempty"`
+}
+
+// FileNode error codes
+const (
+	TreeErrAccessDenied  = "accessDenied"
+	TreeErrNotDownloaded = "notDownloaded"
+	TreeErrUnreadable    = "unreadable"
+	TreeErrBrokenLink    = "brokenLink"
+	TreeErrSymlinkLoop   = "symlinkLoop"
+)
+
+// treeErrorCode classifies a directory read error for the UI
+func treeErrorCode(err error) string {
+	switch {
+	case os.IsPermission(err):
+		return TreeErrAccessDenied
+	case isCloudFileError(err):
+		return TreeErrNotDownloaded
+	default:
+		return TreeErrUnreadable
+	}
+}
+
+// treeOptions holds the preferences that shape a tree listing, read once
+// per request
+type treeOptions struct {
+	root        string
+	showHidden  bool
+	showIgnored bool
+	showAll     bool
+	include     []string
+	exclude     []string
+	sortMode    string
+	dirsFirst   bool
+	followLinks bool
+	// degraded batches stats for network drives
+	degraded    bool
+	folderStats bool
+	// stats holds the folder stats of every directory below the listed
+	// one, gathered in one walk on first use
+	stats map[string]*FolderStats
+	// submodules holds the absolute paths of git submodules
+	submodules map[string]bool
+	// visiting holds the resolved directories on the current path from the
+	// root, so a link back to one of them is not entered again
+	visiting map[string]bool
+}
+
+// File tree sort modes, set through the "fileTreeSort" preference
+const (
+	TreeSortName      = "name"
+	TreeSortModified  = "modified"
+	TreeSortExtension = "extension"
+)
+
+// defaultTreeInclude is used when the "fileTreeInclude" preference is unset
+var defaultTreeInclude = []string{"*.adoc", "*.txt", "*.md", "*.css"}
+
+func (a *App) newTreeOptions(root string) *treeOptions {
+	showHiddenRaw, _ := a.GetPreference("showHiddenFiles")
+	showHidden, _ := showHiddenRaw.(bool)
+	showIgnoredRaw, _ := a.GetPreference("showIgnoredFiles")
+	showIgnored, _ := showIgnoredRaw.(bool)
+
+	showAllRaw, _ := a.GetPreference("showAllFiles")
+	showAll, _ := showAllRaw.(bool)
+
+	include, ok := a.prefStrings("fileTreeInclude")
+	if !ok {
+		include = defaultTreeInclude
+	}
+	exclude, _ := a.prefStrings("fileTreeExclude")
+
+	sortModeRaw, _ := a.GetPreference("fileTreeSort")
+	sortMode, _ := sortModeRaw.(string)
+	dirsFirstRaw, _ := a.GetPreference("fileTreeDirsFirst")
+	dirsFirst, _ := dirsFirstRaw.(bool)
+	followLinksRaw, _ := a.GetPreference("followSymlinks")
+	followLinks, _ := followLinksRaw.(bool)
+	folderStatsRaw, _ := a.GetPreference("fileTreeFolderStats")
+	folderStats, _ := folderStatsRaw.(bool)
+
+	return &treeOptions{
+		root:        root,
+		showHidden:  showHidden,
+		showIgnored: showIgnored,
+		showAll:     showAll,
+		include:     include,
+		exclude:     exclude,
+		sortMode:    sortMode,
+		dirsFirst:   dirsFirst,
+		followLinks: followLinks,
+		degraded:    a.degraded(root),
+		folderStats: folderStats,
+		submodules:  submodulePaths(root),
+		visiting:    make(map[string]bool),
+	}
+}
+
+// prefStrings reads a preference holding a list of strings
+func (a *App) prefStrings(key string) ([]string, bool) {
+	v, _ := a.GetPreference(key)
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out, true
+}
+
+// wants reports whether an entry passes the include/exclude lists. Patterns
+// without a slash match the entry name, others the root-relative path.
+// Excludes apply to directories too; includes only to files. showAllFiles
+// bypasses both.
+func (o *treeOptions) wants(rel string, isDir bool) bool {
+	if o.showAll {
+		return true
+	}
+	if matchTreePattern(o.exclude, rel) {
+		return false
+	}
+	return isDir || matchTreePattern(o.include, rel)
+}
+
+func matchTreePattern(patterns []string, rel string) bool {
+	name := path.Base(rel)
+	for _, p := range patterns {
+		subject := name
+		if strings.Contains(p, "/") {
+			subject = rel
+		}
+		if ok, _ := path.Match(strings.ToLower(p), strings.ToLower(subject)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// GetFileTree returns the top level of the given directory; folders come
+// back Unloaded and are expanded with GetDirectoryChildren. For a
+// multi-root project it returns a forest: one IsRoot node per root, in the
+// project's order.
+func (a *App) GetFileTree(dirPath string) ([]*FileNode, error) {
+	if dirPath == "" {
+		dirPath = "./content"
+	}
+	if roots := workspaceRoots(dirPath); len(roots) > 0 {
+		return a.workspaceForest(roots), nil
+	}
+
+	// Create content dir if it doesn't exist (legacy behavior)
+	if dirPath == "./content" {
+		if _, err := os.Stat(dirPath); os.IsNotExist(err) {
+			_ = os.Mkdir(dirPath, 0755)
+		}
+	}
+
+	opts := a.newTreeOptions(dirPath)
+	ignore := (&ignoreMatcher{}).withDir(dirPath, "")
+	return partialTree(a.readDirRecursive(dirPath, opts, ignore, false, 1))
+}
+
+// GetDirectoryChildren lists a single directory for lazy tree expansion.
+// depth is how many levels to read (values below 1 read one level);
+// directories at the limit come back with Unloaded set. Ignore files are
+// read from the workspace root holding dirPath down to it, as for the
+// root's first level in GetFileTree.
+func (a *App) GetDirectoryChildren(dirPath string, depth int) ([]*FileNode, error) {
+	if depth < 1 {
+		depth = 1
+	}
+	dirPath = filepath.Clean(dirPath)
+	root := a.treeRootFor(dirPath)
+	opts := a.newTreeOptions(root)
+	for dir := filepath.Dir(dirPath); ; dir = filepath.Dir(dir) {
+		if real, err := filepath.EvalSymlinks(dir); err == nil {
+			opts.visiting[real] = true
+		}
+		if dir == root || dir == filepath.Dir(dir) {
+			break
+		}
+	}
+	ignore, ignored := ignoreMatcherFor(root, dirPath)
+	return partialTree(a.readDirRecursive(dirPath, opts, ignore, ignored, depth))
+}
+
+// partialTree drops the error of a top-level listing that was cut short,
+// since Wails can't return both; only a listing with nothing in it fails
+func partialTree(nodes []*FileNode, err error) ([]*FileNode, error) {
+	if err != nil && nodes != nil {
+		return nodes, nil
+	}
+	return nodes, err
+}
+
+// treeRootFor returns the root that ignore files and path patterns are
+// resolved against: the open project, or the workspace root, holding
+// dirPath
+func (a *App) treeRootFor(dirPath string) string {
+	root := a.currentProjectRoot()
+	if root == "" {
+		return dirPath
+	}
+	for _, r := range workspaceRoots(root) {
+		if isWithin(dirPath, r.Path) {
+			return filepath.Clean(r.Path)
+		}
+	}
+	if !isWithin(dirPath, root) {
+		return dirPath
+	}
+	return root
+}
+
+// ignoreMatcherFor collects the ignore rules that apply inside dir by
+// walking down from root, and reports whether dir itself is ignored
+func ignoreMatcherFor(root, dir string) (*ignoreMatcher, bool) {
+	m := (&ignoreMatcher{}).withDir(root, "")
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == "." {
+		return m, false
+	}
+
+	ignored := false
+	cur, curRel := root, ""
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		cur = filepath.Join(cur, part)
+		curRel = path.Join(curRel, part)
+		ignored = ignored || m.Match(curRel, true)
+		m = m.withDir(cur, curRel)
+	}
+	return m, ignored
+}
+
+// readDirRecursive lists dirPath and everything below it. Entries matched
+// by .gitignore/.ndxcraftignore are dropped, or kept and flagged when the
+// showIgnoredFiles preference is set. depth limits how many levels are
+// read; a negative depth reads everything. Unreadable subdirectories are
+// kept with their Error set, and a listing cut short by an error still
+// returns the entries read so far along with it. Symlinked directories are
+// only entered with the followSymlinks preference, and never when they
+// lead back to a directory being listed.
+func (a *App) readDirRecursive(dirPath string, opts *treeOptions, ignore *ignoreMatcher, parentIgnored bool, depth int) ([]*FileNode, error) {
+	entries, readErr := os.ReadDir(dirPath)
+	if readErr != nil && len(entries) == 0 {
+		return nil, readErr
+	}
+
+	if real, err := filepath.EvalSymlinks(dirPath); err == nil {
+		opts.visiting[real] = true
+		defer delete(opts.visiting, real)
+	}
+
+	opts.sortEntries(entries)
+
+	var nodes []*FileNode
+	for _, entry := range entries {
+		// Skip hidden files/dirs unless preference is set
+		if !opts.showHidden && strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(dirPath, entry.Name())
+		rel, _ := filepath.Rel(opts.root, path)
+		rel = filepath.ToSlash(rel)
+
+		isDir := entry.IsDir()
+		isLink := entry.Type()&fs.ModeSymlink != 0
+		target := ""
+		if isLink {
+			if resolved, err := filepath.EvalSymlinks(path); err == nil {
+				target = resolved
+				if info, err := os.Stat(resolved); err == nil {
+					isDir = info.IsDir()
+				}
+			}
+		}
+
+		if !opts.wants(rel, isDir) {
+			continue
+		}
+
+		ignored := parentIgnored || ignore.Match(rel, isDir)
+		if ignored && !opts.showIgnored {
+			continue
+		}
+
+		node := &FileNode{
+			Name:      entry.Name(),
+			Path:      path,
+			IsDir:     isDir,
+			Ignored:   ignored,
+			IsSymlink: isLink,
+			Target:    target,
+		}
+		if isLink && target == "" {
+			node.Error = TreeErrBrokenLink
+		}
+
+		if isDir {
+			node.Submodule = opts.submodules[path]
+			if opts.folderStats && !opts.degraded {
+				if opts.stats == nil {
+					opts.stats, _ = folderStats(dirPath, treeRel(opts.root, dirPath), ignore)
+				}
+				node.Stats = opts.stats[path]
+			}
+			switch {
+			case isLink && !opts.followLinks:
+				// Shown, but only entered when following links
+			case isLink && opts.visiting[target]:
+				node.Error = TreeErrSymlinkLoop
+			case depth == 1:
+				node.Unloaded = true
+			default:
+				children, err := a.readDirRecursive(path, opts, ignore.withDir(path, rel), ignored, depth-1)
+				node.Children = children
+				if err != nil {
+					node.Error = treeErrorCode(err)
+				}
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, readErr
+}
+
+// sortEntries orders a directory listing by the "fileTreeSort" mode, newest
+// first for "modified", with directories on top when "fileTreeDirsFirst"
+// is set. Ties fall back to the name.
+func (o *treeOptions) sortEntries(entries []fs.DirEntry) {
+	var modTimes map[string]int64
+	if o.sortMode == TreeSortModified {
+		modTimes = o.modTimes(entries)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if o.dirsFirst && a.IsDir() != b.IsDir() {
+			return a.IsDir()
+		}
+		switch o.sortMode {
+		case TreeSortModified:
+			if ta, tb := modTimes[a.Name()], modTimes[b.Name()]; ta != tb {
+				return ta > tb
+			}
+		case TreeSortExtension:
+			ea := strings.ToLower(filepath.Ext(a.Name()))
+			eb := strings.ToLower(filepath.Ext(b.Name()))
+			if ea != eb {
+				return ea < eb
+			}
+		}
+		return strings.ToLower(a.Name()) < strings.ToLower(b.Name())
+	})
+}
+
+// modTimes stats every entry; on network drives several at a time, since
+// each stat is a round-trip
+func (o *treeOptions) modTimes(entries []fs.DirEntry) map[string]int64 {
+	times := make([]int64, len(entries))
+	stat := func(i int) {
+		if info, err := entries[i].Info(); err == nil {
+			times[i] = info.ModTime().UnixNano()
+		}
+	}
+
+	if o.degraded {
+		var wg sync.WaitGroup
+		next := make(chan int)
+		for w := 0; w < statWorkers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range next {
+					stat(i)
+				}
+			}()
+		}
+		for i := range entries {
+			next <- i
+		}
+		close(next)
+		wg.Wait()
+	} else {
+		for i := range entries {
+			stat(i)
+		}
+	}
+
+	m := make(map[string]int64, len(entries))
+	for i, e := range entries {
+		m[e.Name()] = times[i]
+	}
+	return m
+}
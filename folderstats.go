@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FolderStats aggregates everything below a directory
+type FolderStats struct {
+	FileCount    int       `json:"fileCount"`
+	DirCount     int       `json:"dirCount"`
+	TotalSize    int64     `json:"totalSize"`
+	LastModified time.Time `json:"lastModified"`
+	// LastModifiedPath is the file LastModified comes from
+	LastModifiedPath string `json:"lastModifiedPath,omitempty"`
+}
+
+// GetFolderStats counts the files below path, their total size and the
+// most recent modification, so the tree can show large or stale modules.
+// Hidden directories (.git and the like) and files matched by
+// .gitignore/.ndxcraftignore are not counted.
+func (a *App) GetFolderStats(path string) (*FolderStats, error) {
+	root := a.treeRootFor(path)
+	ignore, _ := ignoreMatcherFor(root, path)
+	stats, err := folderStats(path, treeRel(root, path), ignore)
+	if err != nil {
+		return nil, err
+	}
+	return stats[filepath.Clean(path)], nil
+}
+
+// folderStats aggregates every directory below root, root included, in a
+// single walk. relRoot is root's slash-separated path relative to the tree
+// root the ignore rules apply to, and ignore holds the rules for root.
+func folderStats(root, relRoot string, ignore *ignoreMatcher) (map[string]*FolderStats, error) {
+	root = filepath.Clean(root)
+	info, err := os.Stat(longPath(root))
+	if err != nil {
+		return nil, newFileError("read", root, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a folder", root)
+	}
+
+	stats := map[string]*FolderStats{root: {}}
+	matchers := map[string]*ignoreMatcher{root: ignore}
+	// ancestors returns the stats of every directory from root down to dir
+	ancestors := func(dir string) []*FolderStats {
+		var list []*FolderStats
+		for {
+			if s, ok := stats[dir]; ok {
+				list = append(list, s)
+			}
+			if dir == root || dir == filepath.Dir(dir) {
+				return list
+			}
+			dir = filepath.Dir(dir)
+		}
+	}
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Count what can be read
+			if d != nil && d.IsDir() && p != root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if p == root {
+			return nil
+		}
+		dir := filepath.Dir(p)
+		rel := path.Join(relRoot, d.Name())
+		if dirRel, err := filepath.Rel(root, dir); err == nil && dirRel != "." {
+			rel = path.Join(relRoot, filepath.ToSlash(dirRel), d.Name())
+		}
+		ignore := matchers[dir]
+		if d.IsDir() {
+			if strings.HasPrefix(d.Name(), ".") || ignore.Match(rel, true) {
+				return filepath.SkipDir
+			}
+			for _, s := range ancestors(dir) {
+				s.DirCount++
+			}
+			stats[p] = &FolderStats{}
+			matchers[p] = ignore.withDir(p, rel)
+			return nil
+		}
+		if ignore.Match(rel, false) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		for _, s := range ancestors(dir) {
+			s.FileCount++
+			s.TotalSize += info.Size()
+			if info.ModTime().After(s.LastModified) {
+				s.LastModified = info.ModTime()
+				s.LastModifiedPath = p
+			}
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// treeRel returns dir relative to the tree root as a slash-separated path,
+// "" for the root itself
+func treeRel(root, dir string) string {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == "." {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
@@ -10,7 +10,7 @@ import { EditorMode, LeftTab, FileNode } from './types';
 import { Sidebar, PanelRight, Circle } from 'lucide-react';
 import ProjectListModal from './components/ProjectListModal';
 import { ReadFile, SaveFile, SelectFile, SelectSaveFile, ListFiles, OpenGitClient, OpenBrowser, SaveShadowFile, GetShadowFile, SaveAppState, GetAppState, HasCorruption, RestoreBackup, GetFileTree, ClearShadowFile, UpdateProjectLastOpened, GetDefaultProjectRoot, GetPreference, AddProject, GetGitIcons } from '../wailsjs/go/main/App';
-import { WindowFullscreen, WindowUnfullscreen, WindowIsFullscreen } from '../wailsjs/runtime/runtime';
+import { WindowFullscreen, WindowUnfullscreen, WindowIsFullscreen, EventsOn } from '../wailsjs/runtime/runtime';
 import { Badge } from "@/components/ui/badge";
 import { Button } from "@/components/ui/button";
 import { Input } from "@/components/ui/input";
@@ -141,6 +141,15 @@ const App: React.FC = () => {
     init();
   }, []);
 
+  // Follow projects the backend opens (imports, clones, relocations)
+  useEffect(() => {
+    return EventsOn("project:opened", (root: string) => {
+      if (root) {
+        setProjectRoot(root);
+      }
+    });
+  }, []);
+
   // Check Corruption
   useEffect(() => {
     const check = async () => {
@@ -3,11 +3,13 @@
  * 
  * Location: Inside the LeftSidebar (Files tab).
  * Purpose: Displays the project directory structure recursively. 
- *          Handles expanding/collapsing folders and selecting files.
+ *          Handles expanding/collapsing folders, loading their contents on
+ *          first expand, and selecting files.
  */
-import React, { useState } from 'react';
+import React, { useEffect, useState } from 'react';
 import { FileNode } from '../types';
 import { ChevronRight, ChevronDown, File, Folder } from 'lucide-react';
+import { GetDirectoryChildren } from '../../wailsjs/go/main/App';
 
 interface FileTreeProps {
   nodes: FileNode[];
@@ -46,10 +48,30 @@ interface FileTreeNodeProps {
 
 const FileTreeNode: React.FC<FileTreeNodeProps> = ({ node, onFileClick, level }) => {
   const [isOpen, setIsOpen] = useState(false);
+  // Folders are listed one level at a time; unloaded ones are read on first expand
+  const [children, setChildren] = useState<FileNode[] | undefined>(node.children);
 
-  const handleClick = (e: React.MouseEvent) => {
+  const loadChildren = async () => {
+    try {
+      // @ts-ignore
+      setChildren((await GetDirectoryChildren(node.path, 1)) || []);
+    } catch (err) {
+      console.error("Failed to list folder", err);
+    }
+  };
+
+  // A reloaded tree comes back unloaded again; re-read folders left open
+  useEffect(() => {
+    setChildren(node.children);
+    if (isOpen && node.unloaded) loadChildren();
+  }, [node]);
+
+  const handleClick = async (e: React.MouseEvent) => {
     e.stopPropagation();
     if (node.isDir) {
+      if (!isOpen && node.unloaded && !children) {
+        await loadChildren();
+      }
       setIsOpen(!isOpen);
     } else {
       onFileClick(node.path);
@@ -78,9 +100,9 @@ const FileTreeNode: React.FC<FileTreeNodeProps> = ({ node, onFileClick, level })
         </span>
       </div>
 
-      {node.isDir && isOpen && node.children && (
+      {node.isDir && isOpen && children && (
         <div className="animate-in slide-in-from-top-1 fade-in duration-200">
-          <FileTree nodes={node.children} onFileClick={onFileClick} level={level + 1} />
+          <FileTree nodes={children} onFileClick={onFileClick} level={level + 1} />
         </div>
       )}
     </div>
@@ -31,5 +31,6 @@ export interface FileNode {
   name: string;
   path: string;
   isDir: boolean;
+  unloaded?: boolean;
   children?: FileNode[];
 }
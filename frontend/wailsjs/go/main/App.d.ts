@@ -2,58 +2,346 @@
 // This file is automatically generated. DO NOT EDIT
 import {main} from '../models';
 
+export function AcceptReviewItem(arg1:string):Promise<void>;
+
 export function AddGitIcon(arg1:string):Promise<string>;
 
+export function AddGitignorePattern(arg1:string,arg2:string,arg3:string):Promise<void>;
+
 export function AddProject(arg1:string):Promise<void>;
 
+export function AddRedirect(arg1:string,arg2:string,arg3:string):Promise<Array<main.Redirect>>;
+
+export function ApplyLicenseHeader(arg1:string,arg2:string):Promise<main.LicenseHeaderResult>;
+
+export function ApplyQuickFix(arg1:string):Promise<string>;
+
+export function ApprovePublishTarget(arg1:string,arg2:string):Promise<void>;
+
+export function CancelStream(arg1:string):Promise<void>;
+
+export function CheckInclusiveLanguage(arg1:string):Promise<Array<main.Diagnostic>>;
+
+export function CheckLicenseHeaders(arg1:string,arg2:string):Promise<Array<main.Diagnostic>>;
+
+export function CheckProjectRoot(arg1:string):Promise<main.ProjectStatus>;
+
+export function CheckSaveConflict(arg1:string,arg2:string):Promise<main.SaveConflict>;
+
+export function CheckTone(arg1:string):Promise<main.ToneReport>;
+
+export function ClearDiagnostics(arg1:string,arg2:string):Promise<void>;
+
+export function ClearReviewBatch(arg1:string):Promise<void>;
+
 export function ClearShadowFile(arg1:string):Promise<void>;
 
+export function CopyPath(arg1:string,arg2:string,arg3:main.CopyOptions):Promise<main.CopyProgress>;
+
+export function CopyRemoteLink(arg1:string,arg2:number):Promise<string>;
+
+export function CreateFromTemplate(arg1:string,arg2:string,arg3:Record<string, string>):Promise<string>;
+
+export function CreatePullRequest(arg1:string,arg2:string,arg3:string,arg4:string):Promise<main.PullRequest>;
+
+export function DeleteAITemplate(arg1:string):Promise<void>;
+
+export function DeleteFile(arg1:string,arg2:boolean):Promise<void>;
+
 export function DeleteGitIcon(arg1:string):Promise<void>;
 
+export function DeleteGitToken(arg1:string,arg2:string):Promise<void>;
+
+export function DeleteSecret(arg1:string):Promise<void>;
+
+export function DetectInstalledTools():Promise<Array<main.InstalledTool>>;
+
+export function DetectLineEnding(arg1:string):Promise<string>;
+
+export function DuplicateFile(arg1:string,arg2:boolean):Promise<string>;
+
+export function EmptyTrash(arg1:string):Promise<void>;
+
+export function ExplainDiagnostic(arg1:main.Diagnostic):Promise<main.DiagnosticExplanation>;
+
+export function ExportAIHistory(arg1:string,arg2:string):Promise<string>;
+
+export function ExportCheckReport(arg1:string,arg2:string,arg3:string):Promise<main.CheckReportResult>;
+
+export function ExportGitIconPack(arg1:string,arg2:string):Promise<void>;
+
+export function ExportProjectArchive(arg1:string,arg2:string,arg3:main.ArchiveOptions):Promise<main.ArchiveProgress>;
+
+export function ExportRedirects(arg1:string,arg2:string,arg3:string):Promise<main.RedirectExport>;
+
+export function ExportReviewSchedule(arg1:string,arg2:string,arg3:string):Promise<main.ReviewSchedule>;
+
+export function FindContradictions(arg1:string,arg2:string):Promise<Array<main.Contradiction>>;
+
 export function FixGrammar(arg1:string):Promise<string>;
 
 export function GenerateContent(arg1:string,arg2:string):Promise<string>;
 
+export function GenerateFAQ(arg1:string):Promise<main.FAQDraft>;
+
+export function GenerateLintBaseline(arg1:string):Promise<main.LintBaselineResult>;
+
+export function GetAIHistory(arg1:string):Promise<Array<main.AIHistoryEntry>>;
+
+export function GetAISafetyOptions():Promise<main.AISafetyOptions>;
+
+export function GetAITemplates():Promise<Array<main.AITemplate>>;
+
 export function GetAllPreferences():Promise<Record<string, any>>;
 
 export function GetAppState(arg1:string):Promise<string>;
 
+export function GetAssetLicenseReport(arg1:string):Promise<main.AssetLicenseReport>;
+
+export function GetAssetLicenses(arg1:string):Promise<Array<main.AssetLicense>>;
+
+export function GetAttachmentPreview(arg1:string):Promise<main.AttachmentPreview>;
+
+export function GetConfigSchema():Promise<Record<string, any>>;
+
+export function GetConflictSections(arg1:string):Promise<Array<main.ConflictSection>>;
+
+export function GetContentSources(arg1:string):Promise<Array<main.ContentSourceStatus>>;
+
 export function GetDefaultProjectRoot():Promise<string>;
 
+export function GetDiagnostics(arg1:string,arg2:string):Promise<Array<main.Diagnostic>>;
+
+export function GetDirectoryChildren(arg1:string,arg2:number):Promise<Array<main.FileNode>>;
+
+export function GetFileMetadata(arg1:string):Promise<main.FileMetadata>;
+
 export function GetFileTree(arg1:string):Promise<Array<main.FileNode>>;
 
+export function GetFolderStats(arg1:string):Promise<main.FolderStats>;
+
+export function GetGitClientIcon():Promise<string>;
+
+export function GetGitClientIcons():Promise<Record<string, string>>;
+
+export function GetGitCredentials(arg1:string,arg2:string):Promise<main.GitCredentials>;
+
 export function GetGitIcons():Promise<Record<string, string>>;
 
+export function GetGitMergeState(arg1:string):Promise<main.GitMergeState>;
+
+export function GetGitStagedStatus(arg1:string):Promise<main.GitStagedStatus>;
+
+export function GetGitStatus(arg1:string):Promise<main.GitStatus>;
+
+export function GetGitignorePatterns(arg1:string):Promise<Array<string>>;
+
+export function GetOperationJournal():Promise<Array<main.JournalEntry>>;
+
+export function GetPendingOpenLocation():Promise<main.OpenLocation>;
+
+export function GetPerformanceProfile(arg1:string):Promise<main.PerformanceProfile>;
+
+export function GetPreCommitChecks(arg1:string):Promise<Array<string>>;
+
 export function GetPreference(arg1:string):Promise<any>;
 
+export function GetProjectConfig(arg1:string):Promise<main.ProjectConfig>;
+
+export function GetProjectDashboard(arg1:string):Promise<main.ProjectDashboard>;
+
+export function GetProjectRoots(arg1:string):Promise<Array<main.ProjectRoot>>;
+
+export function GetProjectSettings(arg1:string):Promise<Record<string, any>>;
+
 export function GetProjects():Promise<Array<main.Project>>;
 
+export function GetPublishTargets(arg1:string):Promise<Array<main.PublishTarget>>;
+
+export function GetQuickFixes(arg1:string):Promise<Array<main.QuickFix>>;
+
+export function GetRedirects(arg1:string):Promise<Array<main.Redirect>>;
+
+export function GetReleases(arg1:string):Promise<Array<main.DocsRelease>>;
+
+export function GetReviewQueue(arg1:string):Promise<Array<main.ReviewItem>>;
+
 export function GetShadowFile(arg1:string):Promise<Record<string, any>>;
 
+export function GetStyleProfile(arg1:string):Promise<main.StyleProfile>;
+
+export function GetWebClipperInfo():Promise<main.WebClipperInfo>;
+
+export function GitAddRemote(arg1:string,arg2:string,arg3:string):Promise<void>;
+
+export function GitBlame(arg1:string):Promise<Array<main.GitBlameLine>>;
+
+export function GitCheckout(arg1:string,arg2:string):Promise<void>;
+
+export function GitClone(arg1:string,arg2:string):Promise<string>;
+
+export function GitCommit(arg1:string,arg2:string,arg3:Array<string>):Promise<string>;
+
+export function GitCreateBranch(arg1:string,arg2:string,arg3:boolean):Promise<void>;
+
+export function GitCreateTag(arg1:string,arg2:string,arg3:string):Promise<main.GitTag>;
+
+export function GitDiffFile(arg1:string,arg2:boolean):Promise<main.GitDiff>;
+
+export function GitDiscardChanges(arg1:string,arg2:string):Promise<string>;
+
+export function GitFetch(arg1:string):Promise<main.GitTracking>;
+
+export function GitFileLog(arg1:string,arg2:number,arg3:number):Promise<Array<main.GitLogEntry>>;
+
+export function GitInit(arg1:string):Promise<void>;
+
+export function GitListBranches(arg1:string):Promise<Array<main.GitBranch>>;
+
+export function GitListRemotes(arg1:string):Promise<Array<main.GitRemote>>;
+
+export function GitListSubmodules(arg1:string):Promise<Array<main.GitSubmodule>>;
+
+export function GitListTags(arg1:string):Promise<Array<main.GitTag>>;
+
+export function GitPull(arg1:string,arg2:string):Promise<main.GitSyncResult>;
+
+export function GitPush(arg1:string,arg2:string):Promise<main.GitSyncResult>;
+
+export function GitSetRemoteURL(arg1:string,arg2:string,arg3:string):Promise<void>;
+
+export function GitShowFileAtCommit(arg1:string,arg2:string):Promise<string>;
+
+export function GitStage(arg1:Array<string>):Promise<void>;
+
+export function GitStashList(arg1:string):Promise<Array<main.GitStashEntry>>;
+
+export function GitStashPop(arg1:string,arg2:number):Promise<void>;
+
+export function GitStashSave(arg1:string,arg2:string):Promise<void>;
+
+export function GitSubmoduleUpdate(arg1:string):Promise<void>;
+
+export function GitTrackingStatus(arg1:string):Promise<main.GitTracking>;
+
+export function GitUnstage(arg1:Array<string>):Promise<void>;
+
 export function Greet(arg1:string):Promise<string>;
 
 export function HasCorruption():Promise<boolean>;
 
+export function HasSecret(arg1:string):Promise<boolean>;
+
+export function HydrateFile(arg1:string):Promise<void>;
+
+export function ImportFiles(arg1:Array<string>):Promise<Array<string>>;
+
+export function ImportGitIconPack(arg1:string):Promise<main.GitIconPackImport>;
+
+export function ImportProject(arg1:string,arg2:string):Promise<string>;
+
+export function IndexProjectEmbeddings(arg1:string):Promise<main.EmbeddingIndexResult>;
+
+export function IsGitHubDesktopInstalled():Promise<boolean>;
+
+export function IsSafeMode():Promise<boolean>;
+
+export function IsWritable(arg1:string):Promise<boolean>;
+
+export function LintFile(arg1:string):Promise<Array<main.Diagnostic>>;
+
+export function LintProject(arg1:string):Promise<Array<main.Diagnostic>>;
+
+export function ListCloudBackups():Promise<Array<main.CloudBackup>>;
+
 export function ListFiles(arg1:string):Promise<Array<string>>;
 
+export function ListTemplates():Promise<Array<main.DocTemplate>>;
+
+export function ListTrash(arg1:string):Promise<Array<main.TrashItem>>;
+
+export function MarkExport(arg1:string,arg2:main.ExportMarkingOptions):Promise<string>;
+
+export function MoveFile(arg1:string,arg2:string):Promise<string>;
+
 export function OpenBrowser(arg1:string):Promise<void>;
 
 export function OpenGitClient(arg1:string):Promise<boolean>;
 
+export function OpenInTool(arg1:string,arg2:string):Promise<void>;
+
+export function OpenRemoteFile(arg1:string,arg2:number):Promise<string>;
+
+export function OverwriteFile(arg1:string,arg2:string):Promise<void>;
+
+export function PollEmailInbox():Promise<main.EmailInboxResult>;
+
+export function PrepareDiscardChanges(arg1:string):Promise<main.DiscardPreview>;
+
+export function PrepareExportSource(arg1:string,arg2:string):Promise<main.ExportSource>;
+
+export function PreviewFile(arg1:string):Promise<main.FilePreview>;
+
 export function ReadFile(arg1:string):Promise<string>;
 
+export function ReadFileBase64(arg1:string,arg2:string):Promise<main.Base64File>;
+
+export function ReadFileRange(arg1:string,arg2:number,arg3:number):Promise<main.FileChunk>;
+
+export function RedoLastOperation():Promise<main.JournalEntry>;
+
+export function RejectReviewItem(arg1:string):Promise<void>;
+
+export function ReleaseDocs(arg1:string,arg2:string):Promise<main.DocsRelease>;
+
+export function RelocateProject(arg1:string,arg2:string):Promise<void>;
+
+export function RemoveAssetLicense(arg1:string,arg2:string):Promise<void>;
+
 export function RemoveProject(arg1:string):Promise<void>;
 
+export function RemoveRedirect(arg1:string,arg2:string):Promise<Array<main.Redirect>>;
+
+export function RenameFile(arg1:string,arg2:string):Promise<void>;
+
+export function ReportDiagnostics(arg1:string,arg2:string,arg3:Array<main.Diagnostic>):Promise<void>;
+
+export function ResetWebClipperToken():Promise<main.WebClipperInfo>;
+
+export function ResolveInclude(arg1:string,arg2:string):Promise<string>;
+
 export function RestoreBackup():Promise<void>;
 
+export function RestoreFromCloudBackup(arg1:string):Promise<void>;
+
+export function RestoreFromTrash(arg1:string):Promise<string>;
+
+export function RevealInFileManager(arg1:string):Promise<void>;
+
+export function RunAIBatch(arg1:string,arg2:string):Promise<string>;
+
+export function RunCloudBackup():Promise<main.CloudBackup>;
+
+export function RunPreCommitChecks(arg1:string,arg2:Array<string>):Promise<Array<main.PreCommitResult>>;
+
+export function SaveAITemplate(arg1:main.AITemplate):Promise<string>;
+
+export function SaveAll(arg1:Array<main.FileContent>):Promise<Array<main.SaveResult>>;
+
 export function SaveAppState(arg1:string,arg2:string):Promise<void>;
 
 export function SaveFile(arg1:string,arg2:string):Promise<void>;
 
 export function SavePreference(arg1:string,arg2:any):Promise<void>;
 
+export function SaveProjectSetting(arg1:string,arg2:string,arg3:any):Promise<void>;
+
 export function SaveShadowFile(arg1:string,arg2:string,arg3:boolean):Promise<void>;
 
+export function SaveStyleProfile(arg1:string,arg2:main.StyleProfile):Promise<void>;
+
+export function SearchPassages(arg1:string,arg2:string,arg3:number):Promise<Array<main.Passage>>;
+
 export function SelectCssFile():Promise<string>;
 
 export function SelectDirectory():Promise<string>;
@@ -66,4 +354,48 @@ export function SelectSaveFile():Promise<string>;
 
 export function SelectSvgFile():Promise<string>;
 
+export function SetAssetLicense(arg1:string,arg2:main.AssetLicense):Promise<void>;
+
+export function SetGitClientIcon(arg1:string,arg2:string):Promise<void>;
+
+export function SetGitSSHKey(arg1:string,arg2:string):Promise<void>;
+
+export function SetGitToken(arg1:string,arg2:string,arg3:string,arg4:string):Promise<void>;
+
+export function SetPreCommitChecks(arg1:string,arg2:Array<string>):Promise<void>;
+
+export function SetProjectRoots(arg1:string,arg2:Array<main.ProjectRoot>):Promise<void>;
+
+export function SetSecret(arg1:string,arg2:string):Promise<void>;
+
+export function SignPDF(arg1:string,arg2:main.PDFSignOptions):Promise<main.PDFSignature>;
+
+export function SlugifyTitle(arg1:string,arg2:string):Promise<string>;
+
+export function SnapshotShadowFiles():Promise<main.SnapshotResult>;
+
+export function StartWebClipper(arg1:number):Promise<main.WebClipperInfo>;
+
+export function StopWebClipper():Promise<void>;
+
+export function StreamFile(arg1:string,arg2:number):Promise<string>;
+
+export function SuggestDiagramSource(arg1:string):Promise<main.DiagramSuggestion>;
+
+export function SuggestTitles(arg1:string,arg2:number):Promise<main.TitleSuggestions>;
+
+export function SyncContentSources(arg1:string):Promise<Array<main.ContentSourceStatus>>;
+
+export function TestGitConnection(arg1:string,arg2:string):Promise<main.GitConnectionTest>;
+
+export function TestPublishTarget(arg1:string,arg2:string):Promise<main.PublishTargetTest>;
+
+export function ToggleGitignorePath(arg1:string,arg2:string):Promise<boolean>;
+
+export function UndoLastOperation():Promise<main.JournalEntry>;
+
+export function UpdateGitIcon(arg1:string,arg2:string):Promise<void>;
+
 export function UpdateProjectLastOpened(arg1:string):Promise<void>;
+
+export function ValidateProjectConfig(arg1:string):Promise<Array<main.ConfigIssue>>;
@@ -2,22 +2,158 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AcceptReviewItem(arg1) {
+  return window['go']['main']['App']['AcceptReviewItem'](arg1);
+}
+
 export function AddGitIcon(arg1) {
   return window['go']['main']['App']['AddGitIcon'](arg1);
 }
 
+export function AddGitignorePattern(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AddGitignorePattern'](arg1, arg2, arg3);
+}
+
 export function AddProject(arg1) {
   return window['go']['main']['App']['AddProject'](arg1);
 }
 
+export function AddRedirect(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AddRedirect'](arg1, arg2, arg3);
+}
+
+export function ApplyLicenseHeader(arg1, arg2) {
+  return window['go']['main']['App']['ApplyLicenseHeader'](arg1, arg2);
+}
+
+export function ApplyQuickFix(arg1) {
+  return window['go']['main']['App']['ApplyQuickFix'](arg1);
+}
+
+export function ApprovePublishTarget(arg1, arg2) {
+  return window['go']['main']['App']['ApprovePublishTarget'](arg1, arg2);
+}
+
+export function CancelStream(arg1) {
+  return window['go']['main']['App']['CancelStream'](arg1);
+}
+
+export function CheckInclusiveLanguage(arg1) {
+  return window['go']['main']['App']['CheckInclusiveLanguage'](arg1);
+}
+
+export function CheckLicenseHeaders(arg1, arg2) {
+  return window['go']['main']['App']['CheckLicenseHeaders'](arg1, arg2);
+}
+
+export function CheckProjectRoot(arg1) {
+  return window['go']['main']['App']['CheckProjectRoot'](arg1);
+}
+
+export function CheckSaveConflict(arg1, arg2) {
+  return window['go']['main']['App']['CheckSaveConflict'](arg1, arg2);
+}
+
+export function CheckTone(arg1) {
+  return window['go']['main']['App']['CheckTone'](arg1);
+}
+
+export function ClearDiagnostics(arg1, arg2) {
+  return window['go']['main']['App']['ClearDiagnostics'](arg1, arg2);
+}
+
+export function ClearReviewBatch(arg1) {
+  return window['go']['main']['App']['ClearReviewBatch'](arg1);
+}
+
 export function ClearShadowFile(arg1) {
   return window['go']['main']['App']['ClearShadowFile'](arg1);
 }
 
+export function CopyPath(arg1, arg2, arg3) {
+  return window['go']['main']['App']['CopyPath'](arg1, arg2, arg3);
+}
+
+export function CopyRemoteLink(arg1, arg2) {
+  return window['go']['main']['App']['CopyRemoteLink'](arg1, arg2);
+}
+
+export function CreateFromTemplate(arg1, arg2, arg3) {
+  return window['go']['main']['App']['CreateFromTemplate'](arg1, arg2, arg3);
+}
+
+export function CreatePullRequest(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['CreatePullRequest'](arg1, arg2, arg3, arg4);
+}
+
+export function DeleteAITemplate(arg1) {
+  return window['go']['main']['App']['DeleteAITemplate'](arg1);
+}
+
+export function DeleteFile(arg1, arg2) {
+  return window['go']['main']['App']['DeleteFile'](arg1, arg2);
+}
+
 export function DeleteGitIcon(arg1) {
   return window['go']['main']['App']['DeleteGitIcon'](arg1);
 }
 
+export function DeleteGitToken(arg1, arg2) {
+  return window['go']['main']['App']['DeleteGitToken'](arg1, arg2);
+}
+
+export function DeleteSecret(arg1) {
+  return window['go']['main']['App']['DeleteSecret'](arg1);
+}
+
+export function DetectInstalledTools() {
+  return window['go']['main']['App']['DetectInstalledTools']();
+}
+
+export function DetectLineEnding(arg1) {
+  return window['go']['main']['App']['DetectLineEnding'](arg1);
+}
+
+export function DuplicateFile(arg1, arg2) {
+  return window['go']['main']['App']['DuplicateFile'](arg1, arg2);
+}
+
+export function EmptyTrash(arg1) {
+  return window['go']['main']['App']['EmptyTrash'](arg1);
+}
+
+export function ExplainDiagnostic(arg1) {
+  return window['go']['main']['App']['ExplainDiagnostic'](arg1);
+}
+
+export function ExportAIHistory(arg1, arg2) {
+  return window['go']['main']['App']['ExportAIHistory'](arg1, arg2);
+}
+
+export function ExportCheckReport(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportCheckReport'](arg1, arg2, arg3);
+}
+
+export function ExportGitIconPack(arg1, arg2) {
+  return window['go']['main']['App']['ExportGitIconPack'](arg1, arg2);
+}
+
+export function ExportProjectArchive(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportProjectArchive'](arg1, arg2, arg3);
+}
+
+export function ExportRedirects(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportRedirects'](arg1, arg2, arg3);
+}
+
+export function ExportReviewSchedule(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportReviewSchedule'](arg1, arg2, arg3);
+}
+
+export function FindContradictions(arg1, arg2) {
+  return window['go']['main']['App']['FindContradictions'](arg1, arg2);
+}
+
 export function FixGrammar(arg1) {
   return window['go']['main']['App']['FixGrammar'](arg1);
 }
@@ -26,6 +162,26 @@ export function GenerateContent(arg1, arg2) {
   return window['go']['main']['App']['GenerateContent'](arg1, arg2);
 }
 
+export function GenerateFAQ(arg1) {
+  return window['go']['main']['App']['GenerateFAQ'](arg1);
+}
+
+export function GenerateLintBaseline(arg1) {
+  return window['go']['main']['App']['GenerateLintBaseline'](arg1);
+}
+
+export function GetAIHistory(arg1) {
+  return window['go']['main']['App']['GetAIHistory'](arg1);
+}
+
+export function GetAISafetyOptions() {
+  return window['go']['main']['App']['GetAISafetyOptions']();
+}
+
+export function GetAITemplates() {
+  return window['go']['main']['App']['GetAITemplates']();
+}
+
 export function GetAllPreferences() {
   return window['go']['main']['App']['GetAllPreferences']();
 }
@@ -34,30 +190,266 @@ export function GetAppState(arg1) {
   return window['go']['main']['App']['GetAppState'](arg1);
 }
 
+export function GetAssetLicenseReport(arg1) {
+  return window['go']['main']['App']['GetAssetLicenseReport'](arg1);
+}
+
+export function GetAssetLicenses(arg1) {
+  return window['go']['main']['App']['GetAssetLicenses'](arg1);
+}
+
+export function GetAttachmentPreview(arg1) {
+  return window['go']['main']['App']['GetAttachmentPreview'](arg1);
+}
+
+export function GetConfigSchema() {
+  return window['go']['main']['App']['GetConfigSchema']();
+}
+
+export function GetConflictSections(arg1) {
+  return window['go']['main']['App']['GetConflictSections'](arg1);
+}
+
+export function GetContentSources(arg1) {
+  return window['go']['main']['App']['GetContentSources'](arg1);
+}
+
 export function GetDefaultProjectRoot() {
   return window['go']['main']['App']['GetDefaultProjectRoot']();
 }
 
+export function GetDiagnostics(arg1, arg2) {
+  return window['go']['main']['App']['GetDiagnostics'](arg1, arg2);
+}
+
+export function GetDirectoryChildren(arg1, arg2) {
+  return window['go']['main']['App']['GetDirectoryChildren'](arg1, arg2);
+}
+
+export function GetFileMetadata(arg1) {
+  return window['go']['main']['App']['GetFileMetadata'](arg1);
+}
+
 export function GetFileTree(arg1) {
   return window['go']['main']['App']['GetFileTree'](arg1);
 }
 
+export function GetFolderStats(arg1) {
+  return window['go']['main']['App']['GetFolderStats'](arg1);
+}
+
+export function GetGitClientIcon() {
+  return window['go']['main']['App']['GetGitClientIcon']();
+}
+
+export function GetGitClientIcons() {
+  return window['go']['main']['App']['GetGitClientIcons']();
+}
+
+export function GetGitCredentials(arg1, arg2) {
+  return window['go']['main']['App']['GetGitCredentials'](arg1, arg2);
+}
+
 export function GetGitIcons() {
   return window['go']['main']['App']['GetGitIcons']();
 }
 
+export function GetGitMergeState(arg1) {
+  return window['go']['main']['App']['GetGitMergeState'](arg1);
+}
+
+export function GetGitStagedStatus(arg1) {
+  return window['go']['main']['App']['GetGitStagedStatus'](arg1);
+}
+
+export function GetGitStatus(arg1) {
+  return window['go']['main']['App']['GetGitStatus'](arg1);
+}
+
+export function GetGitignorePatterns(arg1) {
+  return window['go']['main']['App']['GetGitignorePatterns'](arg1);
+}
+
+export function GetOperationJournal() {
+  return window['go']['main']['App']['GetOperationJournal']();
+}
+
+export function GetPendingOpenLocation() {
+  return window['go']['main']['App']['GetPendingOpenLocation']();
+}
+
+export function GetPerformanceProfile(arg1) {
+  return window['go']['main']['App']['GetPerformanceProfile'](arg1);
+}
+
+export function GetPreCommitChecks(arg1) {
+  return window['go']['main']['App']['GetPreCommitChecks'](arg1);
+}
+
 export function GetPreference(arg1) {
   return window['go']['main']['App']['GetPreference'](arg1);
 }
 
+export function GetProjectConfig(arg1) {
+  return window['go']['main']['App']['GetProjectConfig'](arg1);
+}
+
+export function GetProjectDashboard(arg1) {
+  return window['go']['main']['App']['GetProjectDashboard'](arg1);
+}
+
+export function GetProjectRoots(arg1) {
+  return window['go']['main']['App']['GetProjectRoots'](arg1);
+}
+
+export function GetProjectSettings(arg1) {
+  return window['go']['main']['App']['GetProjectSettings'](arg1);
+}
+
 export function GetProjects() {
   return window['go']['main']['App']['GetProjects']();
 }
 
+export function GetPublishTargets(arg1) {
+  return window['go']['main']['App']['GetPublishTargets'](arg1);
+}
+
+export function GetQuickFixes(arg1) {
+  return window['go']['main']['App']['GetQuickFixes'](arg1);
+}
+
+export function GetRedirects(arg1) {
+  return window['go']['main']['App']['GetRedirects'](arg1);
+}
+
+export function GetReleases(arg1) {
+  return window['go']['main']['App']['GetReleases'](arg1);
+}
+
+export function GetReviewQueue(arg1) {
+  return window['go']['main']['App']['GetReviewQueue'](arg1);
+}
+
 export function GetShadowFile(arg1) {
   return window['go']['main']['App']['GetShadowFile'](arg1);
 }
 
+export function GetStyleProfile(arg1) {
+  return window['go']['main']['App']['GetStyleProfile'](arg1);
+}
+
+export function GetWebClipperInfo() {
+  return window['go']['main']['App']['GetWebClipperInfo']();
+}
+
+export function GitAddRemote(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GitAddRemote'](arg1, arg2, arg3);
+}
+
+export function GitBlame(arg1) {
+  return window['go']['main']['App']['GitBlame'](arg1);
+}
+
+export function GitCheckout(arg1, arg2) {
+  return window['go']['main']['App']['GitCheckout'](arg1, arg2);
+}
+
+export function GitClone(arg1, arg2) {
+  return window['go']['main']['App']['GitClone'](arg1, arg2);
+}
+
+export function GitCommit(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GitCommit'](arg1, arg2, arg3);
+}
+
+export function GitCreateBranch(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GitCreateBranch'](arg1, arg2, arg3);
+}
+
+export function GitCreateTag(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GitCreateTag'](arg1, arg2, arg3);
+}
+
+export function GitDiffFile(arg1, arg2) {
+  return window['go']['main']['App']['GitDiffFile'](arg1, arg2);
+}
+
+export function GitDiscardChanges(arg1, arg2) {
+  return window['go']['main']['App']['GitDiscardChanges'](arg1, arg2);
+}
+
+export function GitFetch(arg1) {
+  return window['go']['main']['App']['GitFetch'](arg1);
+}
+
+export function GitFileLog(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GitFileLog'](arg1, arg2, arg3);
+}
+
+export function GitInit(arg1) {
+  return window['go']['main']['App']['GitInit'](arg1);
+}
+
+export function GitListBranches(arg1) {
+  return window['go']['main']['App']['GitListBranches'](arg1);
+}
+
+export function GitListRemotes(arg1) {
+  return window['go']['main']['App']['GitListRemotes'](arg1);
+}
+
+export function GitListSubmodules(arg1) {
+  return window['go']['main']['App']['GitListSubmodules'](arg1);
+}
+
+export function GitListTags(arg1) {
+  return window['go']['main']['App']['GitListTags'](arg1);
+}
+
+export function GitPull(arg1, arg2) {
+  return window['go']['main']['App']['GitPull'](arg1, arg2);
+}
+
+export function GitPush(arg1, arg2) {
+  return window['go']['main']['App']['GitPush'](arg1, arg2);
+}
+
+export function GitSetRemoteURL(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GitSetRemoteURL'](arg1, arg2, arg3);
+}
+
+export function GitShowFileAtCommit(arg1, arg2) {
+  return window['go']['main']['App']['GitShowFileAtCommit'](arg1, arg2);
+}
+
+export function GitStage(arg1) {
+  return window['go']['main']['App']['GitStage'](arg1);
+}
+
+export function GitStashList(arg1) {
+  return window['go']['main']['App']['GitStashList'](arg1);
+}
+
+export function GitStashPop(arg1, arg2) {
+  return window['go']['main']['App']['GitStashPop'](arg1, arg2);
+}
+
+export function GitStashSave(arg1, arg2) {
+  return window['go']['main']['App']['GitStashSave'](arg1, arg2);
+}
+
+export function GitSubmoduleUpdate(arg1) {
+  return window['go']['main']['App']['GitSubmoduleUpdate'](arg1);
+}
+
+export function GitTrackingStatus(arg1) {
+  return window['go']['main']['App']['GitTrackingStatus'](arg1);
+}
+
+export function GitUnstage(arg1) {
+  return window['go']['main']['App']['GitUnstage'](arg1);
+}
+
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
@@ -66,10 +458,74 @@ export function HasCorruption() {
   return window['go']['main']['App']['HasCorruption']();
 }
 
+export function HasSecret(arg1) {
+  return window['go']['main']['App']['HasSecret'](arg1);
+}
+
+export function HydrateFile(arg1) {
+  return window['go']['main']['App']['HydrateFile'](arg1);
+}
+
+export function ImportFiles(arg1) {
+  return window['go']['main']['App']['ImportFiles'](arg1);
+}
+
+export function ImportGitIconPack(arg1) {
+  return window['go']['main']['App']['ImportGitIconPack'](arg1);
+}
+
+export function ImportProject(arg1, arg2) {
+  return window['go']['main']['App']['ImportProject'](arg1, arg2);
+}
+
+export function IndexProjectEmbeddings(arg1) {
+  return window['go']['main']['App']['IndexProjectEmbeddings'](arg1);
+}
+
+export function IsGitHubDesktopInstalled() {
+  return window['go']['main']['App']['IsGitHubDesktopInstalled']();
+}
+
+export function IsSafeMode() {
+  return window['go']['main']['App']['IsSafeMode']();
+}
+
+export function IsWritable(arg1) {
+  return window['go']['main']['App']['IsWritable'](arg1);
+}
+
+export function LintFile(arg1) {
+  return window['go']['main']['App']['LintFile'](arg1);
+}
+
+export function LintProject(arg1) {
+  return window['go']['main']['App']['LintProject'](arg1);
+}
+
+export function ListCloudBackups() {
+  return window['go']['main']['App']['ListCloudBackups']();
+}
+
 export function ListFiles(arg1) {
   return window['go']['main']['App']['ListFiles'](arg1);
 }
 
+export function ListTemplates() {
+  return window['go']['main']['App']['ListTemplates']();
+}
+
+export function ListTrash(arg1) {
+  return window['go']['main']['App']['ListTrash'](arg1);
+}
+
+export function MarkExport(arg1, arg2) {
+  return window['go']['main']['App']['MarkExport'](arg1, arg2);
+}
+
+export function MoveFile(arg1, arg2) {
+  return window['go']['main']['App']['MoveFile'](arg1, arg2);
+}
+
 export function OpenBrowser(arg1) {
   return window['go']['main']['App']['OpenBrowser'](arg1);
 }
@@ -78,18 +534,126 @@ export function OpenGitClient(arg1) {
   return window['go']['main']['App']['OpenGitClient'](arg1);
 }
 
+export function OpenInTool(arg1, arg2) {
+  return window['go']['main']['App']['OpenInTool'](arg1, arg2);
+}
+
+export function OpenRemoteFile(arg1, arg2) {
+  return window['go']['main']['App']['OpenRemoteFile'](arg1, arg2);
+}
+
+export function OverwriteFile(arg1, arg2) {
+  return window['go']['main']['App']['OverwriteFile'](arg1, arg2);
+}
+
+export function PollEmailInbox() {
+  return window['go']['main']['App']['PollEmailInbox']();
+}
+
+export function PrepareDiscardChanges(arg1) {
+  return window['go']['main']['App']['PrepareDiscardChanges'](arg1);
+}
+
+export function PrepareExportSource(arg1, arg2) {
+  return window['go']['main']['App']['PrepareExportSource'](arg1, arg2);
+}
+
+export function PreviewFile(arg1) {
+  return window['go']['main']['App']['PreviewFile'](arg1);
+}
+
 export function ReadFile(arg1) {
   return window['go']['main']['App']['ReadFile'](arg1);
 }
 
+export function ReadFileBase64(arg1, arg2) {
+  return window['go']['main']['App']['ReadFileBase64'](arg1, arg2);
+}
+
+export function ReadFileRange(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ReadFileRange'](arg1, arg2, arg3);
+}
+
+export function RedoLastOperation() {
+  return window['go']['main']['App']['RedoLastOperation']();
+}
+
+export function RejectReviewItem(arg1) {
+  return window['go']['main']['App']['RejectReviewItem'](arg1);
+}
+
+export function ReleaseDocs(arg1, arg2) {
+  return window['go']['main']['App']['ReleaseDocs'](arg1, arg2);
+}
+
+export function RelocateProject(arg1, arg2) {
+  return window['go']['main']['App']['RelocateProject'](arg1, arg2);
+}
+
+export function RemoveAssetLicense(arg1, arg2) {
+  return window['go']['main']['App']['RemoveAssetLicense'](arg1, arg2);
+}
+
 export function RemoveProject(arg1) {
   return window['go']['main']['App']['RemoveProject'](arg1);
 }
 
+export function RemoveRedirect(arg1, arg2) {
+  return window['go']['main']['App']['RemoveRedirect'](arg1, arg2);
+}
+
+export function RenameFile(arg1, arg2) {
+  return window['go']['main']['App']['RenameFile'](arg1, arg2);
+}
+
+export function ReportDiagnostics(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ReportDiagnostics'](arg1, arg2, arg3);
+}
+
+export function ResetWebClipperToken() {
+  return window['go']['main']['App']['ResetWebClipperToken']();
+}
+
+export function ResolveInclude(arg1, arg2) {
+  return window['go']['main']['App']['ResolveInclude'](arg1, arg2);
+}
+
 export function RestoreBackup() {
   return window['go']['main']['App']['RestoreBackup']();
 }
 
+export function RestoreFromCloudBackup(arg1) {
+  return window['go']['main']['App']['RestoreFromCloudBackup'](arg1);
+}
+
+export function RestoreFromTrash(arg1) {
+  return window['go']['main']['App']['RestoreFromTrash'](arg1);
+}
+
+export function RevealInFileManager(arg1) {
+  return window['go']['main']['App']['RevealInFileManager'](arg1);
+}
+
+export function RunAIBatch(arg1, arg2) {
+  return window['go']['main']['App']['RunAIBatch'](arg1, arg2);
+}
+
+export function RunCloudBackup() {
+  return window['go']['main']['App']['RunCloudBackup']();
+}
+
+export function RunPreCommitChecks(arg1, arg2) {
+  return window['go']['main']['App']['RunPreCommitChecks'](arg1, arg2);
+}
+
+export function SaveAITemplate(arg1) {
+  return window['go']['main']['App']['SaveAITemplate'](arg1);
+}
+
+export function SaveAll(arg1) {
+  return window['go']['main']['App']['SaveAll'](arg1);
+}
+
 export function SaveAppState(arg1, arg2) {
   return window['go']['main']['App']['SaveAppState'](arg1, arg2);
 }
@@ -102,10 +666,22 @@ export function SavePreference(arg1, arg2) {
   return window['go']['main']['App']['SavePreference'](arg1, arg2);
 }
 
+export function SaveProjectSetting(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SaveProjectSetting'](arg1, arg2, arg3);
+}
+
 export function SaveShadowFile(arg1, arg2, arg3) {
   return window['go']['main']['App']['SaveShadowFile'](arg1, arg2, arg3);
 }
 
+export function SaveStyleProfile(arg1, arg2) {
+  return window['go']['main']['App']['SaveStyleProfile'](arg1, arg2);
+}
+
+export function SearchPassages(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SearchPassages'](arg1, arg2, arg3);
+}
+
 export function SelectCssFile() {
   return window['go']['main']['App']['SelectCssFile']();
 }
@@ -130,6 +706,94 @@ export function SelectSvgFile() {
   return window['go']['main']['App']['SelectSvgFile']();
 }
 
+export function SetAssetLicense(arg1, arg2) {
+  return window['go']['main']['App']['SetAssetLicense'](arg1, arg2);
+}
+
+export function SetGitClientIcon(arg1, arg2) {
+  return window['go']['main']['App']['SetGitClientIcon'](arg1, arg2);
+}
+
+export function SetGitSSHKey(arg1, arg2) {
+  return window['go']['main']['App']['SetGitSSHKey'](arg1, arg2);
+}
+
+export function SetGitToken(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SetGitToken'](arg1, arg2, arg3, arg4);
+}
+
+export function SetPreCommitChecks(arg1, arg2) {
+  return window['go']['main']['App']['SetPreCommitChecks'](arg1, arg2);
+}
+
+export function SetProjectRoots(arg1, arg2) {
+  return window['go']['main']['App']['SetProjectRoots'](arg1, arg2);
+}
+
+export function SetSecret(arg1, arg2) {
+  return window['go']['main']['App']['SetSecret'](arg1, arg2);
+}
+
+export function SignPDF(arg1, arg2) {
+  return window['go']['main']['App']['SignPDF'](arg1, arg2);
+}
+
+export function SlugifyTitle(arg1, arg2) {
+  return window['go']['main']['App']['SlugifyTitle'](arg1, arg2);
+}
+
+export function SnapshotShadowFiles() {
+  return window['go']['main']['App']['SnapshotShadowFiles']();
+}
+
+export function StartWebClipper(arg1) {
+  return window['go']['main']['App']['StartWebClipper'](arg1);
+}
+
+export function StopWebClipper() {
+  return window['go']['main']['App']['StopWebClipper']();
+}
+
+export function StreamFile(arg1, arg2) {
+  return window['go']['main']['App']['StreamFile'](arg1, arg2);
+}
+
+export function SuggestDiagramSource(arg1) {
+  return window['go']['main']['App']['SuggestDiagramSource'](arg1);
+}
+
+export function SuggestTitles(arg1, arg2) {
+  return window['go']['main']['App']['SuggestTitles'](arg1, arg2);
+}
+
+export function SyncContentSources(arg1) {
+  return window['go']['main']['App']['SyncContentSources'](arg1);
+}
+
+export function TestGitConnection(arg1, arg2) {
+  return window['go']['main']['App']['TestGitConnection'](arg1, arg2);
+}
+
+export function TestPublishTarget(arg1, arg2) {
+  return window['go']['main']['App']['TestPublishTarget'](arg1, arg2);
+}
+
+export function ToggleGitignorePath(arg1, arg2) {
+  return window['go']['main']['App']['ToggleGitignorePath'](arg1, arg2);
+}
+
+export function UndoLastOperation() {
+  return window['go']['main']['App']['UndoLastOperation']();
+}
+
+export function UpdateGitIcon(arg1, arg2) {
+  return window['go']['main']['App']['UpdateGitIcon'](arg1, arg2);
+}
+
 export function UpdateProjectLastOpened(arg1) {
   return window['go']['main']['App']['UpdateProjectLastOpened'](arg1);
 }
+
+export function ValidateProjectConfig(arg1) {
+  return window['go']['main']['App']['ValidateProjectConfig'](arg1);
+}
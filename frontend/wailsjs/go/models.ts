@@ -1,21 +1,159 @@
 export namespace main {
 	
-	export class FileNode {
+	export class AIHistoryEntry {
+	    id: number;
+	    project: string;
+	    action: string;
+	    document: string;
+	    model: string;
+	    prompt: string;
+	    response: string;
+	    error: string;
+	    // Go type: time
+	    createdAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new AIHistoryEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.project = source["project"];
+	        this.action = source["action"];
+	        this.document = source["document"];
+	        this.model = source["model"];
+	        this.prompt = source["prompt"];
+	        this.response = source["response"];
+	        this.error = source["error"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AISafetyOptions {
+	    categories: string[];
+	    thresholds: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new AISafetyOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.categories = source["categories"];
+	        this.thresholds = source["thresholds"];
+	    }
+	}
+	export class AITemplate {
+	    id: string;
 	    name: string;
-	    path: string;
-	    isDir: boolean;
-	    children?: FileNode[];
+	    prompt: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new FileNode(source);
+	        return new AITemplate(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
 	        this.name = source["name"];
+	        this.prompt = source["prompt"];
+	    }
+	}
+	export class AcronymConfig {
+	    scope: string;
+	    ignore: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new AcronymConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.scope = source["scope"];
+	        this.ignore = source["ignore"];
+	    }
+	}
+	export class ArchiveOptions {
+	    excludeGit: boolean;
+	    excludeIgnored: boolean;
+	    reproducible: boolean;
+	    stripImageMetadata: boolean;
+	    preset?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ArchiveOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.excludeGit = source["excludeGit"];
+	        this.excludeIgnored = source["excludeIgnored"];
+	        this.reproducible = source["reproducible"];
+	        this.stripImageMetadata = source["stripImageMetadata"];
+	        this.preset = source["preset"];
+	    }
+	}
+	export class ArchiveProgress {
+	    path: string;
+	    current: string;
+	    added: number;
+	    total: number;
+	    sha256?: string;
+	    commit?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ArchiveProgress(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.path = source["path"];
-	        this.isDir = source["isDir"];
-	        this.children = this.convertValues(source["children"], FileNode);
+	        this.current = source["current"];
+	        this.added = source["added"];
+	        this.total = source["total"];
+	        this.sha256 = source["sha256"];
+	        this.commit = source["commit"];
+	    }
+	}
+	export class AssetLicense {
+	    path: string;
+	    license: string;
+	    sourceUrl: string;
+	    author: string;
+	    notes: string;
+	    // Go type: time
+	    updatedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new AssetLicense(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.license = source["license"];
+	        this.sourceUrl = source["sourceUrl"];
+	        this.author = source["author"];
+	        this.notes = source["notes"];
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -36,21 +174,285 @@ export namespace main {
 		    return a;
 		}
 	}
-	export class Project {
+	export class AssetUsage {
+	    path: string;
+	    usedIn: string[];
+	    license?: AssetLicense;
+	
+	    static createFrom(source: any = {}) {
+	        return new AssetUsage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.usedIn = source["usedIn"];
+	        this.license = this.convertValues(source["license"], AssetLicense);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AssetLicenseReport {
+	    assets: AssetUsage[];
+	    unlicensed: AssetUsage[];
+	    orphaned: AssetLicense[];
+	
+	    static createFrom(source: any = {}) {
+	        return new AssetLicenseReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.assets = this.convertValues(source["assets"], AssetUsage);
+	        this.unlicensed = this.convertValues(source["unlicensed"], AssetUsage);
+	        this.orphaned = this.convertValues(source["orphaned"], AssetLicense);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class AttachmentPreview {
+	    path: string;
+	    kind: string;
+	    size: number;
+	    pages?: number;
+	    sheets?: string[];
+	    text: string;
+	    truncated?: boolean;
+	    thumbnail?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new AttachmentPreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.kind = source["kind"];
+	        this.size = source["size"];
+	        this.pages = source["pages"];
+	        this.sheets = source["sheets"];
+	        this.text = source["text"];
+	        this.truncated = source["truncated"];
+	        this.thumbnail = source["thumbnail"];
+	    }
+	}
+	export class Base64File {
 	    path: string;
+	    mimeType: string;
+	    data: string;
+	    size: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Base64File(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.mimeType = source["mimeType"];
+	        this.data = source["data"];
+	        this.size = source["size"];
+	    }
+	}
+	export class CheckReportResult {
+	    path: string;
+	    format: string;
+	    files: number;
+	    errors: number;
+	    warnings: number;
+	    infos: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CheckReportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.format = source["format"];
+	        this.files = source["files"];
+	        this.errors = source["errors"];
+	        this.warnings = source["warnings"];
+	        this.infos = source["infos"];
+	    }
+	}
+	export class CloudBackup {
 	    name: string;
-	    // Go type: time
-	    lastOpened: any;
+	    size: number;
+	    created: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new Project(source);
+	        return new CloudBackup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.size = source["size"];
+	        this.created = source["created"];
+	    }
+	}
+	export class ConfigIssue {
+	    path: string;
+	    message: string;
+	    line: number;
+	    column: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConfigIssue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.message = source["message"];
+	        this.line = source["line"];
+	        this.column = source["column"];
+	    }
+	}
+	export class ConflictSection {
+	    startLine: number;
+	    endLine: number;
+	    oursLabel: string;
+	    theirsLabel: string;
+	    ours: string;
+	    theirs: string;
+	    base?: string;
+	    hasBase?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ConflictSection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.startLine = source["startLine"];
+	        this.endLine = source["endLine"];
+	        this.oursLabel = source["oursLabel"];
+	        this.theirsLabel = source["theirsLabel"];
+	        this.ours = source["ours"];
+	        this.theirs = source["theirs"];
+	        this.base = source["base"];
+	        this.hasBase = source["hasBase"];
+	    }
+	}
+	export class ContentSource {
+	    name: string;
+	    url: string;
+	    ref: string;
+	    path: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ContentSource(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.url = source["url"];
+	        this.ref = source["ref"];
 	        this.path = source["path"];
+	    }
+	}
+	export class ContentSourceStatus {
+	    name: string;
+	    url: string;
+	    ref: string;
+	    path: string;
+	    attribute: string;
+	    dir?: string;
+	    commit?: string;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ContentSourceStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.name = source["name"];
-	        this.lastOpened = this.convertValues(source["lastOpened"], null);
+	        this.url = source["url"];
+	        this.ref = source["ref"];
+	        this.path = source["path"];
+	        this.attribute = source["attribute"];
+	        this.dir = source["dir"];
+	        this.commit = source["commit"];
+	        this.error = source["error"];
+	    }
+	}
+	export class Passage {
+	    path: string;
+	    sectionId: string;
+	    title: string;
+	    line: number;
+	    content: string;
+	    score: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Passage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.sectionId = source["sectionId"];
+	        this.title = source["title"];
+	        this.line = source["line"];
+	        this.content = source["content"];
+	        this.score = source["score"];
+	    }
+	}
+	export class Contradiction {
+	    a: Passage;
+	    b: Passage;
+	    statementA: string;
+	    statementB: string;
+	    explanation: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Contradiction(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.a = this.convertValues(source["a"], Passage);
+	        this.b = this.convertValues(source["b"], Passage);
+	        this.statementA = source["statementA"];
+	        this.statementB = source["statementB"];
+	        this.explanation = source["explanation"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -71,6 +473,2123 @@ export namespace main {
 		    return a;
 		}
 	}
+	export class CopyOptions {
+	    overwrite: string;
+	    followSymlinks: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CopyOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.overwrite = source["overwrite"];
+	        this.followSymlinks = source["followSymlinks"];
+	    }
+	}
+	export class CopyProgress {
+	    src: string;
+	    current: string;
+	    copied: number;
+	    skipped: number;
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CopyProgress(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.src = source["src"];
+	        this.current = source["current"];
+	        this.copied = source["copied"];
+	        this.skipped = source["skipped"];
+	        this.total = source["total"];
+	    }
+	}
+	export class DashboardCounts {
+	    date?: string;
+	    errors: number;
+	    warnings: number;
+	    infos: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DashboardCounts(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.errors = source["errors"];
+	        this.warnings = source["warnings"];
+	        this.infos = source["infos"];
+	    }
+	}
+	export class DashboardPage {
+	    path: string;
+	    title: string;
+	    words: number;
+	    modified: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DashboardPage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.title = source["title"];
+	        this.words = source["words"];
+	        this.modified = source["modified"];
+	    }
+	}
+	export class Diagnostic {
+	    id?: string;
+	    path: string;
+	    line: number;
+	    column: number;
+	    severity: string;
+	    message: string;
+	    source: string;
+	    rule: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Diagnostic(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.path = source["path"];
+	        this.line = source["line"];
+	        this.column = source["column"];
+	        this.severity = source["severity"];
+	        this.message = source["message"];
+	        this.source = source["source"];
+	        this.rule = source["rule"];
+	    }
+	}
+	export class DiagnosticExplanation {
+	    explanation: string;
+	    fix: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiagnosticExplanation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.explanation = source["explanation"];
+	        this.fix = source["fix"];
+	    }
+	}
+	export class DiagramSuggestion {
+	    plantuml: string;
+	    mermaid: string;
+	    notes: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiagramSuggestion(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.plantuml = source["plantuml"];
+	        this.mermaid = source["mermaid"];
+	        this.notes = source["notes"];
+	    }
+	}
+	export class DiscardPreview {
+	    token: string;
+	    path: string;
+	    diff: string;
+	    deleted?: boolean;
+	    unsavedEdits?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DiscardPreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.token = source["token"];
+	        this.path = source["path"];
+	        this.diff = source["diff"];
+	        this.deleted = source["deleted"];
+	        this.unsavedEdits = source["unsavedEdits"];
+	    }
+	}
+	export class DocTemplate {
+	    id: string;
+	    name: string;
+	    source: string;
+	    path?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DocTemplate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.source = source["source"];
+	        this.path = source["path"];
+	    }
+	}
+	export class DocsRelease {
+	    version: string;
+	    tag: string;
+	    commit: string;
+	    folder: string;
+	    errors: number;
+	    warnings: number;
+	    outputs: string[];
+	    // Go type: time
+	    createdAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new DocsRelease(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
+	        this.tag = source["tag"];
+	        this.commit = source["commit"];
+	        this.folder = source["folder"];
+	        this.errors = source["errors"];
+	        this.warnings = source["warnings"];
+	        this.outputs = source["outputs"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EmailInboxResult {
+	    filed: string[];
+	    errors: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new EmailInboxResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filed = source["filed"];
+	        this.errors = source["errors"];
+	    }
+	}
+	export class EmbeddingIndexResult {
+	    passages: number;
+	    updated: number;
+	    removed: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new EmbeddingIndexResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.passages = source["passages"];
+	        this.updated = source["updated"];
+	        this.removed = source["removed"];
+	    }
+	}
+	export class ExportMarkingOptions {
+	    sourcePath: string;
+	    text: string;
+	    preset?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExportMarkingOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sourcePath = source["sourcePath"];
+	        this.text = source["text"];
+	        this.preset = source["preset"];
+	    }
+	}
+	export class ExportPreset {
+	    name: string;
+	    format: string;
+	    output: string;
+	    attributes: Record<string, string>;
+	    audience: string;
+	    redirects: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ExportPreset(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.format = source["format"];
+	        this.output = source["output"];
+	        this.attributes = source["attributes"];
+	        this.audience = source["audience"];
+	        this.redirects = source["redirects"];
+	    }
+	}
+	export class RemovedContent {
+	    file?: string;
+	    line: number;
+	    kind: string;
+	    title?: string;
+	    role: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new RemovedContent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.file = source["file"];
+	        this.line = source["line"];
+	        this.kind = source["kind"];
+	        this.title = source["title"];
+	        this.role = source["role"];
+	    }
+	}
+	export class ExportSource {
+	    content: string;
+	    removed: RemovedContent[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ExportSource(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.content = source["content"];
+	        this.removed = this.convertValues(source["removed"], RemovedContent);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FAQDraft {
+	    content: string;
+	    sources: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new FAQDraft(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.content = source["content"];
+	        this.sources = source["sources"];
+	    }
+	}
+	export class FileChunk {
+	    streamId?: string;
+	    path: string;
+	    offset: number;
+	    next: number;
+	    size: number;
+	    data: string;
+	    eof: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileChunk(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.streamId = source["streamId"];
+	        this.path = source["path"];
+	        this.offset = source["offset"];
+	        this.next = source["next"];
+	        this.size = source["size"];
+	        this.data = source["data"];
+	        this.eof = source["eof"];
+	    }
+	}
+	export class FileContent {
+	    path: string;
+	    content: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileContent(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.content = source["content"];
+	    }
+	}
+	export class SaveConflict {
+	    path: string;
+	    ours: string;
+	    theirs: string;
+	    // Go type: time
+	    loadedModTime: any;
+	    // Go type: time
+	    diskModTime: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new SaveConflict(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.ours = source["ours"];
+	        this.theirs = source["theirs"];
+	        this.loadedModTime = this.convertValues(source["loadedModTime"], null);
+	        this.diskModTime = this.convertValues(source["diskModTime"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FileError {
+	    op: string;
+	    path: string;
+	    code: string;
+	    message: string;
+	    hint?: string;
+	    conflict?: SaveConflict;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.op = source["op"];
+	        this.path = source["path"];
+	        this.code = source["code"];
+	        this.message = source["message"];
+	        this.hint = source["hint"];
+	        this.conflict = this.convertValues(source["conflict"], SaveConflict);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FileMetadata {
+	    path: string;
+	    size: number;
+	    // Go type: time
+	    modTime: any;
+	    permissions: string;
+	    isDir: boolean;
+	    readOnly: boolean;
+	    placeholder: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new FileMetadata(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.modTime = this.convertValues(source["modTime"], null);
+	        this.permissions = source["permissions"];
+	        this.isDir = source["isDir"];
+	        this.readOnly = source["readOnly"];
+	        this.placeholder = source["placeholder"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FolderStats {
+	    fileCount: number;
+	    dirCount: number;
+	    totalSize: number;
+	    // Go type: time
+	    lastModified: any;
+	    lastModifiedPath?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FolderStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.fileCount = source["fileCount"];
+	        this.dirCount = source["dirCount"];
+	        this.totalSize = source["totalSize"];
+	        this.lastModified = this.convertValues(source["lastModified"], null);
+	        this.lastModifiedPath = source["lastModifiedPath"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class FileNode {
+	    name: string;
+	    path: string;
+	    isDir: boolean;
+	    ignored?: boolean;
+	    unloaded?: boolean;
+	    error?: string;
+	    isSymlink?: boolean;
+	    target?: string;
+	    isRoot?: boolean;
+	    stats?: FolderStats;
+	    submodule?: boolean;
+	    children?: FileNode[];
+	
+	    static createFrom(source: any = {}) {
+	        return new FileNode(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.isDir = source["isDir"];
+	        this.ignored = source["ignored"];
+	        this.unloaded = source["unloaded"];
+	        this.error = source["error"];
+	        this.isSymlink = source["isSymlink"];
+	        this.target = source["target"];
+	        this.isRoot = source["isRoot"];
+	        this.stats = this.convertValues(source["stats"], FolderStats);
+	        this.submodule = source["submodule"];
+	        this.children = this.convertValues(source["children"], FileNode);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ImageInfo {
+	    format: string;
+	    width: number;
+	    height: number;
+	    dataUrl?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImageInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.format = source["format"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.dataUrl = source["dataUrl"];
+	    }
+	}
+	export class FilePreview {
+	    path: string;
+	    kind: string;
+	    mimeType: string;
+	    size: number;
+	    text?: string;
+	    html?: string;
+	    frontMatter?: Record<string, any>;
+	    issues?: ConfigIssue[];
+	    image?: ImageInfo;
+	
+	    static createFrom(source: any = {}) {
+	        return new FilePreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.kind = source["kind"];
+	        this.mimeType = source["mimeType"];
+	        this.size = source["size"];
+	        this.text = source["text"];
+	        this.html = source["html"];
+	        this.frontMatter = source["frontMatter"];
+	        this.issues = this.convertValues(source["issues"], ConfigIssue);
+	        this.image = this.convertValues(source["image"], ImageInfo);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class GitBlameLine {
+	    line: number;
+	    hash?: string;
+	    author?: string;
+	    email?: string;
+	    date?: string;
+	    subject?: string;
+	    uncommitted?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new GitBlameLine(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.line = source["line"];
+	        this.hash = source["hash"];
+	        this.author = source["author"];
+	        this.email = source["email"];
+	        this.date = source["date"];
+	        this.subject = source["subject"];
+	        this.uncommitted = source["uncommitted"];
+	    }
+	}
+	export class GitBranch {
+	    name: string;
+	    head: string;
+	    current?: boolean;
+	    remote?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new GitBranch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.head = source["head"];
+	        this.current = source["current"];
+	        this.remote = source["remote"];
+	    }
+	}
+	export class GitChange {
+	    path: string;
+	    state: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GitChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.state = source["state"];
+	    }
+	}
+	export class GitError {
+	    op: string;
+	    remote: string;
+	    code: string;
+	    message: string;
+	    hint?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GitError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.op = source["op"];
+	        this.remote = source["remote"];
+	        this.code = source["code"];
+	        this.message = source["message"];
+	        this.hint = source["hint"];
+	    }
+	}
+	export class GitConnectionTest {
+	    remote: string;
+	    url: string;
+	    host: string;
+	    method: string;
+	    username?: string;
+	    sshKey?: string;
+	    ok: boolean;
+	    branches: number;
+	    error?: GitError;
+	
+	    static createFrom(source: any = {}) {
+	        return new GitConnectionTest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.remote = source["remote"];
+	        this.url = source["url"];
+	        this.host = source["host"];
+	        this.method = source["method"];
+	        this.username = source["username"];
+	        this.sshKey = source["sshKey"];
+	        this.ok = source["ok"];
+	        this.branches = source["branches"];
+	        this.error = this.convertValues(source["error"], GitError);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GitCredentials {
+	    remote: string;
+	    url: string;
+	    host: string;
+	    method: string;
+	    username?: string;
+	    sshKey?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GitCredentials(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.remote = source["remote"];
+	        this.url = source["url"];
+	        this.host = source["host"];
+	        this.method = source["method"];
+	        this.username = source["username"];
+	        this.sshKey = source["sshKey"];
+	    }
+	}
+	export class GitDiffLine {
+	    kind: string;
+	    text: string;
+	    oldLine?: number;
+	    newLine?: number;
+	    noNewline?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new GitDiffLine(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.text = source["text"];
+	        this.oldLine = source["oldLine"];
+	        this.newLine = source["newLine"];
+	        this.noNewline = source["noNewline"];
+	    }
+	}
+	export class GitDiffHunk {
+	    oldStart: number;
+	    oldLines: number;
+	    newStart: number;
+	    newLines: number;
+	    lines: GitDiffLine[];
+	
+	    static createFrom(source: any = {}) {
+	        return new GitDiffHunk(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.oldStart = source["oldStart"];
+	        this.oldLines = source["oldLines"];
+	        this.newStart = source["newStart"];
+	        this.newLines = source["newLines"];
+	        this.lines = this.convertValues(source["lines"], GitDiffLine);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GitDiff {
+	    path: string;
+	    staged: boolean;
+	    binary?: boolean;
+	    diff: string;
+	    hunks: GitDiffHunk[];
+	
+	    static createFrom(source: any = {}) {
+	        return new GitDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.staged = source["staged"];
+	        this.binary = source["binary"];
+	        this.diff = source["diff"];
+	        this.hunks = this.convertValues(source["hunks"], GitDiffHunk);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	export class GitFileStatus {
+	    state: string;
+	    staged: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new GitFileStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.state = source["state"];
+	        this.staged = source["staged"];
+	    }
+	}
+	export class GitIconPackImport {
+	    added: string[];
+	    updated: string[];
+	    clients: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new GitIconPackImport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.added = source["added"];
+	        this.updated = source["updated"];
+	        this.clients = source["clients"];
+	    }
+	}
+	export class GitLogEntry {
+	    hash: string;
+	    author: string;
+	    email: string;
+	    date: string;
+	    subject: string;
+	    tags?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new GitLogEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hash = source["hash"];
+	        this.author = source["author"];
+	        this.email = source["email"];
+	        this.date = source["date"];
+	        this.subject = source["subject"];
+	        this.tags = source["tags"];
+	    }
+	}
+	export class GitMergeState {
+	    operation?: string;
+	    conflicted: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new GitMergeState(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.operation = source["operation"];
+	        this.conflicted = source["conflicted"];
+	    }
+	}
+	export class GitRemote {
+	    name: string;
+	    url: string;
+	    provider?: string;
+	    webUrl?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GitRemote(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.url = source["url"];
+	        this.provider = source["provider"];
+	        this.webUrl = source["webUrl"];
+	    }
+	}
+	export class GitStagedStatus {
+	    branch?: string;
+	    staged: GitChange[];
+	    unstaged: GitChange[];
+	
+	    static createFrom(source: any = {}) {
+	        return new GitStagedStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.branch = source["branch"];
+	        this.staged = this.convertValues(source["staged"], GitChange);
+	        this.unstaged = this.convertValues(source["unstaged"], GitChange);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GitStashEntry {
+	    index: number;
+	    hash: string;
+	    date: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GitStashEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.hash = source["hash"];
+	        this.date = source["date"];
+	        this.message = source["message"];
+	    }
+	}
+	export class GitStatus {
+	    isRepo: boolean;
+	    root?: string;
+	    branch?: string;
+	    head?: string;
+	    detached?: boolean;
+	    files: Record<string, GitFileStatus>;
+	
+	    static createFrom(source: any = {}) {
+	        return new GitStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.isRepo = source["isRepo"];
+	        this.root = source["root"];
+	        this.branch = source["branch"];
+	        this.head = source["head"];
+	        this.detached = source["detached"];
+	        this.files = this.convertValues(source["files"], GitFileStatus, true);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GitSubmodule {
+	    name: string;
+	    path: string;
+	    url: string;
+	    initialized: boolean;
+	    dirty?: boolean;
+	    detached?: boolean;
+	    branch?: string;
+	    expected?: string;
+	    current?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GitSubmodule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.url = source["url"];
+	        this.initialized = source["initialized"];
+	        this.dirty = source["dirty"];
+	        this.detached = source["detached"];
+	        this.branch = source["branch"];
+	        this.expected = source["expected"];
+	        this.current = source["current"];
+	    }
+	}
+	export class GitSyncResult {
+	    branch: string;
+	    upToDate: boolean;
+	    head: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GitSyncResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.branch = source["branch"];
+	        this.upToDate = source["upToDate"];
+	        this.head = source["head"];
+	    }
+	}
+	export class GitTag {
+	    name: string;
+	    commit: string;
+	    annotated?: boolean;
+	    message?: string;
+	    tagger?: string;
+	    date: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GitTag(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.commit = source["commit"];
+	        this.annotated = source["annotated"];
+	        this.message = source["message"];
+	        this.tagger = source["tagger"];
+	        this.date = source["date"];
+	    }
+	}
+	export class GitTracking {
+	    branch: string;
+	    upstream: string;
+	    remote: string;
+	    ahead: number;
+	    behind: number;
+	    commit?: string;
+	    fetched?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new GitTracking(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.branch = source["branch"];
+	        this.upstream = source["upstream"];
+	        this.remote = source["remote"];
+	        this.ahead = source["ahead"];
+	        this.behind = source["behind"];
+	        this.commit = source["commit"];
+	        this.fetched = source["fetched"];
+	    }
+	}
+	
+	export class TermRule {
+	    term: string;
+	    replacements: string[];
+	    reason: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TermRule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.term = source["term"];
+	        this.replacements = source["replacements"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class InclusiveLanguageConfig {
+	    disableDefaults: boolean;
+	    terms: TermRule[];
+	    allow: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new InclusiveLanguageConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.disableDefaults = source["disableDefaults"];
+	        this.terms = this.convertValues(source["terms"], TermRule);
+	        this.allow = source["allow"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class InstalledTool {
+	    id: string;
+	    name: string;
+	    kind: string;
+	    installed: boolean;
+	    command?: string;
+	    args?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new InstalledTool(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.kind = source["kind"];
+	        this.installed = source["installed"];
+	        this.command = source["command"];
+	        this.args = source["args"];
+	    }
+	}
+	export class JournalStep {
+	    kind: string;
+	    path: string;
+	    newPath?: string;
+	    backup?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new JournalStep(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.path = source["path"];
+	        this.newPath = source["newPath"];
+	        this.backup = source["backup"];
+	    }
+	}
+	export class JournalEntry {
+	    id: string;
+	    label: string;
+	    steps: JournalStep[];
+	    // Go type: time
+	    createdAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new JournalEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.label = source["label"];
+	        this.steps = this.convertValues(source["steps"], JournalStep);
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class LengthLimits {
+	    maxPageWords: number;
+	    maxSectionWords: number;
+	    minIntroWords: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LengthLimits(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.maxPageWords = source["maxPageWords"];
+	        this.maxSectionWords = source["maxSectionWords"];
+	        this.minIntroWords = source["minIntroWords"];
+	    }
+	}
+	export class LicenseHeaderResult {
+	    added: string[];
+	    updated: string[];
+	    unchanged: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LicenseHeaderResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.added = source["added"];
+	        this.updated = source["updated"];
+	        this.unchanged = source["unchanged"];
+	    }
+	}
+	export class LintBaselineResult {
+	    path: string;
+	    files: number;
+	    findings: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LintBaselineResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.files = source["files"];
+	        this.findings = source["findings"];
+	    }
+	}
+	export class LintConfig {
+	    rules: Record<string, string>;
+	    length: LengthLimits;
+	    acronyms: AcronymConfig;
+	
+	    static createFrom(source: any = {}) {
+	        return new LintConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.rules = source["rules"];
+	        this.length = this.convertValues(source["length"], LengthLimits);
+	        this.acronyms = this.convertValues(source["acronyms"], AcronymConfig);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class OpenLocation {
+	    path: string;
+	    line?: number;
+	    column?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new OpenLocation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.line = source["line"];
+	        this.column = source["column"];
+	    }
+	}
+	export class PDFSignOptions {
+	    certificateSecret: string;
+	    passwordSecret: string;
+	    reason: string;
+	    location: string;
+	    contactInfo: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PDFSignOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.certificateSecret = source["certificateSecret"];
+	        this.passwordSecret = source["passwordSecret"];
+	        this.reason = source["reason"];
+	        this.location = source["location"];
+	        this.contactInfo = source["contactInfo"];
+	    }
+	}
+	export class PDFSignature {
+	    path: string;
+	    signer: string;
+	    issuer: string;
+	    // Go type: time
+	    notAfter: any;
+	    // Go type: time
+	    signedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new PDFSignature(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.signer = source["signer"];
+	        this.issuer = source["issuer"];
+	        this.notAfter = this.convertValues(source["notAfter"], null);
+	        this.signedAt = this.convertValues(source["signedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class PerformanceProfile {
+	    network: boolean;
+	    degraded: boolean;
+	    autosaveInterval: number;
+	    watchFiles: boolean;
+	    backgroundIndex: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PerformanceProfile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.network = source["network"];
+	        this.degraded = source["degraded"];
+	        this.autosaveInterval = source["autosaveInterval"];
+	        this.watchFiles = source["watchFiles"];
+	        this.backgroundIndex = source["backgroundIndex"];
+	    }
+	}
+	export class PreCommitResult {
+	    check: string;
+	    passed: boolean;
+	    skipped?: boolean;
+	    message?: string;
+	    diagnostics: Diagnostic[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PreCommitResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.check = source["check"];
+	        this.passed = source["passed"];
+	        this.skipped = source["skipped"];
+	        this.message = source["message"];
+	        this.diagnostics = this.convertValues(source["diagnostics"], Diagnostic);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ProjectRoot {
+	    path: string;
+	    label: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProjectRoot(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.label = source["label"];
+	    }
+	}
+	export class Project {
+	    path: string;
+	    name: string;
+	    // Go type: time
+	    lastOpened: any;
+	    roots?: ProjectRoot[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Project(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.name = source["name"];
+	        this.lastOpened = this.convertValues(source["lastOpened"], null);
+	        this.roots = this.convertValues(source["roots"], ProjectRoot);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StyleProfile {
+	    audience: string;
+	    tone: string;
+	    forbiddenPhrases: string[];
+	    examples: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new StyleProfile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.audience = source["audience"];
+	        this.tone = source["tone"];
+	        this.forbiddenPhrases = source["forbiddenPhrases"];
+	        this.examples = source["examples"];
+	    }
+	}
+	export class PublishTarget {
+	    name: string;
+	    type: string;
+	    url: string;
+	    options: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new PublishTarget(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.type = source["type"];
+	        this.url = source["url"];
+	        this.options = source["options"];
+	    }
+	}
+	export class ProjectConfig {
+	    attributes: Record<string, string>;
+	    lint: LintConfig;
+	    exportPresets: ExportPreset[];
+	    publishTargets: PublishTarget[];
+	    glossary: string;
+	    styleProfile?: StyleProfile;
+	    inclusiveLanguage?: InclusiveLanguageConfig;
+	    contentSources: ContentSource[];
+	    settings: Record<string, any>;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProjectConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.attributes = source["attributes"];
+	        this.lint = this.convertValues(source["lint"], LintConfig);
+	        this.exportPresets = this.convertValues(source["exportPresets"], ExportPreset);
+	        this.publishTargets = this.convertValues(source["publishTargets"], PublishTarget);
+	        this.glossary = source["glossary"];
+	        this.styleProfile = this.convertValues(source["styleProfile"], StyleProfile);
+	        this.inclusiveLanguage = this.convertValues(source["inclusiveLanguage"], InclusiveLanguageConfig);
+	        this.contentSources = this.convertValues(source["contentSources"], ContentSource);
+	        this.settings = source["settings"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ProjectDashboard {
+	    project: string;
+	    generated: string;
+	    pages: number;
+	    words: number;
+	    diagnostics: DashboardCounts;
+	    trend: DashboardCounts[];
+	    status: Record<string, number>;
+	    overdueReviews: number;
+	    stalePages: DashboardPage[];
+	    recentlyEdited: DashboardPage[];
+	    recentCommits: GitLogEntry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ProjectDashboard(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.project = source["project"];
+	        this.generated = source["generated"];
+	        this.pages = source["pages"];
+	        this.words = source["words"];
+	        this.diagnostics = this.convertValues(source["diagnostics"], DashboardCounts);
+	        this.trend = this.convertValues(source["trend"], DashboardCounts);
+	        this.status = source["status"];
+	        this.overdueReviews = source["overdueReviews"];
+	        this.stalePages = this.convertValues(source["stalePages"], DashboardPage);
+	        this.recentlyEdited = this.convertValues(source["recentlyEdited"], DashboardPage);
+	        this.recentCommits = this.convertValues(source["recentCommits"], GitLogEntry);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ProjectStatus {
+	    path: string;
+	    available: boolean;
+	    reason?: string;
+	    message?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProjectStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.available = source["available"];
+	        this.reason = source["reason"];
+	        this.message = source["message"];
+	    }
+	}
+	
+	export class PublishTargetTest {
+	    name: string;
+	    ok: boolean;
+	    missing?: string[];
+	    needsApproval?: boolean;
+	    host?: string;
+	    status?: number;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PublishTargetTest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.ok = source["ok"];
+	        this.missing = source["missing"];
+	        this.needsApproval = source["needsApproval"];
+	        this.host = source["host"];
+	        this.status = source["status"];
+	        this.error = source["error"];
+	    }
+	}
+	export class PullRequest {
+	    number: number;
+	    url: string;
+	    head: string;
+	    base: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PullRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.number = source["number"];
+	        this.url = source["url"];
+	        this.head = source["head"];
+	        this.base = source["base"];
+	    }
+	}
+	export class TextEdit {
+	    line: number;
+	    column: number;
+	    endLine: number;
+	    endColumn: number;
+	    newText: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TextEdit(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.line = source["line"];
+	        this.column = source["column"];
+	        this.endLine = source["endLine"];
+	        this.endColumn = source["endColumn"];
+	        this.newText = source["newText"];
+	    }
+	}
+	export class QuickFix {
+	    id: string;
+	    diagnosticId: string;
+	    title: string;
+	    edits: TextEdit[];
+	
+	    static createFrom(source: any = {}) {
+	        return new QuickFix(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.diagnosticId = source["diagnosticId"];
+	        this.title = source["title"];
+	        this.edits = this.convertValues(source["edits"], TextEdit);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Redirect {
+	    from: string;
+	    to: string;
+	    added: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Redirect(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.from = source["from"];
+	        this.to = source["to"];
+	        this.added = source["added"];
+	    }
+	}
+	export class RedirectExport {
+	    files: string[];
+	    skipped: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RedirectExport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.files = source["files"];
+	        this.skipped = source["skipped"];
+	    }
+	}
+	
+	export class ReviewEntry {
+	    path: string;
+	    title: string;
+	    due: string;
+	    owner?: string;
+	    overdue: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReviewEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.title = source["title"];
+	        this.due = source["due"];
+	        this.owner = source["owner"];
+	        this.overdue = source["overdue"];
+	    }
+	}
+	export class ReviewItem {
+	    id: string;
+	    batchId: string;
+	    path: string;
+	    original: string;
+	    proposed: string;
+	    status: string;
+	    error: string;
+	    // Go type: time
+	    createdAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReviewItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.batchId = source["batchId"];
+	        this.path = source["path"];
+	        this.original = source["original"];
+	        this.proposed = source["proposed"];
+	        this.status = source["status"];
+	        this.error = source["error"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ReviewSchedule {
+	    outputPath: string;
+	    entries: ReviewEntry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ReviewSchedule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.outputPath = source["outputPath"];
+	        this.entries = this.convertValues(source["entries"], ReviewEntry);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class SaveResult {
+	    path: string;
+	    saved: boolean;
+	    error?: FileError;
+	
+	    static createFrom(source: any = {}) {
+	        return new SaveResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.saved = source["saved"];
+	        this.error = this.convertValues(source["error"], FileError);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SnapshotResult {
+	    folder: string;
+	    files: string[];
+	    pruned: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SnapshotResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.folder = source["folder"];
+	        this.files = source["files"];
+	        this.pruned = source["pruned"];
+	    }
+	}
+	
+	
+	
+	export class TitleSuggestions {
+	    sectionTitles: string[];
+	    pageTitles: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new TitleSuggestions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sectionTitles = source["sectionTitles"];
+	        this.pageTitles = source["pageTitles"];
+	    }
+	}
+	export class ToneIssue {
+	    excerpt: string;
+	    reason: string;
+	    suggestion: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ToneIssue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.excerpt = source["excerpt"];
+	        this.reason = source["reason"];
+	        this.suggestion = source["suggestion"];
+	    }
+	}
+	export class ToneReport {
+	    issues: ToneIssue[];
+	    rewrite: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ToneReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.issues = this.convertValues(source["issues"], ToneIssue);
+	        this.rewrite = source["rewrite"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TrashItem {
+	    id: string;
+	    project: string;
+	    originalPath: string;
+	    trashPath: string;
+	    isDir: boolean;
+	    // Go type: time
+	    deletedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new TrashItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.project = source["project"];
+	        this.originalPath = source["originalPath"];
+	        this.trashPath = source["trashPath"];
+	        this.isDir = source["isDir"];
+	        this.deletedAt = this.convertValues(source["deletedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WebClipperInfo {
+	    running: boolean;
+	    url?: string;
+	    token?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WebClipperInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.running = source["running"];
+	        this.url = source["url"];
+	        this.token = source["token"];
+	    }
+	}
 
 }
 
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// errNotRepo is returned by git operations outside a repository
+var errNotRepo = errors.New("not a git repository")
+
+// openRepo opens the repository holding path, searching parent folders
+func openRepo(path string) (*git.Repository, *git.Worktree, error) {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{DetectDotGit: true, EnableDotGitCommonDir: true})
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		return nil, nil, errNotRepo
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return repo, nil, err
+	}
+	return repo, wt, nil
+}
+
+// repoRelPath turns an absolute path into the slash-separated form go-git
+// uses, refusing paths outside the working tree
+func repoRelPath(wt *git.Worktree, path string) (string, error) {
+	root := wt.Filesystem.Root()
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the repository at %s", path, root)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// docsGitignore is written by GitInit when the project has no .gitignore.
+// .ndxcraft/config.yaml is shared with the team; drafts and trash are not.
+const docsGitignore = `# ndxCraft
+.ndxcraft/drafts/
+.ndxcraft-trash/
+
+# Build output
+build/
+dist/
+.asciidoctor/
+
+# Editors and OS
+.vscode/
+.idea/
+*.swp
+*~
+.DS_Store
+Thumbs.db
+desktop.ini
+`
+
+// GitInit puts a plain project folder under version control on branch
+// main, adding a .gitignore suited to a docs project unless it already has
+// one
+func (a *App) GitInit(projectPath string) error {
+	if exists(filepath.Join(projectPath, git.GitDirName)) {
+		return fmt.Errorf("%s is already a git repository", filepath.Base(projectPath))
+	}
+	_, err := git.PlainInitWithOptions(projectPath, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: plumbing.Main},
+	})
+	if err != nil {
+		return err
+	}
+	ignorePath := filepath.Join(projectPath, ".gitignore")
+	if !exists(ignorePath) {
+		if err := os.WriteFile(longPath(ignorePath), []byte(docsGitignore), 0644); err != nil {
+			return newFileError("write", ignorePath, err)
+		}
+	}
+	a.emitTreeChanged()
+	return nil
+}
+
+// GitCommit stages paths (deletions included) and commits them together
+// with anything already staged; with no paths it commits just the index.
+// The author comes from the user's git config. When the project has
+// pre-commit checks, staged documents must pass them first; failures come
+// back as a *PreCommitError. It returns the new commit hash.
+func (a *App) GitCommit(projectPath string, message string, paths []string) (string, error) {
+	if strings.TrimSpace(message) == "" {
+		return "", fmt.Errorf("commit message must not be empty")
+	}
+	repo, wt, err := openRepo(projectPath)
+	if err != nil {
+		return "", err
+	}
+
+	if err := stagePaths(wt, paths); err != nil {
+		return "", err
+	}
+
+	status, err := wt.Status()
+	if err != nil {
+		return "", err
+	}
+	staged := false
+	for _, fs := range status {
+		if fs.Staging != git.Unmodified && fs.Staging != git.Untracked {
+			staged = true
+			break
+		}
+	}
+	if !staged {
+		return "", fmt.Errorf("nothing to commit")
+	}
+	if err := a.preCommit(projectPath, repo, wt); err != nil {
+		return "", err
+	}
+
+	hash, err := wt.Commit(message, &git.CommitOptions{})
+	if errors.Is(err, git.ErrMissingAuthor) {
+		return "", fmt.Errorf("set user.name and user.email in your git config to commit")
+	}
+	if err != nil {
+		return "", err
+	}
+	return hash.String(), nil
+}
+
+// GitChange is a changed file in a staging panel
+type GitChange struct {
+	Path  string `json:"path"`
+	State string `json:"state"`
+}
+
+// GitStagedStatus splits a repository's changes into staged and unstaged
+type GitStagedStatus struct {
+	Branch   string      `json:"branch,omitempty"`
+	Staged   []GitChange `json:"staged"`
+	Unstaged []GitChange `json:"unstaged"`
+}
+
+// GetGitStagedStatus lists what is staged for the next commit and what
+// isn't. A file with staged and further unstaged edits is in both lists.
+func (a *App) GetGitStagedStatus(projectPath string) (*GitStagedStatus, error) {
+	repo, wt, err := openRepo(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := wt.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GitStagedStatus{Staged: []GitChange{}, Unstaged: []GitChange{}}
+	if head, err := repo.Head(); err == nil && head.Name().IsBranch() {
+		result.Branch = head.Name().Short()
+	}
+	root := wt.Filesystem.Root()
+	for rel, fs := range changes {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if state := gitStatusCodeState(fs.Staging); state != "" && fs.Staging != git.Untracked {
+			result.Staged = append(result.Staged, GitChange{Path: path, State: state})
+		}
+		if state := gitStatusCodeState(fs.Worktree); state != "" {
+			result.Unstaged = append(result.Unstaged, GitChange{Path: path, State: state})
+		}
+	}
+	sort.Slice(result.Staged, func(i, j int) bool { return result.Staged[i].Path < result.Staged[j].Path })
+	sort.Slice(result.Unstaged, func(i, j int) bool { return result.Unstaged[i].Path < result.Unstaged[j].Path })
+	return result, nil
+}
+
+// gitStatusCodeState names a single index or worktree status code
+func gitStatusCodeState(code git.StatusCode) string {
+	switch code {
+	case git.Modified:
+		return GitModified
+	case git.Added:
+		return GitAdded
+	case git.Deleted:
+		return GitDeleted
+	case git.Renamed, git.Copied:
+		return GitRenamed
+	case git.Untracked:
+		return GitUntracked
+	case git.UpdatedButUnmerged:
+		return GitConflicted
+	}
+	return ""
+}
+
+// GitStage adds files (or removals of deleted files) to the index. All
+// paths must be in the same repository.
+func (a *App) GitStage(paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	_, wt, err := openRepo(filepath.Dir(paths[0]))
+	if err != nil {
+		return err
+	}
+	return stagePaths(wt, paths)
+}
+
+// GitUnstage takes files out of the index again, keeping their changes in
+// the working tree
+func (a *App) GitUnstage(paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	repo, wt, err := openRepo(filepath.Dir(paths[0]))
+	if err != nil {
+		return err
+	}
+	rels := make([]string, 0, len(paths))
+	for _, p := range paths {
+		rel, err := repoRelPath(wt, p)
+		if err != nil {
+			return err
+		}
+		rels = append(rels, rel)
+	}
+
+	if _, err := repo.Head(); errors.Is(err, plumbing.ErrReferenceNotFound) {
+		// Nothing committed yet: unstaging means dropping the entries
+		idx, err := repo.Storer.Index()
+		if err != nil {
+			return err
+		}
+		for _, rel := range rels {
+			_, _ = idx.Remove(rel)
+		}
+		return repo.Storer.SetIndex(idx)
+	}
+	return wt.Restore(&git.RestoreOptions{Staged: true, Files: rels})
+}
+
+func stagePaths(wt *git.Worktree, paths []string) error {
+	for _, p := range paths {
+		rel, err := repoRelPath(wt, p)
+		if err != nil {
+			return err
+		}
+		if _, err := wt.Add(rel); err != nil {
+			return fmt.Errorf("failed to stage %s: %w", p, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// errDirtyWorktree is returned when switching branches would overwrite
+// uncommitted changes
+var errDirtyWorktree = errors.New("commit or discard your changes before switching branches")
+
+// GitBranch is a local or remote-tracking branch
+type GitBranch struct {
+	Name string `json:"name"`
+	Head string `json:"head"`
+	// Current is set for the checked-out branch
+	Current bool `json:"current,omitempty"`
+	// Remote is set for remote-tracking branches such as origin/main
+	Remote bool `json:"remote,omitempty"`
+}
+
+// GitListBranches lists the repository's local branches, then its
+// remote-tracking ones, each sorted by name
+func (a *App) GitListBranches(projectPath string) ([]GitBranch, error) {
+	repo, _, err := openRepo(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	current := ""
+	if ref, err := repo.Storer.Reference(plumbing.HEAD); err == nil && ref.Type() == plumbing.SymbolicReference {
+		current = ref.Target().String()
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+	defer refs.Close()
+	branches := []GitBranch{}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name()
+		if ref.Type() != plumbing.HashReference || !(name.IsBranch() || name.IsRemote()) {
+			return nil
+		}
+		branches = append(branches, GitBranch{
+			Name:    name.Short(),
+			Head:    ref.Hash().String(),
+			Current: name.String() == current,
+			Remote:  name.IsRemote(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(branches, func(i, j int) bool {
+		if branches[i].Remote != branches[j].Remote {
+			return !branches[i].Remote
+		}
+		return branches[i].Name < branches[j].Name
+	})
+	return branches, nil
+}
+
+// GitCreateBranch creates a branch at the current commit and optionally
+// switches to it. Switching to a new branch at HEAD keeps uncommitted
+// changes, as git switch -c does.
+func (a *App) GitCreateBranch(projectPath string, name string, checkout bool) error {
+	repo, wt, err := openRepo(projectPath)
+	if err != nil {
+		return err
+	}
+	refName := plumbing.NewBranchReferenceName(strings.TrimSpace(name))
+	if err := refName.Validate(); err != nil {
+		return fmt.Errorf("invalid branch name %q", name)
+	}
+	if _, err := repo.Reference(refName, false); err == nil {
+		return fmt.Errorf("branch %s already exists", name)
+	}
+	head, err := repo.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return fmt.Errorf("commit something before creating a branch")
+	}
+	if err != nil {
+		return err
+	}
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(refName, head.Hash())); err != nil {
+		return err
+	}
+	if !checkout {
+		return nil
+	}
+	if wt == nil {
+		return fmt.Errorf("repository has no working tree")
+	}
+	return wt.Checkout(&git.CheckoutOptions{Branch: refName, Keep: true})
+}
+
+// GitCheckout switches to a local branch, a remote branch (creating a
+// local branch of the same name), a tag or a commit. It refuses while
+// tracked files have uncommitted changes; untracked files are left alone.
+func (a *App) GitCheckout(projectPath string, ref string) error {
+	repo, wt, err := openRepo(projectPath)
+	if err != nil {
+		return err
+	}
+	if wt == nil {
+		return fmt.Errorf("repository has no working tree")
+	}
+
+	status, err := wt.Status()
+	if err != nil {
+		return err
+	}
+	for _, fs := range status {
+		if fs.Worktree != git.Untracked && (fs.Staging != git.Unmodified || fs.Worktree != git.Unmodified) {
+			return errDirtyWorktree
+		}
+	}
+
+	opts, err := checkoutTarget(repo, ref)
+	if err != nil {
+		return err
+	}
+	if err := wt.Checkout(opts); err != nil {
+		if errors.Is(err, git.ErrUnstagedChanges) {
+			return errDirtyWorktree
+		}
+		return err
+	}
+	a.emitTreeChanged()
+	return nil
+}
+
+// checkoutTarget resolves what GitCheckout was asked to switch to
+func checkoutTarget(repo *git.Repository, ref string) (*git.CheckoutOptions, error) {
+	local := plumbing.NewBranchReferenceName(ref)
+	if _, err := repo.Reference(local, false); err == nil {
+		return &git.CheckoutOptions{Branch: local}, nil
+	}
+
+	remote := plumbing.ReferenceName("refs/remotes/" + ref)
+	if r, err := repo.Reference(remote, true); err == nil {
+		_, branch, ok := strings.Cut(ref, "/")
+		if !ok {
+			return nil, fmt.Errorf("unknown branch %s", ref)
+		}
+		local = plumbing.NewBranchReferenceName(branch)
+		if _, err := repo.Reference(local, false); err == nil {
+			return nil, fmt.Errorf("branch %s already exists; check it out instead", branch)
+		}
+		return &git.CheckoutOptions{Branch: local, Hash: r.Hash(), Create: true}, nil
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, fmt.Errorf("unknown branch, tag or commit %s", ref)
+	}
+	return &git.CheckoutOptions{Hash: *hash}, nil
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+// Operations that can leave a repository with conflicts
+const (
+	GitOpMerge      = "merge"
+	GitOpRebase     = "rebase"
+	GitOpCherryPick = "cherry-pick"
+	GitOpRevert     = "revert"
+)
+
+// GitMergeState says whether a merge-like operation is waiting to be
+// finished and which files still have conflicts
+type GitMergeState struct {
+	// Operation is empty when nothing is in progress
+	Operation string `json:"operation,omitempty"`
+	// Conflicted holds absolute paths of unmerged files
+	Conflicted []string `json:"conflicted"`
+}
+
+// ConflictSection is one <<<<<<< ... >>>>>>> block of a conflicted file.
+// Lines are 1-based and include the marker lines.
+type ConflictSection struct {
+	StartLine   int    `json:"startLine"`
+	EndLine     int    `json:"endLine"`
+	OursLabel   string `json:"oursLabel"`
+	TheirsLabel string `json:"theirsLabel"`
+	Ours        string `json:"ours"`
+	Theirs      string `json:"theirs"`
+	// Base is the common ancestor, present with merge.conflictStyle diff3
+	Base    string `json:"base,omitempty"`
+	HasBase bool   `json:"hasBase,omitempty"`
+}
+
+// GetGitMergeState reports an unfinished merge, rebase, cherry-pick or
+// revert and the files left with conflicts
+func (a *App) GetGitMergeState(projectPath string) (*GitMergeState, error) {
+	repo, wt, err := openRepo(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if wt == nil {
+		return nil, fmt.Errorf("repository has no working tree")
+	}
+	state := &GitMergeState{Operation: gitOperationInProgress(repo), Conflicted: []string{}}
+	conflicted, err := conflictedPaths(repo)
+	if err != nil {
+		return nil, err
+	}
+	root := wt.Filesystem.Root()
+	for _, rel := range conflicted {
+		state.Conflicted = append(state.Conflicted, filepath.Join(root, filepath.FromSlash(rel)))
+	}
+	return state, nil
+}
+
+// GetConflictSections parses the conflict markers in a file as it is on
+// disk. A file without markers returns no sections.
+func (a *App) GetConflictSections(path string) ([]ConflictSection, error) {
+	content, err := os.ReadFile(longPath(path))
+	if err != nil {
+		return nil, newFileError("read", path, err)
+	}
+	return parseConflictSections(string(content))
+}
+
+// parseConflictSections finds marker blocks. Markers must start a line
+// and be exactly seven characters, followed by a space or the line end.
+func parseConflictSections(content string) ([]ConflictSection, error) {
+	const (
+		outside = iota
+		inOurs
+		inBase
+		inTheirs
+	)
+	sections := []ConflictSection{}
+	var cur ConflictSection
+	var ours, base, theirs strings.Builder
+	state := outside
+	for i, line := range strings.SplitAfter(content, "\n") {
+		if line == "" {
+			continue
+		}
+		n := i + 1
+		marker, label := conflictMarker(line)
+		switch {
+		case marker == "<<<<<<<":
+			if state != outside {
+				return nil, fmt.Errorf("line %d: conflict starts inside another conflict", n)
+			}
+			cur = ConflictSection{StartLine: n, OursLabel: label}
+			ours.Reset()
+			base.Reset()
+			theirs.Reset()
+			state = inOurs
+		case marker == "|||||||" && state == inOurs:
+			cur.HasBase = true
+			state = inBase
+		case marker == "=======" && (state == inOurs || state == inBase):
+			state = inTheirs
+		case marker == ">>>>>>>" && state == inTheirs:
+			cur.EndLine = n
+			cur.TheirsLabel = label
+			cur.Ours, cur.Base, cur.Theirs = ours.String(), base.String(), theirs.String()
+			sections = append(sections, cur)
+			state = outside
+		case state == inOurs:
+			ours.WriteString(line)
+		case state == inBase:
+			base.WriteString(line)
+		case state == inTheirs:
+			theirs.WriteString(line)
+		}
+	}
+	if state != outside {
+		return nil, fmt.Errorf("line %d: conflict is not closed with >>>>>>>", cur.StartLine)
+	}
+	return sections, nil
+}
+
+// conflictMarker returns the marker a line starts with and its label
+func conflictMarker(line string) (string, string) {
+	line = strings.TrimRight(line, "\r\n")
+	if len(line) < 7 {
+		return "", ""
+	}
+	marker, rest := line[:7], line[7:]
+	switch marker {
+	case "<<<<<<<", "|||||||", "=======", ">>>>>>>":
+	default:
+		return "", ""
+	}
+	if rest != "" && rest[0] != ' ' {
+		return "", ""
+	}
+	if marker == "=======" && rest != "" {
+		return "", ""
+	}
+	return marker, strings.TrimSpace(rest)
+}
+
+// conflictedPaths lists the files with unmerged entries in the index.
+// go-git's Status reports these as modified.
+func conflictedPaths(repo *git.Repository) ([]string, error) {
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	paths := []string{}
+	for _, e := range idx.Entries {
+		if e.Stage != index.Merged && !seen[e.Name] {
+			seen[e.Name] = true
+			paths = append(paths, e.Name)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// gitOperationInProgress looks for the state files git leaves in the git
+// directory while an operation waits for conflicts to be resolved
+func gitOperationInProgress(repo *git.Repository) string {
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return ""
+	}
+	dir := storage.Filesystem().Root()
+	switch {
+	case exists(filepath.Join(dir, "rebase-merge")), exists(filepath.Join(dir, "rebase-apply")):
+		return GitOpRebase
+	case exists(filepath.Join(dir, "MERGE_HEAD")):
+		return GitOpMerge
+	case exists(filepath.Join(dir, "CHERRY_PICK_HEAD")):
+		return GitOpCherryPick
+	case exists(filepath.Join(dir, "REVERT_HEAD")):
+		return GitOpRevert
+	}
+	return ""
+}
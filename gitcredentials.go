@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+// Ways a remote authenticates
+const (
+	GitAuthNone     = "none"
+	GitAuthToken    = "token"
+	GitAuthSSHAgent = "sshAgent"
+	GitAuthSSHKey   = "sshKey"
+)
+
+// gitSSHKeySetting names the private key used for SSH remotes; the SSH
+// agent is used when it is empty
+const gitSSHKeySetting = "git_ssh_key"
+
+// gitSSHPassphraseSecret is the keychain secret holding the passphrase of
+// the configured SSH key
+const gitSSHPassphraseSecret = "git-ssh-passphrase"
+
+// GitCredentials describes how a remote authenticates. Tokens and
+// passphrases stay in the keychain.
+type GitCredentials struct {
+	Remote string `json:"remote"`
+	URL    string `json:"url"`
+	Host   string `json:"host"`
+	// Method is one of the GitAuth constants
+	Method   string `json:"method"`
+	Username string `json:"username,omitempty"`
+	// SSHKey is the private key file used instead of the agent
+	SSHKey string `json:"sshKey,omitempty"`
+}
+
+// GitConnectionTest is the outcome of TestGitConnection
+type GitConnectionTest struct {
+	GitCredentials
+	OK bool `json:"ok"`
+	// Branches is how many branches the remote advertised
+	Branches int       `json:"branches"`
+	Error    *GitError `json:"error,omitempty"`
+}
+
+// GetGitCredentials reports how remote ("origin" when empty) would
+// authenticate
+func (a *App) GetGitCredentials(projectPath string, remote string) (*GitCredentials, error) {
+	r, url, err := projectRemoteURL(projectPath, remote)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := a.gitAuth(url)
+	if err != nil {
+		return nil, err
+	}
+	return a.describeGitAuth(r.Config().Name, url, auth), nil
+}
+
+// SetGitToken stores the access token and username for the HTTPS host of
+// remote ("origin" when empty). Every remote on that host uses them.
+func (a *App) SetGitToken(projectPath string, remote string, username string, token string) error {
+	host, err := projectRemoteHost(projectPath, remote)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(token) == "" {
+		return fmt.Errorf("token must not be empty")
+	}
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	if err := a.SetSecret(gitTokenSecret(host), token); err != nil {
+		return err
+	}
+	return db.SetAppState(gitUsernameKey(host), strings.TrimSpace(username))
+}
+
+// DeleteGitToken forgets the token stored for the host of remote
+func (a *App) DeleteGitToken(projectPath string, remote string) error {
+	host, err := projectRemoteHost(projectPath, remote)
+	if err != nil {
+		return err
+	}
+	if db != nil {
+		if err := db.SetAppState(gitUsernameKey(host), ""); err != nil {
+			return err
+		}
+	}
+	return a.DeleteSecret(gitTokenSecret(host))
+}
+
+// SetGitSSHKey makes SSH remotes use the private key at keyPath, whose
+// passphrase, if any, goes to the keychain. An empty keyPath goes back to
+// the SSH agent.
+func (a *App) SetGitSSHKey(keyPath string, passphrase string) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	if keyPath == "" {
+		if err := a.DeleteSecret(gitSSHPassphraseSecret); err != nil {
+			return err
+		}
+		return db.SetPreference(gitSSHKeySetting, "")
+	}
+	if _, err := gitssh.NewPublicKeysFromFile("git", keyPath, passphrase); err != nil {
+		if os.IsNotExist(err) {
+			return newFileError("read", keyPath, err)
+		}
+		return fmt.Errorf("can't use %s as an SSH key: %w", keyPath, err)
+	}
+	if passphrase != "" {
+		if err := a.SetSecret(gitSSHPassphraseSecret, passphrase); err != nil {
+			return err
+		}
+	} else if err := a.DeleteSecret(gitSSHPassphraseSecret); err != nil {
+		return err
+	}
+	return db.SetPreference(gitSSHKeySetting, keyPath)
+}
+
+// TestGitConnection lists the branches of remote ("origin" when empty)
+// with the credentials push and pull would use. Failures are reported in
+// the result, classified like push and pull errors, so the settings panel
+// can show what to fix.
+func (a *App) TestGitConnection(projectPath string, remote string) (*GitConnectionTest, error) {
+	r, url, err := projectRemoteURL(projectPath, remote)
+	if err != nil {
+		return nil, err
+	}
+	name := r.Config().Name
+	auth, err := a.gitAuth(url)
+	if err != nil {
+		return &GitConnectionTest{
+			GitCredentials: *a.describeGitAuth(name, url, nil),
+			Error:          &GitError{Op: "connect", Remote: name, Code: GitErrAuth, Message: err.Error(), Hint: "Add your SSH key to the agent, or choose a key file", err: err},
+		}, nil
+	}
+	result := &GitConnectionTest{GitCredentials: *a.describeGitAuth(name, url, auth)}
+	refs, err := r.List(&git.ListOptions{Auth: auth})
+	if err != nil {
+		result.Error = newGitError("connect", name, err)
+		if result.Error.Code == GitErrAuth {
+			result.Error.Message = fmt.Sprintf("%s refused the credentials", name)
+			result.Error.Hint = gitAuthHint(result.Method)
+		}
+		return result, nil
+	}
+	result.OK = true
+	for _, ref := range refs {
+		if ref.Name().IsBranch() {
+			result.Branches++
+		}
+	}
+	return result, nil
+}
+
+// describeGitAuth summarizes the credentials picked for a remote
+func (a *App) describeGitAuth(remote, url string, auth transport.AuthMethod) *GitCredentials {
+	creds := &GitCredentials{Remote: remote, URL: url, Method: GitAuthNone}
+	if endpoint, err := transport.NewEndpoint(url); err == nil {
+		creds.Host = endpoint.Host
+	}
+	switch auth := auth.(type) {
+	case *githttp.BasicAuth:
+		creds.Method = GitAuthToken
+		creds.Username = auth.Username
+	case *gitssh.PublicKeys:
+		creds.Method = GitAuthSSHKey
+		creds.Username = auth.User
+		creds.SSHKey = a.userSettingString(gitSSHKeySetting, "")
+	case *gitssh.PublicKeysCallback:
+		creds.Method = GitAuthSSHAgent
+		creds.Username = auth.User
+	}
+	return creds
+}
+
+// gitAuthHint says what to change when a remote refuses the credentials
+func gitAuthHint(method string) string {
+	switch method {
+	case GitAuthToken:
+		return "The access token may have expired or lack access to this repository; store a new one"
+	case GitAuthSSHKey:
+		return "Add the key's public half to your account on the host, or choose another key"
+	case GitAuthSSHAgent:
+		return "Add your key to the agent with ssh-add, or choose a key file"
+	}
+	return "Store an access token for this host"
+}
+
+// gitTokenSecret is the keychain secret holding the token for a host
+func gitTokenSecret(host string) string {
+	return defaultGitTokenSecret + ":" + strings.ToLower(host)
+}
+
+// gitUsernameKey is the app state key of the username for a host
+func gitUsernameKey(host string) string {
+	return "git_username:" + strings.ToLower(host)
+}
+
+// projectRemoteURL looks up remote ("origin" when empty) and its URL
+func projectRemoteURL(projectPath, remote string) (*git.Remote, string, error) {
+	repo, _, err := openRepo(projectPath)
+	if err != nil {
+		return nil, "", err
+	}
+	if remote == "" {
+		remote = git.DefaultRemoteName
+	}
+	r, err := repo.Remote(remote)
+	if err != nil {
+		return nil, "", &GitError{Op: "connect", Remote: remote, Code: GitErrNoRemote,
+			Message: fmt.Sprintf("remote %q is not configured", remote), err: err}
+	}
+	return r, r.Config().URLs[0], nil
+}
+
+// projectRemoteHost returns the host of an HTTPS remote, which its token
+// is stored under
+func projectRemoteHost(projectPath, remote string) (string, error) {
+	_, url, err := projectRemoteURL(projectPath, remote)
+	if err != nil {
+		return "", err
+	}
+	endpoint, err := transport.NewEndpoint(url)
+	if err != nil {
+		return "", err
+	}
+	if endpoint.Protocol != "http" && endpoint.Protocol != "https" {
+		return "", fmt.Errorf("%s is not an HTTPS remote; SSH remotes use a key instead of a token", url)
+	}
+	return endpoint.Host, nil
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/utils/diff"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// diffContext is the number of unchanged lines shown around a change
+const diffContext = 3
+
+// Line kinds in a GitDiffHunk
+const (
+	DiffContext = "context"
+	DiffAdd     = "add"
+	DiffDelete  = "delete"
+)
+
+// GitDiff is the result of GitDiffFile
+type GitDiff struct {
+	Path   string `json:"path"`
+	Staged bool   `json:"staged"`
+	// Binary is set for files that can't be diffed line by line; Diff and
+	// Hunks are empty then
+	Binary bool          `json:"binary,omitempty"`
+	Diff   string        `json:"diff"`
+	Hunks  []GitDiffHunk `json:"hunks"`
+}
+
+// GitDiffHunk is one block of changes with its surrounding context
+type GitDiffHunk struct {
+	OldStart int           `json:"oldStart"`
+	OldLines int           `json:"oldLines"`
+	NewStart int           `json:"newStart"`
+	NewLines int           `json:"newLines"`
+	Lines    []GitDiffLine `json:"lines"`
+}
+
+// GitDiffLine is a line of a hunk. OldLine and NewLine are 1-based and 0
+// on the side the line doesn't exist.
+type GitDiffLine struct {
+	Kind    string `json:"kind"`
+	Text    string `json:"text"`
+	OldLine int    `json:"oldLine,omitempty"`
+	NewLine int    `json:"newLine,omitempty"`
+	// NoNewline marks a last line without a line break
+	NoNewline bool `json:"noNewline,omitempty"`
+}
+
+// GitDiffFile diffs a file like git diff does: unstaged changes compare
+// the working file with the index, staged ones compare the index with
+// HEAD. The result has both a unified diff and structured hunks for the
+// changes gutter.
+func (a *App) GitDiffFile(path string, staged bool) (*GitDiff, error) {
+	repo, wt, err := openRepo(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	if wt == nil {
+		return nil, fmt.Errorf("repository has no working tree")
+	}
+	rel, err := repoRelPath(wt, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var before, after []byte
+	if staged {
+		if before, err = headContent(repo, rel); err != nil {
+			return nil, err
+		}
+		if after, err = indexContent(repo, rel); err != nil {
+			return nil, err
+		}
+	} else {
+		if before, err = indexContent(repo, rel); err != nil {
+			return nil, err
+		}
+		after, err = os.ReadFile(longPath(path))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, newFileError("read", path, err)
+		}
+	}
+
+	result := &GitDiff{Path: path, Staged: staged, Hunks: []GitDiffHunk{}}
+	if bytes.IndexByte(before, 0) >= 0 || bytes.IndexByte(after, 0) >= 0 {
+		result.Binary = !bytes.Equal(before, after)
+		return result, nil
+	}
+	result.Hunks = diffHunks(string(before), string(after))
+	if len(result.Hunks) > 0 {
+		result.Diff = unifiedDiff(rel, result.Hunks)
+	}
+	return result, nil
+}
+
+// headContent reads a file from the HEAD commit; nil if it isn't there
+func headContent(repo *git.Repository, rel string) ([]byte, error) {
+	head, err := repo.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+	file, err := commit.File(rel)
+	if err != nil {
+		return nil, nil
+	}
+	content, err := file.Contents()
+	return []byte(content), err
+}
+
+// indexContent reads a file as staged; nil if it isn't in the index
+func indexContent(repo *git.Repository, rel string) ([]byte, error) {
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+	entry, err := idx.Entry(rel)
+	if err != nil {
+		return nil, nil
+	}
+	blob, err := repo.BlobObject(entry.Hash)
+	if err != nil {
+		return nil, err
+	}
+	r, err := blob.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// diffHunks compares two texts line by line and groups the changes into
+// hunks with diffContext lines of context, merging hunks that touch
+func diffHunks(before, after string) []GitDiffHunk {
+	var lines []GitDiffLine
+	oldLine, newLine := 1, 1
+	for _, d := range diff.Do(before, after) {
+		for _, text := range strings.SplitAfter(d.Text, "\n") {
+			if text == "" {
+				continue
+			}
+			line := GitDiffLine{Text: strings.TrimSuffix(text, "\n"), NoNewline: !strings.HasSuffix(text, "\n")}
+			switch d.Type {
+			case diffmatchpatch.DiffEqual:
+				line.Kind, line.OldLine, line.NewLine = DiffContext, oldLine, newLine
+				oldLine++
+				newLine++
+			case diffmatchpatch.DiffDelete:
+				line.Kind, line.OldLine = DiffDelete, oldLine
+				oldLine++
+			case diffmatchpatch.DiffInsert:
+				line.Kind, line.NewLine = DiffAdd, newLine
+				newLine++
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	hunks := []GitDiffHunk{}
+	for i := 0; i < len(lines); {
+		if lines[i].Kind == DiffContext {
+			i++
+			continue
+		}
+		start := max(i-diffContext, 0)
+		end := i
+		for j := i; j < len(lines); j++ {
+			if lines[j].Kind != DiffContext {
+				end = j + 1
+			} else if j-end >= 2*diffContext {
+				break
+			}
+		}
+		end = min(end+diffContext, len(lines))
+		hunks = append(hunks, newHunk(lines[start:end], lines[:start]))
+		i = end
+	}
+	return hunks
+}
+
+// newHunk builds a hunk header from its lines and the lines before it
+func newHunk(lines, before []GitDiffLine) GitDiffHunk {
+	h := GitDiffHunk{Lines: lines}
+	for _, l := range before {
+		if l.Kind != DiffAdd {
+			h.OldStart++
+		}
+		if l.Kind != DiffDelete {
+			h.NewStart++
+		}
+	}
+	for _, l := range lines {
+		if l.Kind != DiffAdd {
+			h.OldLines++
+		}
+		if l.Kind != DiffDelete {
+			h.NewLines++
+		}
+	}
+	// Like git, an empty side starts at the line before the hunk
+	if h.OldLines > 0 {
+		h.OldStart++
+	}
+	if h.NewLines > 0 {
+		h.NewStart++
+	}
+	return h
+}
+
+// unifiedDiff renders hunks in the format of git diff
+func unifiedDiff(rel string, hunks []GitDiffHunk) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", rel, rel)
+	for _, h := range hunks {
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(h.OldStart, h.OldLines), hunkRange(h.NewStart, h.NewLines))
+		for _, l := range h.Lines {
+			switch l.Kind {
+			case DiffAdd:
+				b.WriteByte('+')
+			case DiffDelete:
+				b.WriteByte('-')
+			default:
+				b.WriteByte(' ')
+			}
+			b.WriteString(l.Text)
+			b.WriteByte('\n')
+			if l.NoNewline {
+				b.WriteString("\\ No newline at end of file\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
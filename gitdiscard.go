@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/google/uuid"
+)
+
+// discardTokenTTL is how long a token from PrepareDiscardChanges stays
+// valid
+const discardTokenTTL = 5 * time.Minute
+
+// DiscardPreview is what GitDiscardChanges would throw away. The token
+// must be passed back to confirm.
+type DiscardPreview struct {
+	Token string `json:"token"`
+	Path  string `json:"path"`
+	// Diff is the unified diff from HEAD to the file on disk
+	Diff string `json:"diff"`
+	// Deleted is set when the file is missing and will be brought back
+	Deleted bool `json:"deleted,omitempty"`
+	// UnsavedEdits is set when the editor buffer has changes that were not
+	// saved yet; they are dropped too
+	UnsavedEdits bool `json:"unsavedEdits,omitempty"`
+}
+
+// pendingDiscard ties a confirmation token to the file content and the
+// editor's shadow copy it was shown for
+type pendingDiscard struct {
+	path    string
+	hash    [sha256.Size]byte
+	expires time.Time
+}
+
+// PrepareDiscardChanges shows what discarding the changes to a file would
+// lose and returns the token GitDiscardChanges needs
+func (a *App) PrepareDiscardChanges(path string) (*DiscardPreview, error) {
+	repo, wt, err := openRepo(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	if wt == nil {
+		return nil, fmt.Errorf("repository has no working tree")
+	}
+	rel, err := repoRelPath(wt, path)
+	if err != nil {
+		return nil, err
+	}
+	head, err := headContent(repo, rel)
+	if err != nil {
+		return nil, err
+	}
+	if head == nil {
+		return nil, fmt.Errorf("%s is not in the last commit; delete it instead", filepath.Base(path))
+	}
+	current, err := os.ReadFile(longPath(path))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, newFileError("read", path, err)
+	}
+
+	preview := &DiscardPreview{Token: uuid.New().String(), Path: path, Deleted: os.IsNotExist(err)}
+	if !bytes.Equal(head, current) || preview.Deleted {
+		preview.Diff = unifiedDiff(rel, diffHunks(string(head), string(current)))
+	}
+	if db != nil {
+		if _, dirty, err := db.GetShadowFile(path); err == nil {
+			preview.UnsavedEdits = dirty
+		}
+	}
+
+	a.discardMu.Lock()
+	if a.discards == nil {
+		a.discards = make(map[string]pendingDiscard)
+	}
+	for token, p := range a.discards {
+		if p.path == path || time.Now().After(p.expires) {
+			delete(a.discards, token)
+		}
+	}
+	a.discards[preview.Token] = pendingDiscard{path: path, hash: discardState(path, current), expires: time.Now().Add(discardTokenTTL)}
+	a.discardMu.Unlock()
+	return preview, nil
+}
+
+// GitDiscardChanges restores a file, in the working tree and the index, to
+// how it is in HEAD, with its mode there. token comes from
+// PrepareDiscardChanges; it is refused when the file or the editor's
+// unsaved edits changed since. The editor's shadow copy is dropped with
+// the changes, and the restored content is returned for the buffer. The
+// discard can be undone.
+func (a *App) GitDiscardChanges(path string, token string) (string, error) {
+	a.discardMu.Lock()
+	pending, ok := a.discards[token]
+	delete(a.discards, token)
+	a.discardMu.Unlock()
+	if !ok || pending.path != path || time.Now().After(pending.expires) {
+		return "", fmt.Errorf("confirm discarding the changes to %s again", filepath.Base(path))
+	}
+
+	repo, wt, err := openRepo(filepath.Dir(path))
+	if err != nil {
+		return "", err
+	}
+	if wt == nil {
+		return "", fmt.Errorf("repository has no working tree")
+	}
+	rel, err := repoRelPath(wt, path)
+	if err != nil {
+		return "", err
+	}
+	current, err := os.ReadFile(longPath(path))
+	if err != nil && !os.IsNotExist(err) {
+		return "", newFileError("read", path, err)
+	}
+	if discardState(path, current) != pending.hash {
+		return "", fmt.Errorf("%s changed since; review the changes again", filepath.Base(path))
+	}
+	head, err := headContent(repo, rel)
+	if err != nil {
+		return "", err
+	}
+	if head == nil {
+		return "", fmt.Errorf("%s is not in the last commit", filepath.Base(path))
+	}
+	mode, err := headFileMode(repo, rel)
+	if err != nil {
+		return "", err
+	}
+
+	op := a.beginOperation("Discard changes to " + filepath.Base(path))
+	if exists(path) {
+		err = op.replacing(path)
+	} else {
+		op.created(path)
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+	}
+	if err != nil {
+		op.discard()
+		return "", err
+	}
+	if mode&os.ModeSymlink != 0 {
+		os.Remove(longPath(path))
+		err = os.Symlink(string(head), longPath(path))
+	} else {
+		err = writeFileAtomic(path, head, mode.Perm())
+	}
+	if err != nil {
+		op.discard()
+		return "", newFileError("save", path, err)
+	}
+	a.commitOperation(op)
+	if err := wt.Restore(&git.RestoreOptions{Staged: true, Files: []string{rel}}); err != nil {
+		return "", err
+	}
+
+	if db != nil {
+		if err := db.ClearShadowFile(path); err != nil {
+			return "", err
+		}
+	}
+	a.rememberStamp(path, head)
+	a.emitTreeChanged()
+	return string(head), nil
+}
+
+// discardState fingerprints what a discard throws away: the file on disk
+// and the editor's shadow copy of it
+func discardState(path string, current []byte) [sha256.Size]byte {
+	h := sha256.New()
+	h.Write(current)
+	if db != nil {
+		if shadow, dirty, err := db.GetShadowFile(path); err == nil {
+			fmt.Fprintf(h, "\x00%t\x00%s", dirty, shadow)
+		}
+	}
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+// headFileMode returns the mode a file has in HEAD, so a restored script
+// stays executable and a link stays a link
+func headFileMode(repo *git.Repository, rel string) (os.FileMode, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return 0, err
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return 0, err
+	}
+	file, err := commit.File(rel)
+	if err != nil {
+		return 0, err
+	}
+	return file.Mode.ToOSFileMode()
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// EventGitRemoteAhead is emitted with a GitTracking when a fetch finds new
+// commits on the branch the current one tracks
+const EventGitRemoteAhead = "git:remoteAhead"
+
+// Auto-fetch settings and their defaults. The "git_autofetch_minutes"
+// setting changes the interval; 0 turns auto-fetch off.
+const (
+	defaultAutoFetchInterval = 15 * time.Minute
+	autoFetchLastRunKey      = "git_autofetch_last:"
+	autoFetchAttemptKey      = "git_autofetch_attempt:"
+	autoFetchNotifiedKey     = "git_autofetch_notified:"
+)
+
+// GitTracking compares the current branch with the remote branch it
+// tracks, as last fetched
+type GitTracking struct {
+	Branch string `json:"branch"`
+	// Upstream is the tracked branch, such as origin/main
+	Upstream string `json:"upstream"`
+	Remote   string `json:"remote"`
+	// Ahead counts local commits not pushed, Behind remote commits not
+	// pulled
+	Ahead  int    `json:"ahead"`
+	Behind int    `json:"behind"`
+	Commit string `json:"commit,omitempty"`
+	// Fetched is when the remote was last fetched by ndxCraft
+	Fetched string `json:"fetched,omitempty"`
+}
+
+// GitFetch fetches the remote the current branch tracks and compares the
+// two. EventGitRemoteAhead is emitted the first time new remote commits
+// are seen.
+func (a *App) GitFetch(projectPath string) (*GitTracking, error) {
+	repo, _, err := openRepo(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	branch, remote, merge, err := trackedBranch(repo)
+	if err != nil {
+		return nil, err
+	}
+	r, err := repo.Remote(remote)
+	if err != nil {
+		return nil, &GitError{Op: "fetch", Remote: remote, Code: GitErrNoRemote,
+			Message: fmt.Sprintf("remote %q is not configured", remote), err: err}
+	}
+	root := repoRoot(repo, projectPath)
+	if db != nil {
+		// Failed attempts count too, so an unreachable remote waits for the
+		// next interval
+		_ = db.SetAppState(autoFetchAttemptKey+root, time.Now().Format(time.RFC3339))
+	}
+	auth, err := a.gitAuth(r.Config().URLs[0])
+	if err != nil {
+		return nil, &GitError{Op: "fetch", Remote: remote, Code: GitErrAuth, Message: err.Error(), err: err}
+	}
+	err = repo.Fetch(&git.FetchOptions{RemoteName: remote, Auth: auth})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil, newGitError("fetch", remote, err)
+	}
+	if db != nil {
+		_ = db.SetAppState(autoFetchLastRunKey+root, time.Now().Format(time.RFC3339))
+	}
+
+	tracking, err := compareTracking(repo, branch, remote, merge)
+	if err != nil {
+		return nil, err
+	}
+	tracking.Fetched = time.Now().Format(time.RFC3339)
+	if tracking.Behind > 0 && db != nil {
+		notified, _ := db.GetAppState(autoFetchNotifiedKey + root)
+		if notified != tracking.Commit {
+			_ = db.SetAppState(autoFetchNotifiedKey+root, tracking.Commit)
+			if a.ctx != nil {
+				runtime.EventsEmit(a.ctx, EventGitRemoteAhead, tracking)
+			}
+		}
+	}
+	return tracking, nil
+}
+
+// GitTrackingStatus compares the current branch with its upstream as of
+// the last fetch, without going to the network
+func (a *App) GitTrackingStatus(projectPath string) (*GitTracking, error) {
+	repo, _, err := openRepo(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	branch, remote, merge, err := trackedBranch(repo)
+	if err != nil {
+		return nil, err
+	}
+	tracking, err := compareTracking(repo, branch, remote, merge)
+	if err != nil {
+		return nil, err
+	}
+	if db != nil {
+		tracking.Fetched, _ = db.GetAppState(autoFetchLastRunKey + repoRoot(repo, projectPath))
+	}
+	return tracking, nil
+}
+
+// runAutoFetch fetches the open project on the schedule set by the
+// "git_autofetch_minutes" setting until ctx is done
+func (a *App) runAutoFetch(ctx context.Context) {
+	ticker := time.NewTicker(snapshotCheckEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if root := a.currentProjectRoot(); root != "" && a.autoFetchDue(root) {
+				_, _ = a.GitFetch(root)
+			}
+		}
+	}
+}
+
+func (a *App) autoFetchDue(root string) bool {
+	if db == nil {
+		return false
+	}
+	interval := defaultAutoFetchInterval
+	if v, ok := a.projectSetting("git_autofetch_minutes").(float64); ok {
+		if v <= 0 {
+			return false
+		}
+		interval = time.Duration(v) * time.Minute
+	}
+	repo, _, err := openRepo(root)
+	if err != nil {
+		return false
+	}
+	if remotes, err := repo.Remotes(); err != nil || len(remotes) == 0 {
+		return false
+	}
+	last, _ := db.GetAppState(autoFetchAttemptKey + repoRoot(repo, root))
+	t, err := time.Parse(time.RFC3339, last)
+	return err != nil || time.Since(t) >= interval
+}
+
+// trackedBranch returns the checked-out branch with the remote and remote
+// branch it tracks; branches without tracking config are taken to track
+// the same name on origin
+func trackedBranch(repo *git.Repository) (plumbing.ReferenceName, string, plumbing.ReferenceName, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return "", "", "", fmt.Errorf("nothing committed yet")
+	}
+	if !head.Name().IsBranch() {
+		return "", "", "", fmt.Errorf("check out a branch first; HEAD is detached")
+	}
+	remote, merge := git.DefaultRemoteName, head.Name()
+	if cfg, err := repo.Config(); err == nil {
+		if b, ok := cfg.Branches[head.Name().Short()]; ok && b.Remote != "" && b.Merge != "" {
+			remote, merge = b.Remote, b.Merge
+		}
+	}
+	return head.Name(), remote, merge, nil
+}
+
+// compareTracking counts the commits on either side of branch and its
+// upstream
+func compareTracking(repo *git.Repository, branch plumbing.ReferenceName, remote string, merge plumbing.ReferenceName) (*GitTracking, error) {
+	upstream := plumbing.NewRemoteReferenceName(remote, merge.Short())
+	tracking := &GitTracking{Branch: branch.Short(), Upstream: upstream.Short(), Remote: remote}
+	local, err := repo.Reference(branch, true)
+	if err != nil {
+		return nil, err
+	}
+	remoteRef, err := repo.Reference(upstream, true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		// Not pushed yet, or never fetched
+		return tracking, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	tracking.Commit = remoteRef.Hash().String()
+	if local.Hash() == remoteRef.Hash() {
+		return tracking, nil
+	}
+	localCommit, err := repo.CommitObject(local.Hash())
+	if err != nil {
+		return nil, err
+	}
+	remoteCommit, err := repo.CommitObject(remoteRef.Hash())
+	if err != nil {
+		return nil, err
+	}
+	bases, err := localCommit.MergeBase(remoteCommit)
+	if err != nil {
+		return nil, err
+	}
+	var stop []plumbing.Hash
+	for _, b := range bases {
+		stop = append(stop, b.Hash)
+	}
+	if tracking.Ahead, err = countCommits(localCommit, stop); err != nil {
+		return nil, err
+	}
+	if tracking.Behind, err = countCommits(remoteCommit, stop); err != nil {
+		return nil, err
+	}
+	return tracking, nil
+}
+
+// countCommits counts the commits from tip back to, not including, the
+// merge bases in stop
+func countCommits(tip *object.Commit, stop []plumbing.Hash) (int, error) {
+	iter := object.NewCommitPreorderIter(tip, nil, stop)
+	defer iter.Close()
+	n := 0
+	err := iter.ForEach(func(*object.Commit) error {
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// repoRoot is the working tree root of repo, which keys its auto-fetch
+// state
+func repoRoot(repo *git.Repository, fallback string) string {
+	if wt, err := repo.Worktree(); err == nil {
+		return wt.Filesystem.Root()
+	}
+	return fallback
+}
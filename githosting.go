@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Git hosting providers
+const (
+	ProviderGitHub    = "github"
+	ProviderGitLab    = "gitlab"
+	ProviderGitea     = "gitea"
+	ProviderBitbucket = "bitbucket"
+)
+
+// gitRemoteInfo is a remote URL broken down into what web and API URLs
+// are built from
+type gitRemoteInfo struct {
+	Provider string
+	// Host is the web host, without the SSH port
+	Host string
+	// Owner may hold several segments for GitLab subgroups
+	Owner string
+	Repo  string
+}
+
+// webURL is the repository's home page
+func (r *gitRemoteInfo) webURL() string {
+	return "https://" + r.Host + "/" + r.Owner + "/" + r.Repo
+}
+
+// fileURL links to a file at ref, and to line when above zero. Rendered
+// AsciiDoc has no line anchors, so line links show the source.
+func (r *gitRemoteInfo) fileURL(ref, rel string, line int) string {
+	segments := strings.Split(rel, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	path := strings.Join(segments, "/")
+	base := r.webURL()
+	switch r.Provider {
+	case ProviderGitLab:
+		if line > 0 {
+			return fmt.Sprintf("%s/-/blob/%s/%s?plain=1#L%d", base, ref, path, line)
+		}
+		return fmt.Sprintf("%s/-/blob/%s/%s", base, ref, path)
+	case ProviderGitea:
+		kind := "branch"
+		if plumbing.IsHash(ref) {
+			kind = "commit"
+		}
+		if line > 0 {
+			return fmt.Sprintf("%s/src/%s/%s/%s?display=source#L%d", base, kind, ref, path, line)
+		}
+		return fmt.Sprintf("%s/src/%s/%s/%s", base, kind, ref, path)
+	case ProviderBitbucket:
+		if line > 0 {
+			return fmt.Sprintf("%s/src/%s/%s#lines-%d", base, ref, path, line)
+		}
+		return fmt.Sprintf("%s/src/%s/%s", base, ref, path)
+	}
+	if line > 0 {
+		return fmt.Sprintf("%s/blob/%s/%s?plain=1#L%d", base, ref, path, line)
+	}
+	return fmt.Sprintf("%s/blob/%s/%s", base, ref, path)
+}
+
+// OpenRemoteFile opens a file on its git host, on the current branch, at
+// line when it is above zero. It returns the URL.
+func (a *App) OpenRemoteFile(path string, line int) (string, error) {
+	link, err := a.remoteFileURL(path, line)
+	if err != nil {
+		return "", err
+	}
+	if a.ctx != nil {
+		runtime.BrowserOpenURL(a.ctx, link)
+	}
+	return link, nil
+}
+
+// CopyRemoteLink puts the link OpenRemoteFile would open on the clipboard,
+// for sharing a doc location with reviewers
+func (a *App) CopyRemoteLink(path string, line int) (string, error) {
+	link, err := a.remoteFileURL(path, line)
+	if err != nil {
+		return "", err
+	}
+	if a.ctx != nil {
+		if err := runtime.ClipboardSetText(a.ctx, link); err != nil {
+			return "", err
+		}
+	}
+	return link, nil
+}
+
+// remoteFileURL builds the web URL of a file on the origin remote. The
+// branch (or commit, when detached) has to be pushed for it to resolve.
+func (a *App) remoteFileURL(path string, line int) (string, error) {
+	repo, wt, err := openRepo(filepath.Dir(path))
+	if err != nil {
+		return "", err
+	}
+	rel, err := repoRelPath(wt, path)
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("the repository has no commits yet")
+	}
+	ref := head.Hash().String()
+	if head.Name().IsBranch() {
+		ref = head.Name().Short()
+	}
+	info, err := a.remoteInfo(filepath.Dir(path), "")
+	if err != nil {
+		return "", err
+	}
+	return info.fileURL(ref, rel, line), nil
+}
+
+// remoteInfo reads a remote ("origin" when empty) of the repository holding
+// projectPath. The provider is guessed from the host name; self-hosted
+// instances on other hosts need the "git_provider" user setting, which a
+// cloned repository can't set since it decides where tokens are sent.
+func (a *App) remoteInfo(projectPath, remote string) (*gitRemoteInfo, error) {
+	repo, _, err := openRepo(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if remote == "" {
+		remote = git.DefaultRemoteName
+	}
+	r, err := repo.Remote(remote)
+	if err != nil {
+		return nil, &GitError{Op: "remote", Remote: remote, Code: GitErrNoRemote,
+			Message: fmt.Sprintf("remote %q is not configured", remote), err: err}
+	}
+	info, err := parseRemoteURL(r.Config().URLs[0])
+	if err != nil {
+		return nil, err
+	}
+	if p := a.userSettingString("git_provider", ""); p != "" {
+		info.Provider = p
+	}
+	if info.Provider == "" {
+		return nil, fmt.Errorf("can't tell which service hosts %s; set git_provider to github, gitlab, gitea or bitbucket", info.Host)
+	}
+	return info, nil
+}
+
+// parseRemoteURL accepts HTTPS, ssh:// and scp-style (git@host:owner/repo)
+// remote URLs
+func parseRemoteURL(rawURL string) (*gitRemoteInfo, error) {
+	endpoint, err := transport.NewEndpoint(rawURL)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("remote URL %q is not a hosted repository", rawURL)
+	}
+	path := strings.TrimSuffix(strings.Trim(endpoint.Path, "/"), ".git")
+	slash := strings.LastIndex(path, "/")
+	if slash <= 0 {
+		return nil, fmt.Errorf("remote URL %q has no owner and repository", rawURL)
+	}
+	info := &gitRemoteInfo{Host: strings.ToLower(endpoint.Host), Owner: path[:slash], Repo: path[slash+1:]}
+	switch {
+	case strings.Contains(info.Host, "github"):
+		info.Provider = ProviderGitHub
+	case strings.Contains(info.Host, "gitlab"):
+		info.Provider = ProviderGitLab
+	case strings.Contains(info.Host, "bitbucket"):
+		info.Provider = ProviderBitbucket
+	case strings.Contains(info.Host, "gitea"), info.Host == "codeberg.org":
+		info.Provider = ProviderGitea
+	}
+	return info, nil
+}
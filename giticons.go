@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// gitIconPackVersion is written to exported packs and checked on import
+const gitIconPackVersion = 1
+
+// defaultClientIcons is the built-in client → icon mapping, used for
+// clients without one of their own
+var defaultClientIcons = map[string]string{
+	"github-desktop": "default",
+	"tower":          "tower",
+}
+
+// GitIconPack is the JSON bundle used to share toolbar icons
+type GitIconPack struct {
+	Version int               `json:"version"`
+	Name    string            `json:"name,omitempty"`
+	Icons   map[string]string `json:"icons"`
+	// Clients maps git client names to icon ids in the pack
+	Clients map[string]string `json:"clients,omitempty"`
+}
+
+// GitIconPackImport reports what ImportGitIconPack changed
+type GitIconPackImport struct {
+	Added   []string `json:"added"`
+	Updated []string `json:"updated"`
+	Clients int      `json:"clients"`
+}
+
+// ExportGitIconPack writes every icon and the client mapping to a JSON file
+func (a *App) ExportGitIconPack(path string, name string) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	icons, err := db.GetGitIcons()
+	if err != nil {
+		return err
+	}
+	clients, err := db.GetGitClientIcons()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(GitIconPack{Version: gitIconPackVersion, Name: name, Icons: icons, Clients: clients}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, append(data, '\n'), 0644); err != nil {
+		return newFileError("export", path, err)
+	}
+	return nil
+}
+
+// ImportGitIconPack adds the icons of a pack, replacing icons with the
+// same id, and applies its client mapping. Nothing is imported if any
+// icon is invalid.
+func (a *App) ImportGitIconPack(path string) (*GitIconPackImport, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, newFileError("read", path, err)
+	}
+	var pack GitIconPack
+	if err := json.Unmarshal(data, &pack); err != nil {
+		return nil, fmt.Errorf("%s is not an icon pack: %w", filepath.Base(path), err)
+	}
+	if pack.Version > gitIconPackVersion {
+		return nil, fmt.Errorf("icon pack version %d is newer than this app supports", pack.Version)
+	}
+	for id, svg := range pack.Icons {
+		if strings.TrimSpace(id) == "" {
+			return nil, fmt.Errorf("icon pack has an icon without id")
+		}
+		if err := checkIconSVG(svg); err != nil {
+			return nil, fmt.Errorf("icon %q: %w", id, err)
+		}
+	}
+
+	existing, err := db.GetGitIcons()
+	if err != nil {
+		return nil, err
+	}
+	result := &GitIconPackImport{Added: []string{}, Updated: []string{}}
+	for id, svg := range pack.Icons {
+		if err := db.SetGitIcon(id, svg); err != nil {
+			return result, err
+		}
+		if _, ok := existing[id]; ok {
+			result.Updated = append(result.Updated, id)
+		} else {
+			result.Added = append(result.Added, id)
+		}
+	}
+	for client, iconID := range pack.Clients {
+		if _, ok := pack.Icons[iconID]; !ok {
+			if _, ok := existing[iconID]; !ok {
+				continue
+			}
+		}
+		if err := db.SetGitClientIcon(normalizeClientName(client), iconID); err != nil {
+			return result, err
+		}
+		result.Clients++
+	}
+	return result, nil
+}
+
+// GetGitClientIcons returns the client → icon mapping, built-in defaults
+// included
+func (a *App) GetGitClientIcons() (map[string]string, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	mapping, err := db.GetGitClientIcons()
+	if err != nil {
+		return nil, err
+	}
+	for client, iconID := range defaultClientIcons {
+		if _, ok := mapping[client]; !ok {
+			mapping[client] = iconID
+		}
+	}
+	return mapping, nil
+}
+
+// SetGitClientIcon chooses the toolbar icon for a git client; an empty
+// iconID restores the default
+func (a *App) SetGitClientIcon(client string, iconID string) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	client = normalizeClientName(client)
+	if client == "" {
+		return fmt.Errorf("client name must not be empty")
+	}
+	if iconID == "" {
+		return db.DeleteGitClientIcon(client)
+	}
+	icons, err := db.GetGitIcons()
+	if err != nil {
+		return err
+	}
+	if _, ok := icons[iconID]; !ok {
+		return fmt.Errorf("icon %q not found", iconID)
+	}
+	return db.SetGitClientIcon(client, iconID)
+}
+
+// GetGitClientIcon returns the icon id for the configured git client,
+// named after the "git_client_path" executable or the "git_client" tool
+// id, or GitHub Desktop
+func (a *App) GetGitClientIcon() (string, error) {
+	mapping, err := a.GetGitClientIcons()
+	if err != nil {
+		return "", err
+	}
+	client := "github-desktop"
+	if path := a.prefString("git_client_path"); path != "" {
+		client = normalizeClientName(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	} else if id := a.prefString("git_client"); id != "" {
+		client = normalizeClientName(id)
+	}
+	if iconID, ok := mapping[client]; ok {
+		return iconID, nil
+	}
+	return "default", nil
+}
+
+// normalizeClientName turns "GitHub Desktop" or "GitHubDesktop.exe"
+// style names into the keys used by the mapping
+func normalizeClientName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.Join(strings.Fields(name), "-")
+	switch name {
+	case "githubdesktop", "github":
+		return "github-desktop"
+	case "gittower":
+		return "tower"
+	case "smerge", "sublime_merge":
+		return "sublime-merge"
+	}
+	return name
+}
+
+// iconSVGElements are the SVG elements icons may use. Anything able to
+// run script, load a resource or switch back to HTML parsing (script,
+// foreignObject, title, use, a, image, ...) is left out.
+var iconSVGElements = map[string]bool{
+	"svg": true, "g": true, "defs": true, "symbol": true, "path": true, "circle": true,
+	"ellipse": true, "line": true, "polyline": true, "polygon": true, "rect": true,
+	"lineargradient": true, "radialgradient": true, "stop": true, "clippath": true, "mask": true,
+}
+
+// iconSVGAttributes are the attributes icons may use: geometry and
+// presentation, but no event handlers, links or styles
+var iconSVGAttributes = map[string]bool{
+	"xmlns": true, "version": true, "id": true, "class": true, "viewbox": true,
+	"preserveaspectratio": true, "width": true, "height": true, "x": true, "y": true,
+	"x1": true, "y1": true, "x2": true, "y2": true, "cx": true, "cy": true, "r": true,
+	"rx": true, "ry": true, "d": true, "points": true, "transform": true, "opacity": true,
+	"fill": true, "fill-opacity": true, "fill-rule": true, "clip-rule": true, "clip-path": true,
+	"mask": true, "stroke": true, "stroke-width": true, "stroke-opacity": true,
+	"stroke-linecap": true, "stroke-linejoin": true, "stroke-miterlimit": true,
+	"stroke-dasharray": true, "stroke-dashoffset": true, "offset": true, "stop-color": true,
+	"stop-opacity": true, "gradientunits": true, "gradienttransform": true, "fx": true, "fy": true,
+	"clippathunits": true, "maskunits": true, "maskcontentunits": true, "vector-effect": true,
+}
+
+// iconURLExpr matches the url() references attributes may hold; only
+// references to elements of the icon itself are allowed
+var iconURLExpr = regexp.MustCompile(`url\(\s*([^)]*)\)`)
+
+// checkIconSVG accepts small SVG documents built only from allowed
+// elements and attributes, since icons are inserted into the page as
+// markup
+func checkIconSVG(svg string) error {
+	if len(svg) > 10*1024 {
+		return fmt.Errorf("icon too large (max 10KB)")
+	}
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	depth, roots := 0, 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("icon is not valid SVG: %w", err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			name := strings.ToLower(t.Name.Local)
+			if depth == 0 {
+				if roots++; roots > 1 || name != "svg" {
+					return fmt.Errorf("icon must be an <svg> element")
+				}
+			}
+			if (t.Name.Space != "" && t.Name.Space != svgNamespace) || !iconSVGElements[name] {
+				return fmt.Errorf("icon must not contain <%s> elements", t.Name.Local)
+			}
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" {
+					continue
+				}
+				if attr.Name.Space != "" || !iconSVGAttributes[strings.ToLower(attr.Name.Local)] {
+					return fmt.Errorf("icon must not use the %s attribute", attr.Name.Local)
+				}
+				for _, m := range iconURLExpr.FindAllStringSubmatch(strings.ToLower(attr.Value), -1) {
+					if !strings.HasPrefix(strings.Trim(m[1], `'" `), "#") {
+						return fmt.Errorf("icon must not load %s", m[1])
+					}
+				}
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			if depth == 0 && len(bytes.TrimSpace(t)) > 0 {
+				return fmt.Errorf("icon must be an <svg> element")
+			}
+			if bytes.ContainsAny(t, "<>") {
+				return fmt.Errorf("icon text must not contain markup")
+			}
+		case xml.Comment:
+			if bytes.ContainsAny(t, "<>") {
+				return fmt.Errorf("icon comments must not contain markup")
+			}
+		case xml.ProcInst:
+			if bytes.ContainsAny(t.Inst, "<>") {
+				return fmt.Errorf("icon declarations must not contain markup")
+			}
+		case xml.Directive:
+			return fmt.Errorf("icon must not contain <!%s>", strings.Fields(string(t) + " ")[0])
+		}
+	}
+	if roots == 0 {
+		return fmt.Errorf("icon must be an <svg> element")
+	}
+	return nil
+}
+
+// svgNamespace is the namespace of SVG elements
+const svgNamespace = "http://www.w3.org/2000/svg"
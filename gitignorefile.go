@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GetGitignorePatterns returns the patterns in the project's .gitignore,
+// without comments and blank lines. A missing file has none.
+func (a *App) GetGitignorePatterns(projectPath string) ([]string, error) {
+	lines, _, err := readGitignore(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	patterns := []string{}
+	for _, line := range lines {
+		if _, ok := parseIgnoreLine(line, ""); ok {
+			patterns = append(patterns, strings.TrimSpace(line))
+		}
+	}
+	return patterns, nil
+}
+
+// AddGitignorePattern appends pattern to the project's .gitignore unless
+// it is already there. With a target path the pattern must match it, so
+// a typo can't silently ignore nothing.
+func (a *App) AddGitignorePattern(projectPath string, pattern string, target string) error {
+	pattern = strings.TrimSpace(pattern)
+	rule, ok := parseIgnoreLine(pattern, "")
+	if !ok {
+		return fmt.Errorf("%q is not a gitignore pattern", pattern)
+	}
+	if target != "" {
+		rel, isDir, err := gitignoreTarget(projectPath, target)
+		if err != nil {
+			return err
+		}
+		if (rule.dirOnly && !isDir) || !rule.re.MatchString(rel) {
+			return fmt.Errorf("%q does not match %s", pattern, rel)
+		}
+	}
+	lines, crlf, err := readGitignore(projectPath)
+	if err != nil {
+		return err
+	}
+	for _, line := range lines {
+		if strings.TrimSpace(line) == pattern {
+			return nil
+		}
+	}
+	return a.writeGitignore(projectPath, append(lines, pattern), crlf)
+}
+
+// ToggleGitignorePath ignores a file or folder of the project, or stops
+// ignoring it when the .gitignore has the exact pattern this adds
+// ("/docs/drafts/" for a folder). It returns whether the path is now
+// listed.
+func (a *App) ToggleGitignorePath(projectPath string, target string) (bool, error) {
+	rel, isDir, err := gitignoreTarget(projectPath, target)
+	if err != nil {
+		return false, err
+	}
+	pattern := "/" + rel
+	if isDir {
+		pattern += "/"
+	}
+	lines, crlf, err := readGitignore(projectPath)
+	if err != nil {
+		return false, err
+	}
+	kept := lines[:0:0]
+	for _, line := range lines {
+		if strings.TrimSpace(line) != pattern {
+			kept = append(kept, line)
+		}
+	}
+	if len(kept) < len(lines) {
+		return false, a.writeGitignore(projectPath, kept, crlf)
+	}
+	return true, a.writeGitignore(projectPath, append(lines, pattern), crlf)
+}
+
+// gitignoreTarget returns target relative to the project, slash-separated,
+// and whether it is a folder
+func gitignoreTarget(projectPath, target string) (string, bool, error) {
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(projectPath, target)
+	}
+	rel, err := filepath.Rel(projectPath, target)
+	if err != nil || rel == "." || !isWithin(target, projectPath) {
+		return "", false, fmt.Errorf("%s is not inside the project", target)
+	}
+	info, err := os.Stat(longPath(target))
+	if err != nil {
+		return "", false, newFileError("stat", target, err)
+	}
+	return filepath.ToSlash(rel), info.IsDir(), nil
+}
+
+// readGitignore returns the lines of the project's .gitignore and whether
+// it uses CRLF line breaks
+func readGitignore(projectPath string) ([]string, bool, error) {
+	path := filepath.Join(projectPath, ".gitignore")
+	data, err := os.ReadFile(longPath(path))
+	if os.IsNotExist(err) {
+		return []string{}, false, nil
+	}
+	if err != nil {
+		return nil, false, newFileError("read", path, err)
+	}
+	crlf := detectLineEnding(data) == LineEndingCRLF
+	text := strings.TrimSuffix(string(normalizeLineEndings(data, LineEndingLF)), "\n")
+	if text == "" {
+		return []string{}, crlf, nil
+	}
+	return strings.Split(text, "\n"), crlf, nil
+}
+
+func (a *App) writeGitignore(projectPath string, lines []string, crlf bool) error {
+	path := filepath.Join(projectPath, ".gitignore")
+	data := []byte(strings.Join(lines, "\n") + "\n")
+	if crlf {
+		data = normalizeLineEndings(data, LineEndingCRLF)
+	}
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return newFileError("write", path, err)
+	}
+	a.emitTreeChanged()
+	return nil
+}
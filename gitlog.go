@@ -0,0 +1,215 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/utils/diff"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// GitLogEntry is a commit in a file's history
+type GitLogEntry struct {
+	Hash    string `json:"hash"`
+	Author  string `json:"author"`
+	Email   string `json:"email"`
+	Date    string `json:"date"`
+	Subject string `json:"subject"`
+	// Tags mark the commit, e.g. a docs release
+	Tags []string `json:"tags,omitempty"`
+}
+
+// GitFileLog lists the commits that changed a file, newest first. Offset
+// and limit page through long histories; limit 0 returns everything.
+// Renames are not followed.
+func (a *App) GitFileLog(path string, limit int, offset int) ([]GitLogEntry, error) {
+	repo, wt, err := openRepo(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	if wt == nil {
+		return nil, fmt.Errorf("repository has no working tree")
+	}
+	rel, err := repoRelPath(wt, path)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []GitLogEntry{}
+	head, err := repo.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	commits, err := repo.Log(&git.LogOptions{From: head.Hash(), FileName: &rel})
+	if err != nil {
+		return nil, err
+	}
+	defer commits.Close()
+
+	tags := tagsByCommit(repo)
+	skipped := 0
+	err = commits.ForEach(func(c *object.Commit) error {
+		if skipped < offset {
+			skipped++
+			return nil
+		}
+		subject, _, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+		entries = append(entries, GitLogEntry{
+			Hash:    c.Hash.String(),
+			Author:  c.Author.Name,
+			Email:   c.Author.Email,
+			Date:    c.Author.When.Format(time.RFC3339),
+			Subject: strings.TrimSpace(subject),
+			Tags:    tags[c.Hash],
+		})
+		if limit > 0 && len(entries) >= limit {
+			return storer.ErrStop
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GitShowFileAtCommit returns a file's content as of a commit, for
+// opening an older version read-only
+func (a *App) GitShowFileAtCommit(path string, hash string) (string, error) {
+	repo, wt, err := openRepo(filepath.Dir(path))
+	if err != nil {
+		return "", err
+	}
+	if wt == nil {
+		return "", fmt.Errorf("repository has no working tree")
+	}
+	rel, err := repoRelPath(wt, path)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := repo.ResolveRevision(plumbing.Revision(hash))
+	if err != nil {
+		return "", fmt.Errorf("unknown commit %s", hash)
+	}
+	commit, err := repo.CommitObject(*resolved)
+	if err != nil {
+		return "", err
+	}
+	file, err := commit.File(rel)
+	if errors.Is(err, object.ErrFileNotFound) {
+		return "", fmt.Errorf("%s did not exist in commit %s", filepath.Base(path), hash)
+	}
+	if err != nil {
+		return "", err
+	}
+	if binary, _ := file.IsBinary(); binary {
+		return "", fmt.Errorf("%s is a binary file", filepath.Base(path))
+	}
+	return file.Contents()
+}
+
+// GitBlameLine says who last touched a line of the working file.
+// Uncommitted lines have no commit.
+type GitBlameLine struct {
+	Line        int    `json:"line"`
+	Hash        string `json:"hash,omitempty"`
+	Author      string `json:"author,omitempty"`
+	Email       string `json:"email,omitempty"`
+	Date        string `json:"date,omitempty"`
+	Subject     string `json:"subject,omitempty"`
+	Uncommitted bool   `json:"uncommitted,omitempty"`
+}
+
+// GitBlame attributes each line of the file as it is on disk to the
+// commit that last changed it. Lines edited since HEAD are marked
+// uncommitted, so the gutter stays aligned while the file is being worked
+// on.
+func (a *App) GitBlame(path string) ([]GitBlameLine, error) {
+	repo, wt, err := openRepo(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	if wt == nil {
+		return nil, fmt.Errorf("repository has no working tree")
+	}
+	rel, err := repoRelPath(wt, path)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(longPath(path))
+	if err != nil {
+		return nil, newFileError("read", path, err)
+	}
+
+	var blamed []*git.Line
+	var committed string
+	if head, err := repo.Head(); err == nil {
+		commit, err := repo.CommitObject(head.Hash())
+		if err != nil {
+			return nil, err
+		}
+		if file, err := commit.File(rel); err == nil {
+			if binary, _ := file.IsBinary(); binary {
+				return nil, fmt.Errorf("%s is a binary file", filepath.Base(path))
+			}
+			result, err := git.Blame(commit, rel)
+			if err != nil {
+				return nil, err
+			}
+			blamed = result.Lines
+			committed, _ = file.Contents()
+		}
+	} else if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, err
+	}
+
+	subjects := map[plumbing.Hash]string{}
+	lines := []GitBlameLine{}
+	headLine := 0
+	for _, d := range diff.Do(committed, string(content)) {
+		for _, text := range strings.SplitAfter(d.Text, "\n") {
+			if text == "" {
+				continue
+			}
+			switch d.Type {
+			case diffmatchpatch.DiffDelete:
+				headLine++
+			case diffmatchpatch.DiffInsert:
+				lines = append(lines, GitBlameLine{Line: len(lines) + 1, Uncommitted: true})
+			case diffmatchpatch.DiffEqual:
+				entry := GitBlameLine{Line: len(lines) + 1, Uncommitted: true}
+				if headLine < len(blamed) {
+					l := blamed[headLine]
+					if _, ok := subjects[l.Hash]; !ok {
+						if c, err := repo.CommitObject(l.Hash); err == nil {
+							subject, _, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+							subjects[l.Hash] = strings.TrimSpace(subject)
+						}
+					}
+					entry = GitBlameLine{
+						Line:    entry.Line,
+						Hash:    l.Hash.String(),
+						Author:  l.AuthorName,
+						Email:   l.Author,
+						Date:    l.Date.Format(time.RFC3339),
+						Subject: subjects[l.Hash],
+					}
+				}
+				headLine++
+				lines = append(lines, entry)
+			}
+		}
+	}
+	return lines, nil
+}
@@ -0,0 +1,408 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// EventGitProgress is emitted with a GitProgress while cloning, pushing or
+// pulling
+const EventGitProgress = "git:progress"
+
+// defaultGitTokenSecret prefixes the keychain secrets holding the token of
+// each host; see gitTokenSecret
+const defaultGitTokenSecret = "git-token"
+
+// Git error codes the frontend can act on
+const (
+	GitErrAuth           = "authFailed"
+	GitErrNonFastForward = "nonFastForward"
+	GitErrDirty          = "dirty"
+	GitErrNoRemote       = "noRemote"
+	GitErrNetwork        = "network"
+	GitErrOther          = "other"
+)
+
+// GitError is a clone, push or pull failure with a machine-readable code.
+// Like FileError it reaches the frontend as an object.
+type GitError struct {
+	Op      string `json:"op"`
+	Remote  string `json:"remote"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+	err     error
+}
+
+func (e *GitError) Error() string { return e.Message }
+
+func (e *GitError) Unwrap() error { return e.err }
+
+// GitProgress is the payload of EventGitProgress: a line of the server's
+// progress output, such as "Receiving objects:  50% (10/20)"
+type GitProgress struct {
+	Op      string `json:"op"`
+	Remote  string `json:"remote"`
+	Message string `json:"message"`
+}
+
+// GitSyncResult is returned by GitPush and GitPull
+type GitSyncResult struct {
+	Branch   string `json:"branch"`
+	UpToDate bool   `json:"upToDate"`
+	Head     string `json:"head"`
+}
+
+// GitPush pushes the current branch to remote ("origin" when empty).
+// Credentials are picked by gitAuth.
+func (a *App) GitPush(projectPath string, remote string) (*GitSyncResult, error) {
+	repo, _, err := openRepo(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	remote, branch, auth, err := a.gitRemoteSetup(repo, "push", remote)
+	if err != nil {
+		return nil, err
+	}
+	head, _ := repo.Head()
+
+	refSpec := config.RefSpec(fmt.Sprintf("%s:%s", branch, branch))
+	err = repo.Push(&git.PushOptions{
+		RemoteName: remote,
+		RefSpecs:   []config.RefSpec{refSpec},
+		Auth:       auth,
+		Progress:   a.gitProgressWriter("push", remote),
+	})
+	result := &GitSyncResult{Branch: branch.Short(), Head: head.Hash().String()}
+	if errors.Is(err, git.NoErrAlreadyUpToDate) {
+		result.UpToDate = true
+		return result, nil
+	}
+	if err != nil {
+		return nil, newGitError("push", remote, err)
+	}
+	return result, nil
+}
+
+// GitPull fetches remote ("origin" when empty) and fast-forwards the
+// current branch. Diverged branches and uncommitted changes are reported
+// as GitErrors rather than merged over.
+func (a *App) GitPull(projectPath string, remote string) (*GitSyncResult, error) {
+	repo, wt, err := openRepo(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if wt == nil {
+		return nil, fmt.Errorf("repository has no working tree")
+	}
+	remote, branch, auth, err := a.gitRemoteSetup(repo, "pull", remote)
+	if err != nil {
+		return nil, err
+	}
+
+	err = wt.Pull(&git.PullOptions{
+		RemoteName:    remote,
+		ReferenceName: branch,
+		SingleBranch:  true,
+		Auth:          auth,
+		Progress:      a.gitProgressWriter("pull", remote),
+	})
+	result := &GitSyncResult{Branch: branch.Short()}
+	if errors.Is(err, git.NoErrAlreadyUpToDate) {
+		result.UpToDate = true
+	} else if err != nil {
+		return nil, newGitError("pull", remote, err)
+	} else {
+		a.emitTreeChanged()
+	}
+	if head, err := repo.Head(); err == nil {
+		result.Head = head.Hash().String()
+	}
+	return result, nil
+}
+
+// gitRemoteSetup resolves the remote, the checked-out branch and the
+// credentials for the remote's URL
+func (a *App) gitRemoteSetup(repo *git.Repository, op, remote string) (string, plumbing.ReferenceName, transport.AuthMethod, error) {
+	if remote == "" {
+		remote = git.DefaultRemoteName
+	}
+	r, err := repo.Remote(remote)
+	if err != nil {
+		return "", "", nil, &GitError{Op: op, Remote: remote, Code: GitErrNoRemote,
+			Message: fmt.Sprintf("remote %q is not configured", remote), err: err}
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", "", nil, fmt.Errorf("nothing committed yet")
+	}
+	if !head.Name().IsBranch() {
+		return "", "", nil, fmt.Errorf("check out a branch first; HEAD is detached")
+	}
+	auth, err := a.gitAuth(r.Config().URLs[0])
+	if err != nil {
+		return "", "", nil, &GitError{Op: op, Remote: remote, Code: GitErrAuth, Message: err.Error(), err: err}
+	}
+	return remote, head.Name(), auth, nil
+}
+
+// gitAuth picks credentials for a remote URL: the configured key file or
+// else the SSH agent for SSH remotes, and for HTTPS ones the token stored
+// for the host. HTTPS remotes without a token for their host get none
+// (public remotes), so a token is never sent to another host. Remote URLs
+// can come from a cloned repository, so nothing here is read from the
+// project config.
+func (a *App) gitAuth(url string) (transport.AuthMethod, error) {
+	endpoint, err := transport.NewEndpoint(url)
+	if err != nil {
+		return nil, err
+	}
+	switch endpoint.Protocol {
+	case "ssh":
+		user := endpoint.User
+		if user == "" {
+			user = "git"
+		}
+		if key := a.userSettingString(gitSSHKeySetting, ""); key != "" {
+			passphrase, _ := getSecret(gitSSHPassphraseSecret)
+			auth, err := gitssh.NewPublicKeysFromFile(user, key, passphrase)
+			if err != nil {
+				return nil, fmt.Errorf("can't use SSH key %s: %w", key, err)
+			}
+			return auth, nil
+		}
+		auth, err := gitssh.NewSSHAgentAuth(user)
+		if err != nil {
+			return nil, fmt.Errorf("no SSH agent available; start one and add your key with ssh-add: %w", err)
+		}
+		return auth, nil
+	case "http", "https":
+		token, err := getSecret(gitTokenSecret(endpoint.Host))
+		if err != nil {
+			return nil, nil
+		}
+		user := endpoint.User
+		if user == "" && db != nil {
+			user, _ = db.GetAppState(gitUsernameKey(endpoint.Host))
+		}
+		if user == "" {
+			user = a.userSettingString("git_username", "git")
+		}
+		return &githttp.BasicAuth{Username: user, Password: token}, nil
+	}
+	return nil, nil
+}
+
+// newGitError classifies a clone, push or pull failure
+func newGitError(op, remote string, err error) *GitError {
+	e := &GitError{Op: op, Remote: remote, Code: GitErrOther, Message: err.Error(), err: err}
+	msg := err.Error()
+	var netErr net.Error
+	switch {
+	case errors.Is(err, transport.ErrAuthenticationRequired), errors.Is(err, transport.ErrAuthorizationFailed),
+		strings.Contains(msg, "unable to authenticate"), strings.Contains(msg, "permission denied"):
+		e.Code = GitErrAuth
+		e.Message = fmt.Sprintf("%s was refused by %s: authentication failed", op, remote)
+		e.Hint = "Store an access token for this host with SetGitToken, or set up your SSH key"
+	case errors.Is(err, git.ErrNonFastForwardUpdate), errors.Is(err, git.ErrForceNeeded),
+		strings.Contains(msg, "non-fast-forward"), strings.Contains(msg, "fetch first"):
+		e.Code = GitErrNonFastForward
+		if op == "push" {
+			e.Message = fmt.Sprintf("%s has commits you don't have yet", remote)
+			e.Hint = "Pull first, then push again"
+		} else {
+			e.Message = fmt.Sprintf("your branch and %s have diverged", remote)
+			e.Hint = "Merge or rebase in a terminal, then pull again"
+		}
+	case errors.Is(err, git.ErrUnstagedChanges), errors.Is(err, git.ErrWorktreeNotClean):
+		e.Code = GitErrDirty
+		e.Message = "pull would overwrite uncommitted changes"
+		e.Hint = "Commit or discard your changes, then pull again"
+	case errors.As(err, &netErr), errors.Is(err, transport.ErrRepositoryNotFound), os.IsTimeout(err):
+		e.Code = GitErrNetwork
+		e.Hint = "Check the remote URL and your network connection"
+	}
+	return e
+}
+
+// gitProgressWriter turns sideband progress output into events, one per
+// line or carriage-return update
+func (a *App) gitProgressWriter(op, remote string) *gitProgressEmitter {
+	return &gitProgressEmitter{app: a, op: op, remote: remote}
+}
+
+type gitProgressEmitter struct {
+	app     *App
+	op      string
+	remote  string
+	pending []byte
+}
+
+func (w *gitProgressEmitter) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	for {
+		i := strings.IndexAny(string(w.pending), "\r\n")
+		if i < 0 {
+			break
+		}
+		line := strings.TrimSpace(string(w.pending[:i]))
+		w.pending = w.pending[i+1:]
+		if line != "" && w.app.ctx != nil {
+			runtime.EventsEmit(w.app.ctx, EventGitProgress, GitProgress{Op: w.op, Remote: w.remote, Message: line})
+		}
+	}
+	return len(p), nil
+}
+
+// GitClone clones url into destDir, which must not exist or be empty,
+// then registers it as a project and makes it the open project. Progress
+// and credentials work as for GitPull. A failed clone leaves nothing
+// behind.
+func (a *App) GitClone(url string, destDir string) (string, error) {
+	if db == nil {
+		return "", fmt.Errorf("database not initialized")
+	}
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return "", fmt.Errorf("repository URL must not be empty")
+	}
+	if entries, err := os.ReadDir(destDir); err == nil && len(entries) > 0 {
+		return "", fmt.Errorf("%s is not empty", destDir)
+	}
+	auth, err := a.gitAuth(url)
+	if err != nil {
+		return "", &GitError{Op: "clone", Remote: git.DefaultRemoteName, Code: GitErrAuth, Message: err.Error(), err: err}
+	}
+
+	_, statErr := os.Stat(destDir)
+	_, err = git.PlainClone(destDir, false, &git.CloneOptions{
+		URL:      url,
+		Auth:     auth,
+		Progress: a.gitProgressWriter("clone", git.DefaultRemoteName),
+	})
+	if err != nil {
+		if os.IsNotExist(statErr) {
+			os.RemoveAll(destDir)
+		} else if entries, _ := os.ReadDir(destDir); len(entries) > 0 {
+			for _, e := range entries {
+				os.RemoveAll(filepath.Join(destDir, e.Name()))
+			}
+		}
+		return "", newGitError("clone", url, err)
+	}
+
+	if err := db.AddProject(destDir); err != nil {
+		return "", err
+	}
+	if err := db.SetPreference("projectRoot", destDir); err != nil {
+		return "", err
+	}
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, EventProjectOpened, destDir)
+	}
+	return destDir, nil
+}
+
+// GitRemote is a configured remote
+type GitRemote struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Provider and WebURL are set for remotes on a known git host
+	Provider string `json:"provider,omitempty"`
+	WebURL   string `json:"webUrl,omitempty"`
+}
+
+// GitListRemotes lists the repository's remotes by name
+func (a *App) GitListRemotes(projectPath string) ([]GitRemote, error) {
+	repo, _, err := openRepo(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	remotes, err := repo.Remotes()
+	if err != nil {
+		return nil, err
+	}
+	list := []GitRemote{}
+	for _, r := range remotes {
+		cfg := r.Config()
+		remote := GitRemote{Name: cfg.Name}
+		if len(cfg.URLs) > 0 {
+			remote.URL = cfg.URLs[0]
+			if info, err := parseRemoteURL(remote.URL); err == nil && info.Provider != "" {
+				remote.Provider, remote.WebURL = info.Provider, info.webURL()
+			}
+		}
+		list = append(list, remote)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// GitAddRemote adds a remote, e.g. origin for a project made with GitInit
+func (a *App) GitAddRemote(projectPath string, name string, url string) error {
+	repo, _, err := openRepo(projectPath)
+	if err != nil {
+		return err
+	}
+	name, url = strings.TrimSpace(name), strings.TrimSpace(url)
+	if err := validateRemoteURL(url); err != nil {
+		return err
+	}
+	cfg := &config.RemoteConfig{Name: name, URLs: []string{url}}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid remote name %q", name)
+	}
+	_, err = repo.CreateRemote(cfg)
+	if errors.Is(err, git.ErrRemoteExists) {
+		return fmt.Errorf("remote %s already exists; change its URL instead", name)
+	}
+	return err
+}
+
+// GitSetRemoteURL points an existing remote at a new URL
+func (a *App) GitSetRemoteURL(projectPath string, name string, url string) error {
+	repo, _, err := openRepo(projectPath)
+	if err != nil {
+		return err
+	}
+	url = strings.TrimSpace(url)
+	if err := validateRemoteURL(url); err != nil {
+		return err
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return err
+	}
+	remote, ok := cfg.Remotes[name]
+	if !ok {
+		return &GitError{Op: "remote", Remote: name, Code: GitErrNoRemote,
+			Message: fmt.Sprintf("remote %q is not configured", name)}
+	}
+	remote.URLs = []string{url}
+	return repo.Storer.SetConfig(cfg)
+}
+
+// validateRemoteURL accepts anything git can fetch from: HTTPS, SSH,
+// scp-style and local paths
+func validateRemoteURL(url string) error {
+	if url == "" {
+		return fmt.Errorf("remote URL must not be empty")
+	}
+	if _, err := transport.NewEndpoint(url); err != nil {
+		return fmt.Errorf("invalid remote URL %q", url)
+	}
+	return nil
+}
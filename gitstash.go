@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// GitErrConflict is the GitError code for a stash that applied with
+// conflicts
+const GitErrConflict = "conflict"
+
+// GitStashEntry is a stash in GitStashList
+type GitStashEntry struct {
+	// Index is n in stash@{n}; 0 is the most recent
+	Index   int    `json:"index"`
+	Hash    string `json:"hash"`
+	Date    string `json:"date"`
+	Message string `json:"message"`
+}
+
+// GitStashSave parks all uncommitted changes, untracked files included,
+// and leaves a clean working tree. go-git has no stash support, so this
+// and the other stash bindings run the git command line tool.
+func (a *App) GitStashSave(projectPath string, message string) error {
+	args := []string{"stash", "push", "--include-untracked"}
+	if strings.TrimSpace(message) != "" {
+		args = append(args, "-m", message)
+	}
+	out, err := runGit(projectPath, args...)
+	if err != nil {
+		return err
+	}
+	if strings.Contains(out, "No local changes to save") {
+		return fmt.Errorf("nothing to stash")
+	}
+	a.emitTreeChanged()
+	return nil
+}
+
+// GitStashList lists stashes, most recent first
+func (a *App) GitStashList(projectPath string) ([]GitStashEntry, error) {
+	out, err := runGit(projectPath, "stash", "list", "--format=%gd%x00%H%x00%cI%x00%gs")
+	if err != nil {
+		return nil, err
+	}
+	entries := []GitStashEntry{}
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 4 {
+			continue
+		}
+		ref := strings.TrimSuffix(strings.TrimPrefix(fields[0], "stash@{"), "}")
+		index, err := strconv.Atoi(ref)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, GitStashEntry{Index: index, Hash: fields[1], Date: fields[2], Message: fields[3]})
+	}
+	return entries, nil
+}
+
+// GitStashPop applies stash@{index} and drops it. When the changes
+// conflict with the working tree, the files are left with conflict
+// markers, the stash is kept and a GitError with code "conflict" is
+// returned.
+func (a *App) GitStashPop(projectPath string, index int) error {
+	ref := fmt.Sprintf("stash@{%d}", index)
+	_, err := runGit(projectPath, "stash", "pop", "--index", ref)
+	if err == nil {
+		a.emitTreeChanged()
+		return nil
+	}
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "CONFLICT") || strings.Contains(msg, "conflict"):
+		a.emitTreeChanged()
+		return &GitError{Op: "stash pop", Code: GitErrConflict, err: err,
+			Message: "the stash conflicts with your changes; conflicted files are marked",
+			Hint:    "Resolve the conflicts, then delete the stash"}
+	case strings.Contains(msg, "would be overwritten"), strings.Contains(msg, "already exists"):
+		return &GitError{Op: "stash pop", Code: GitErrDirty, err: err,
+			Message: "the stash would overwrite uncommitted changes",
+			Hint:    "Commit or stash your current changes first"}
+	}
+	return err
+}
+
+// runGit runs the git command line tool in dir and returns its output. A
+// failing command's error carries git's message.
+func runGit(dir string, args ...string) (string, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return "", fmt.Errorf("git is not installed or not on PATH")
+	}
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		msg := strings.TrimSpace(stderr.String() + "\n" + stdout.String())
+		if strings.Contains(msg, "not a git repository") {
+			return "", errNotRepo
+		}
+		return "", fmt.Errorf("git %s failed: %s", args[0], msg)
+	}
+	if err != nil {
+		return "", err
+	}
+	return stdout.String() + stderr.String(), nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// Git file states in GitStatus, as shown by tree and tab badges
+const (
+	GitModified   = "modified"
+	GitAdded      = "added"
+	GitDeleted    = "deleted"
+	GitRenamed    = "renamed"
+	GitUntracked  = "untracked"
+	GitConflicted = "conflicted"
+)
+
+// GitStatus is the working tree state of a project's repository
+type GitStatus struct {
+	IsRepo bool `json:"isRepo"`
+	// Root is the repository's working tree, which may be above the project
+	Root   string `json:"root,omitempty"`
+	Branch string `json:"branch,omitempty"`
+	// Head is the checked-out commit; Detached is set when it isn't a branch
+	Head     string `json:"head,omitempty"`
+	Detached bool   `json:"detached,omitempty"`
+	// Files maps absolute paths of changed files to their state. Clean
+	// files are left out.
+	Files map[string]GitFileStatus `json:"files"`
+}
+
+// GitFileStatus is the state of one changed file
+type GitFileStatus struct {
+	State string `json:"state"`
+	// Staged is set when the change is in the index
+	Staged bool `json:"staged"`
+}
+
+// GetGitStatus reads branch and per-file status of the repository holding
+// projectPath, without shelling out to git. A folder outside any
+// repository returns IsRepo false.
+func (a *App) GetGitStatus(projectPath string) (*GitStatus, error) {
+	status := &GitStatus{Files: map[string]GitFileStatus{}}
+	repo, wt, err := openRepo(projectPath)
+	if errors.Is(err, errNotRepo) {
+		return status, nil
+	}
+	if repo == nil {
+		return nil, err
+	}
+	status.IsRepo = true
+	if wt == nil {
+		// Bare repository: there is no working tree to report on
+		return status, nil
+	}
+	status.Root = wt.Filesystem.Root()
+
+	if head, err := repo.Head(); err == nil {
+		status.Head = head.Hash().String()
+		if head.Name().IsBranch() {
+			status.Branch = head.Name().Short()
+		} else {
+			status.Detached = true
+		}
+	} else if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		// No commits yet: HEAD names the branch to be born
+		if ref, err := repo.Storer.Reference(plumbing.HEAD); err == nil {
+			status.Branch = ref.Target().Short()
+		}
+	}
+
+	changes, err := wt.Status()
+	if err != nil {
+		return nil, err
+	}
+	for rel, fs := range changes {
+		state, staged := gitFileState(fs)
+		if state == "" {
+			continue
+		}
+		status.Files[filepath.Join(status.Root, filepath.FromSlash(rel))] = GitFileStatus{State: state, Staged: staged}
+	}
+	if conflicted, err := conflictedPaths(repo); err == nil {
+		for _, rel := range conflicted {
+			status.Files[filepath.Join(status.Root, filepath.FromSlash(rel))] = GitFileStatus{State: GitConflicted}
+		}
+	}
+	return status, nil
+}
+
+// gitFileState folds the index and worktree codes into one badge state
+func gitFileState(fs *git.FileStatus) (string, bool) {
+	staged := fs.Staging != git.Unmodified && fs.Staging != git.Untracked
+	switch {
+	case fs.Staging == git.UpdatedButUnmerged || fs.Worktree == git.UpdatedButUnmerged:
+		return GitConflicted, false
+	case fs.Worktree == git.Untracked:
+		return GitUntracked, false
+	case fs.Staging == git.Deleted || fs.Worktree == git.Deleted:
+		return GitDeleted, staged
+	case fs.Staging == git.Added:
+		return GitAdded, true
+	case fs.Staging == git.Renamed || fs.Staging == git.Copied:
+		return GitRenamed, true
+	case fs.Staging == git.Modified || fs.Worktree == git.Modified:
+		return GitModified, staged
+	}
+	return "", false
+}
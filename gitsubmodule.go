@@ -0,0 +1,168 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// GitSubmodule is a submodule of the project's repository
+type GitSubmodule struct {
+	Name string `json:"name"`
+	// Path is absolute, as in FileNode
+	Path string `json:"path"`
+	URL  string `json:"url"`
+	// Initialized is false until GitSubmoduleUpdate has cloned it
+	Initialized bool `json:"initialized"`
+	// Dirty is set when the submodule has uncommitted changes
+	Dirty bool `json:"dirty,omitempty"`
+	// Detached is set when no branch is checked out, which is how
+	// GitSubmoduleUpdate leaves it
+	Detached bool   `json:"detached,omitempty"`
+	Branch   string `json:"branch,omitempty"`
+	// Expected is the commit the parent repository records, Current the
+	// one checked out; they differ when the submodule is out of date
+	Expected string `json:"expected,omitempty"`
+	Current  string `json:"current,omitempty"`
+}
+
+// GitListSubmodules lists the submodules declared in .gitmodules with
+// their status
+func (a *App) GitListSubmodules(projectPath string) ([]GitSubmodule, error) {
+	_, wt, err := openRepo(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if wt == nil {
+		return nil, fmt.Errorf("repository has no working tree")
+	}
+	subs, err := wt.Submodules()
+	if err != nil {
+		return nil, err
+	}
+	root := wt.Filesystem.Root()
+	list := []GitSubmodule{}
+	for _, sub := range subs {
+		cfg := sub.Config()
+		info := GitSubmodule{
+			Name: cfg.Name,
+			Path: filepath.Join(root, filepath.FromSlash(cfg.Path)),
+			URL:  cfg.URL,
+		}
+		if status, err := sub.Status(); err == nil {
+			if !status.Expected.IsZero() {
+				info.Expected = status.Expected.String()
+			}
+			if !status.Current.IsZero() {
+				info.Current = status.Current.String()
+			}
+		}
+		repo, err := sub.Repository()
+		if err == nil {
+			info.Initialized = true
+			if head, err := repo.Head(); err == nil {
+				if head.Name().IsBranch() {
+					info.Branch = head.Name().Short()
+				} else {
+					info.Detached = true
+				}
+			}
+			if subWt, err := repo.Worktree(); err == nil {
+				if status, err := subWt.Status(); err == nil {
+					info.Dirty = !status.IsClean()
+				}
+			}
+		}
+		list = append(list, info)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Path < list[j].Path })
+	return list, nil
+}
+
+// GitSubmoduleUpdate initializes every submodule and checks out the commit
+// the parent repository records, as git submodule update --init
+// --recursive does
+func (a *App) GitSubmoduleUpdate(projectPath string) error {
+	repo, wt, err := openRepo(projectPath)
+	if err != nil {
+		return err
+	}
+	if wt == nil {
+		return fmt.Errorf("repository has no working tree")
+	}
+	subs, err := wt.Submodules()
+	if err != nil {
+		return err
+	}
+	base := wt.Filesystem.Root()
+	if origin, err := repo.Remote(git.DefaultRemoteName); err == nil {
+		base = origin.Config().URLs[0]
+	}
+	for _, sub := range subs {
+		cfg := sub.Config()
+		cfg.URL = resolveSubmoduleURL(base, cfg.URL)
+		url := cfg.URL
+		auth, err := a.gitAuth(url)
+		if err != nil {
+			return newGitError("submodule update", url, err)
+		}
+		err = sub.Update(&git.SubmoduleUpdateOptions{
+			Init:              true,
+			RecurseSubmodules: git.DefaultSubmoduleRecursionDepth,
+			Auth:              auth,
+		})
+		if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+			return newGitError("submodule update", url, err)
+		}
+	}
+	a.emitTreeChanged()
+	return nil
+}
+
+// resolveSubmoduleURL resolves a URL relative to the parent repository's
+// origin (or its directory), such as ../shared.git, which go-git leaves
+// as is
+func resolveSubmoduleURL(base, rel string) string {
+	if !strings.HasPrefix(rel, "./") && !strings.HasPrefix(rel, "../") {
+		return rel
+	}
+	base = strings.TrimSuffix(base, "/")
+	sep := "/"
+	for {
+		switch {
+		case strings.HasPrefix(rel, "./"):
+			rel = rel[2:]
+		case strings.HasPrefix(rel, "../"):
+			rel = rel[3:]
+			// scp-style URLs (git@host:org/repo) separate the path with a colon
+			if i := strings.LastIndexAny(base, "/:"); i >= 0 {
+				sep = base[i : i+1]
+				base = base[:i]
+			}
+		default:
+			return base + sep + rel
+		}
+	}
+}
+
+// submodulePaths returns the absolute paths of the submodules of the
+// repository holding root, for marking them in the file tree
+func submodulePaths(root string) map[string]bool {
+	_, wt, err := openRepo(root)
+	if err != nil || wt == nil || !exists(filepath.Join(wt.Filesystem.Root(), ".gitmodules")) {
+		return nil
+	}
+	subs, err := wt.Submodules()
+	if err != nil {
+		return nil
+	}
+	paths := make(map[string]bool, len(subs))
+	for _, sub := range subs {
+		paths[filepath.Join(wt.Filesystem.Root(), filepath.FromSlash(sub.Config().Path))] = true
+	}
+	return paths
+}
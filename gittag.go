@@ -0,0 +1,155 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// GitTag is a tag, lightweight or annotated
+type GitTag struct {
+	Name string `json:"name"`
+	// Commit is the commit the tag points at
+	Commit    string `json:"commit"`
+	Annotated bool   `json:"annotated,omitempty"`
+	Message   string `json:"message,omitempty"`
+	Tagger    string `json:"tagger,omitempty"`
+	// Date is when an annotated tag was made, or else the commit date
+	Date string `json:"date"`
+}
+
+// GitListTags lists the repository's tags, newest first
+func (a *App) GitListTags(projectPath string) ([]GitTag, error) {
+	repo, _, err := openRepo(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := repo.Tags()
+	if err != nil {
+		return nil, err
+	}
+	defer refs.Close()
+	tags := []GitTag{}
+	var dates []time.Time
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		tag, when, ok := readTag(repo, ref)
+		if ok {
+			tags = append(tags, tag)
+			dates = append(dates, when)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	order := make([]int, len(tags))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		x, y := order[i], order[j]
+		if !dates[x].Equal(dates[y]) {
+			return dates[x].After(dates[y])
+		}
+		return tags[x].Name < tags[y].Name
+	})
+	sorted := make([]GitTag, len(tags))
+	for i, k := range order {
+		sorted[i] = tags[k]
+	}
+	return sorted, nil
+}
+
+// GitCreateTag tags the current commit, e.g. to mark a docs release. With
+// a message the tag is annotated and signed by the user's git identity;
+// without one it is lightweight.
+func (a *App) GitCreateTag(projectPath string, name string, message string) (*GitTag, error) {
+	repo, _, err := openRepo(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if err := plumbing.NewTagReferenceName(name).Validate(); err != nil || name == "" {
+		return nil, fmt.Errorf("invalid tag name %q", name)
+	}
+	head, err := repo.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, fmt.Errorf("commit something before creating a tag")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var opts *git.CreateTagOptions
+	if strings.TrimSpace(message) != "" {
+		opts = &git.CreateTagOptions{Message: message}
+	}
+	ref, err := repo.CreateTag(name, head.Hash(), opts)
+	switch {
+	case errors.Is(err, git.ErrTagExists):
+		return nil, fmt.Errorf("tag %s already exists", name)
+	case errors.Is(err, git.ErrMissingTagger):
+		return nil, fmt.Errorf("set user.name and user.email in your git config to create annotated tags")
+	case err != nil:
+		return nil, err
+	}
+	tag, _, ok := readTag(repo, ref)
+	if !ok {
+		return nil, fmt.Errorf("tag %s was created but can't be read", name)
+	}
+	return &tag, nil
+}
+
+// readTag resolves a tag reference to the commit it marks
+func readTag(repo *git.Repository, ref *plumbing.Reference) (GitTag, time.Time, bool) {
+	tag := GitTag{Name: ref.Name().Short()}
+	hash := ref.Hash()
+	var when time.Time
+	if t, err := repo.TagObject(hash); err == nil {
+		tag.Annotated = true
+		tag.Message = strings.TrimSpace(t.Message)
+		tag.Tagger = t.Tagger.Name
+		when = t.Tagger.When
+		c, err := t.Commit()
+		if err != nil {
+			// Tags of trees and blobs mark no commit
+			return GitTag{}, when, false
+		}
+		hash = c.Hash
+	} else {
+		c, err := repo.CommitObject(hash)
+		if err != nil {
+			return GitTag{}, when, false
+		}
+		when = c.Committer.When
+	}
+	tag.Commit = hash.String()
+	tag.Date = when.Format(time.RFC3339)
+	return tag, when, true
+}
+
+// tagsByCommit maps commit hashes to the names of the tags marking them
+func tagsByCommit(repo *git.Repository) map[plumbing.Hash][]string {
+	byCommit := map[plumbing.Hash][]string{}
+	refs, err := repo.Tags()
+	if err != nil {
+		return byCommit
+	}
+	defer refs.Close()
+	_ = refs.ForEach(func(ref *plumbing.Reference) error {
+		if tag, _, ok := readTag(repo, ref); ok {
+			hash := plumbing.NewHash(tag.Commit)
+			byCommit[hash] = append(byCommit[hash], tag.Name)
+		}
+		return nil
+	})
+	for _, names := range byCommit {
+		sort.Strings(names)
+	}
+	return byCommit
+}
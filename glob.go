@@ -0,0 +1,124 @@
+package main
+
+import (
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// globToRegexp converts a slash-separated glob into a regexp. Besides the
+// usual * ? and [...] it understands ** to match across directories.
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				// "**/" also matches zero directories
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end
+		default:
+			// Quote the whole run of literal text, so multi-byte
+			// characters stay intact
+			end := strings.IndexAny(pattern[i:], "*?[")
+			if end < 0 {
+				end = len(pattern) - i
+			}
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+end]))
+			i += end - 1
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// globFiles returns the files under root whose root-relative path matches
+// pattern. Hidden directories are skipped.
+func globFiles(root, pattern string) ([]string, error) {
+	re, err := globToRegexp(filepath.ToSlash(pattern))
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []string
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		if re.MatchString(filepath.ToSlash(rel)) {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	return matches, err
+}
+
+// globProjectFiles is globFiles leaving out what the project's
+// .gitignore/.ndxcraftignore files ignore
+func globProjectFiles(root, pattern string) ([]string, error) {
+	re, err := globToRegexp(filepath.ToSlash(pattern))
+	if err != nil {
+		return nil, err
+	}
+
+	matchers := map[string]*ignoreMatcher{".": (&ignoreMatcher{}).withDir(root, "")}
+	var matches []string
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == "." {
+			return nil
+		}
+		slashRel := filepath.ToSlash(rel)
+		ignore := matchers[filepath.Dir(rel)]
+		if d.IsDir() {
+			if strings.HasPrefix(d.Name(), ".") || ignore.Match(slashRel, true) {
+				return filepath.SkipDir
+			}
+			matchers[rel] = ignore.withDir(path, slashRel)
+			return nil
+		}
+		if !ignore.Match(slashRel, false) && re.MatchString(slashRel) {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	return matches, err
+}
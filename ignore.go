@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ignoreFileNames are read in every directory of a project tree. Rules in
+// .ndxcraftignore come later and so win over .gitignore.
+var ignoreFileNames = []string{".gitignore", ".ndxcraftignore"}
+
+// ignoreRule is a single gitignore pattern
+type ignoreRule struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+	// base is the slash-separated directory of the ignore file, relative
+	// to the tree root ("" for the root itself)
+	base string
+}
+
+// ignoreMatcher applies gitignore rules collected from a directory and
+// its ancestors. It is immutable; withDir returns an extended copy.
+type ignoreMatcher struct {
+	rules []ignoreRule
+}
+
+// withDir returns a matcher that also applies the ignore files found in
+// dir, whose path relative to the tree root is relDir
+func (m *ignoreMatcher) withDir(dir, relDir string) *ignoreMatcher {
+	var added []ignoreRule
+	for _, name := range ignoreFileNames {
+		added = append(added, readIgnoreFile(filepath.Join(dir, name), relDir)...)
+	}
+	if len(added) == 0 {
+		return m
+	}
+	rules := make([]ignoreRule, 0, len(m.rules)+len(added))
+	rules = append(rules, m.rules...)
+	rules = append(rules, added...)
+	return &ignoreMatcher{rules: rules}
+}
+
+// Match reports whether relPath (slash-separated, relative to the tree
+// root) is ignored. As in git, the last matching rule wins.
+func (m *ignoreMatcher) Match(relPath string, isDir bool) bool {
+	ignored := false
+	for _, r := range m.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		p := relPath
+		if r.base != "" {
+			if !strings.HasPrefix(p, r.base+"/") {
+				continue
+			}
+			p = strings.TrimPrefix(p, r.base+"/")
+		}
+		if r.re.MatchString(p) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+func readIgnoreFile(file, base string) []ignoreRule {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if r, ok := parseIgnoreLine(scanner.Text(), filepath.ToSlash(base)); ok {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+func parseIgnoreLine(line, base string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	r := ignoreRule{base: strings.Trim(base, "/")}
+	if r.base == "." {
+		r.base = ""
+	}
+	if strings.HasPrefix(line, "!") {
+		r.negate = true
+		line = line[1:]
+	}
+	line = strings.TrimPrefix(line, `\`)
+	if strings.HasSuffix(line, "/") {
+		r.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+
+	// A slash anywhere but the end anchors the pattern to the ignore
+	// file's directory; otherwise it matches at any depth
+	if strings.Contains(line, "/") {
+		line = strings.TrimPrefix(line, "/")
+	} else {
+		line = "**/" + line
+	}
+
+	re, err := globToRegexp(path.Clean(line))
+	if err != nil {
+		return ignoreRule{}, false
+	}
+	r.re = re
+	return r, true
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// EventProjectOpened is emitted when the backend switches the open project
+const EventProjectOpened = "project:opened"
+
+// ImportProject extracts a zip (or copies a folder) into targetDir,
+// registers it as a project and makes it the open project. targetDir must
+// not exist or be empty; a failed import leaves it as it was. A zip
+// holding a single top-level folder is unwrapped, as produced by most
+// "download as zip" buttons.
+func (a *App) ImportProject(zipOrFolder string, targetDir string) (string, error) {
+	if db == nil {
+		return "", fmt.Errorf("database not initialized")
+	}
+	info, err := os.Stat(zipOrFolder)
+	if err != nil {
+		return "", err
+	}
+	entries, err := os.ReadDir(targetDir)
+	if err == nil && len(entries) > 0 {
+		return "", fmt.Errorf("%s is not empty", targetDir)
+	}
+	created := err != nil
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return "", err
+	}
+
+	if info.IsDir() {
+		progress := &CopyProgress{Src: zipOrFolder}
+		err = a.copyTree(zipOrFolder, targetDir, CopyOptions{Overwrite: OverwriteError}, progress, map[string]bool{})
+	} else {
+		err = extractZip(zipOrFolder, targetDir)
+	}
+	if err != nil {
+		removeImported(targetDir, created)
+		return "", err
+	}
+
+	if err := db.AddProject(targetDir); err != nil {
+		removeImported(targetDir, created)
+		return "", err
+	}
+	if err := db.SetPreference("projectRoot", targetDir); err != nil {
+		return "", err
+	}
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, EventProjectOpened, targetDir)
+	}
+	return targetDir, nil
+}
+
+// removeImported clears what a failed import left in dir, and dir itself
+// when the import created it
+func removeImported(dir string, created bool) {
+	if created {
+		os.RemoveAll(dir)
+		return
+	}
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		os.RemoveAll(filepath.Join(dir, e.Name()))
+	}
+}
+
+// extractZip unpacks src into dir, refusing entries that would land
+// outside it
+func extractZip(src, dir string) error {
+	zr, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	prefix := zipCommonPrefix(zr.File)
+	for _, f := range zr.File {
+		name := strings.TrimPrefix(f.Name, prefix)
+		if name == "" {
+			continue
+		}
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if !isWithin(target, dir) || target == filepath.Clean(dir) {
+			return fmt.Errorf("zip entry %q points outside the project", f.Name)
+		}
+
+		// Symlinks are skipped; an archive shouldn't reach outside
+		if f.Mode()&os.ModeSymlink != 0 {
+			continue
+		}
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := extractZipFile(f, target); err != nil {
+			return fmt.Errorf("extracting %s: %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
+func extractZipFile(f *zip.File, target string) error {
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	perm := f.Mode().Perm()
+	if perm == 0 {
+		perm = 0644
+	}
+	w, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, r); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// zipCommonPrefix returns "name/" when every entry sits under one folder
+func zipCommonPrefix(files []*zip.File) string {
+	prefix := ""
+	for _, f := range files {
+		top, _, nested := strings.Cut(f.Name, "/")
+		if !nested && !f.FileInfo().IsDir() {
+			return ""
+		}
+		if prefix == "" {
+			prefix = top + "/"
+		} else if prefix != top+"/" {
+			return ""
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// InclusiveLanguageConfig customizes the inclusive-language rule. Terms
+// are checked on top of the built-in list, unless DisableDefaults is set;
+// Allow drops built-in terms the organization has decided to keep.
+type InclusiveLanguageConfig struct {
+	DisableDefaults bool       `yaml:"disableDefaults" json:"disableDefaults"`
+	Terms           []TermRule `yaml:"terms" json:"terms"`
+	Allow           []string   `yaml:"allow" json:"allow"`
+}
+
+// TermRule is a term to avoid and what to say instead
+type TermRule struct {
+	Term         string   `yaml:"term" json:"term"`
+	Replacements []string `yaml:"replacements" json:"replacements"`
+	Reason       string   `yaml:"reason" json:"reason"`
+}
+
+// defaultInclusiveTerms is the built-in wordlist
+var defaultInclusiveTerms = []TermRule{
+	{Term: "whitelist", Replacements: []string{"allowlist"}},
+	{Term: "blacklist", Replacements: []string{"denylist", "blocklist"}},
+	{Term: "master", Replacements: []string{"primary", "main"}},
+	{Term: "slave", Replacements: []string{"replica", "secondary"}},
+	{Term: "sanity check", Replacements: []string{"quick check", "coherence check"}, Reason: "refers to mental health"},
+	{Term: "dummy", Replacements: []string{"placeholder", "sample"}},
+	{Term: "grandfathered", Replacements: []string{"legacy", "exempt"}},
+	{Term: "man-hours", Replacements: []string{"person-hours", "work hours"}},
+	{Term: "manpower", Replacements: []string{"workforce", "staff"}},
+	{Term: "crazy", Replacements: []string{"surprising", "unexpected"}, Reason: "refers to mental health"},
+	{Term: "cripple", Replacements: []string{"disable", "impair"}, Reason: "refers to disability"},
+}
+
+// inclusiveTerm is a TermRule ready for matching
+type inclusiveTerm struct {
+	TermRule
+	expr *regexp.Regexp
+}
+
+// inclusiveTerms compiles the wordlist of a project config, which may be
+// nil
+func inclusiveTerms(cfg *ProjectConfig) []inclusiveTerm {
+	var custom *InclusiveLanguageConfig
+	if cfg != nil {
+		custom = cfg.InclusiveLanguage
+	}
+	var rules []TermRule
+	if custom == nil || !custom.DisableDefaults {
+		rules = append(rules, defaultInclusiveTerms...)
+	}
+	allowed := map[string]bool{}
+	if custom != nil {
+		rules = append(rules, custom.Terms...)
+		for _, t := range custom.Allow {
+			allowed[strings.ToLower(strings.TrimSpace(t))] = true
+		}
+	}
+
+	var terms []inclusiveTerm
+	for _, r := range rules {
+		term := strings.TrimSpace(r.Term)
+		if term == "" || allowed[strings.ToLower(term)] {
+			continue
+		}
+		// Words of a multi-word term may be split across spaces or hyphens;
+		// common inflections are matched too
+		words := strings.Fields(term)
+		for i, w := range words {
+			words[i] = regexp.QuoteMeta(w)
+		}
+		expr, err := regexp.Compile(`(?i)\b(` + strings.Join(words, `[\s-]+`) + `)(?:s|es|ed|ing)?\b`)
+		if err != nil {
+			continue
+		}
+		terms = append(terms, inclusiveTerm{TermRule: r, expr: expr})
+	}
+	return terms
+}
+
+// checkInclusiveLanguage flags non-inclusive or deprecated terms from the
+// project's wordlist
+func checkInclusiveLanguage(doc *lintDocument) []Diagnostic {
+	var found []Diagnostic
+	forEachProseLine(doc.Content, func(n int, line string) {
+		if strings.HasPrefix(strings.TrimSpace(line), ":") {
+			return
+		}
+		for _, t := range doc.Terms {
+			for _, m := range t.expr.FindAllStringSubmatchIndex(line, -1) {
+				msg := fmt.Sprintf("Avoid %q", line[m[2]:m[3]])
+				if t.Reason != "" {
+					msg += " (" + t.Reason + ")"
+				}
+				if len(t.Replacements) > 0 {
+					msg += `; consider "` + strings.Join(t.Replacements, `" or "`) + `"`
+				}
+				found = append(found, Diagnostic{Line: n, Column: m[2] + 1, Message: msg})
+			}
+		}
+	})
+	return found
+}
+
+// CheckInclusiveLanguage runs just the inclusive-language rule over a
+// document, or every document below a directory, without touching the
+// problems panel
+func (a *App) CheckInclusiveLanguage(path string) ([]Diagnostic, error) {
+	info, err := os.Stat(longPath(path))
+	if err != nil {
+		return nil, newFileError("read", path, err)
+	}
+	docs := []string{path}
+	if info.IsDir() {
+		if docs, err = globFiles(path, "**/*.adoc"); err != nil {
+			return nil, err
+		}
+	}
+	cfg, _ := LoadProjectConfig(a.currentProjectRoot())
+	terms := inclusiveTerms(cfg)
+	severity := ruleSeverity(a.severityOverrides(), DiagnosticSourceLint, "inclusive-language", SeverityWarn)
+
+	found := []Diagnostic{}
+	for _, doc := range docs {
+		content, err := os.ReadFile(longPath(doc))
+		if err != nil {
+			return nil, newFileError("read", doc, err)
+		}
+		var diagnostics []Diagnostic
+		for _, d := range checkInclusiveLanguage(&lintDocument{Path: doc, Content: string(content), Terms: terms}) {
+			d.Path = doc
+			d.Severity = severity
+			d.Source = DiagnosticSourceLint
+			d.Rule = "inclusive-language"
+			diagnostics = append(diagnostics, d)
+		}
+		found = append(found, a.filterDiagnostics(doc, diagnostics, nil)...)
+	}
+	return found, nil
+}
+
+// fixInclusiveLanguage returns a fixer offering each replacement of a
+// flagged term, keeping its inflection and capitalization
+func fixInclusiveLanguage(terms []inclusiveTerm) quickFixer {
+	return func(content string, lineStart int, line string, d Diagnostic) []proposedFix {
+		col := d.Column - 1
+		if col < 0 || col > len(line) {
+			return nil
+		}
+		for _, t := range terms {
+			m := t.expr.FindStringSubmatchIndex(line[col:])
+			if m == nil || m[0] != 0 {
+				continue
+			}
+			matched := line[col+m[2] : col+m[3]]
+			var fixes []proposedFix
+			for _, r := range t.Replacements {
+				text := matchCase(matched, r)
+				fixes = append(fixes, proposedFix{
+					title: fmt.Sprintf("Replace with %q", text),
+					edits: []byteEdit{{start: lineStart + col + m[2], end: lineStart + col + m[3], text: text}},
+				})
+			}
+			return fixes
+		}
+		return nil
+	}
+}
+
+// matchCase capitalizes replacement like word
+func matchCase(word, replacement string) string {
+	switch {
+	case word == strings.ToUpper(word) && strings.ToUpper(word) != strings.ToLower(word):
+		return strings.ToUpper(replacement)
+	case replacement != "":
+		first, _ := utf8.DecodeRuneInString(word)
+		if unicode.IsUpper(first) {
+			r, size := utf8.DecodeRuneInString(replacement)
+			return string(unicode.ToUpper(r)) + replacement[size:]
+		}
+	}
+	return replacement
+}
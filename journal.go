@@ -0,0 +1,259 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Journal step kinds
+const (
+	JournalCreate  = "create"
+	JournalRename  = "rename"
+	JournalDelete  = "delete"
+	JournalReplace = "replace"
+)
+
+// Journal stacks
+const (
+	journalUndo = "undo"
+	journalRedo = "redo"
+)
+
+// journalLimit is how many operations can be undone
+const journalLimit = 50
+
+// JournalStep is one filesystem change of an operation
+type JournalStep struct {
+	Kind    string `json:"kind"`
+	Path    string `json:"path"`
+	NewPath string `json:"newPath,omitempty"`
+	// Backup holds the previous content of deleted or replaced paths
+	Backup string `json:"backup,omitempty"`
+}
+
+// JournalEntry is a user-level file operation, possibly touching many files
+type JournalEntry struct {
+	ID        string        `json:"id"`
+	Label     string        `json:"label"`
+	Steps     []JournalStep `json:"steps"`
+	CreatedAt time.Time     `json:"createdAt"`
+}
+
+// journalOp collects the steps of an operation while it runs. All methods
+// are no-ops on a nil op, which is what beginOperation returns when the
+// database is unavailable.
+type journalOp struct {
+	entry JournalEntry
+}
+
+// beginOperation starts recording a multi-step file operation
+func (a *App) beginOperation(label string) *journalOp {
+	if db == nil {
+		return nil
+	}
+	return &journalOp{entry: JournalEntry{ID: uuid.New().String(), Label: label}}
+}
+
+func (op *journalOp) created(path string) {
+	if op != nil {
+		op.entry.Steps = append(op.entry.Steps, JournalStep{Kind: JournalCreate, Path: path})
+	}
+}
+
+func (op *journalOp) renamed(oldPath, newPath string) {
+	if op != nil {
+		op.entry.Steps = append(op.entry.Steps, JournalStep{Kind: JournalRename, Path: oldPath, NewPath: newPath})
+	}
+}
+
+// deleting backs up path before it is removed
+func (op *journalOp) deleting(path string) error {
+	return op.backup(JournalDelete, path)
+}
+
+// replacing backs up path before its content is overwritten
+func (op *journalOp) replacing(path string) error {
+	return op.backup(JournalReplace, path)
+}
+
+func (op *journalOp) backup(kind, path string) error {
+	if op == nil {
+		return nil
+	}
+	backup := filepath.Join(journalDir(), op.entry.ID, strconv.Itoa(len(op.entry.Steps)))
+	if err := os.MkdirAll(filepath.Dir(backup), 0755); err != nil {
+		return err
+	}
+	if err := snapshotPath(path, backup); err != nil {
+		return fmt.Errorf("backing up %s: %w", path, err)
+	}
+	op.entry.Steps = append(op.entry.Steps, JournalStep{Kind: kind, Path: path, Backup: backup})
+	return nil
+}
+
+// discard drops the backups of an operation that failed before committing
+func (op *journalOp) discard() {
+	if op != nil {
+		removeJournalBackups([]string{op.entry.ID})
+	}
+}
+
+// commitOperation records a finished operation as the next thing to undo.
+// A new operation invalidates whatever could be redone.
+func (a *App) commitOperation(op *journalOp) {
+	if op == nil || len(op.entry.Steps) == 0 {
+		return
+	}
+	saveJournalEntry(journalUndo, op)
+	if dropped, err := db.ClearJournal(journalRedo); err == nil {
+		removeJournalBackups(dropped)
+	}
+	if dropped, err := db.TrimJournal(journalUndo, journalLimit); err == nil {
+		removeJournalBackups(dropped)
+	}
+}
+
+func saveJournalEntry(stack string, op *journalOp) {
+	op.entry.CreatedAt = time.Now()
+	if err := db.AddJournalEntry(stack, op.entry); err != nil {
+		removeJournalBackups([]string{op.entry.ID})
+	}
+}
+
+// GetOperationJournal lists the operations that can be undone, newest first
+func (a *App) GetOperationJournal() ([]JournalEntry, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	return db.GetJournalEntries(journalUndo)
+}
+
+// UndoLastOperation reverses the most recent file operation, including
+// multi-file ones like project-wide replacements, and returns it
+func (a *App) UndoLastOperation() (*JournalEntry, error) {
+	return a.reverseOperation(journalUndo, journalRedo)
+}
+
+// RedoLastOperation re-applies the most recently undone operation
+func (a *App) RedoLastOperation() (*JournalEntry, error) {
+	return a.reverseOperation(journalRedo, journalUndo)
+}
+
+// reverseOperation pops an entry from one stack, reverts its steps in
+// reverse order and records the inverse on the other stack. A failed step
+// stops the run; the steps reverted so far are still recorded so they can
+// be re-applied.
+func (a *App) reverseOperation(from, to string) (*JournalEntry, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	entry, err := db.PopJournalEntry(from)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("nothing to %s", from)
+	}
+
+	inverse := &journalOp{entry: JournalEntry{ID: uuid.New().String(), Label: entry.Label}}
+	var stepErr error
+	for i := len(entry.Steps) - 1; i >= 0; i-- {
+		if stepErr = a.revertStep(entry.Steps[i], inverse); stepErr != nil {
+			break
+		}
+	}
+
+	if len(inverse.entry.Steps) > 0 {
+		saveJournalEntry(to, inverse)
+	}
+	removeJournalBackups([]string{entry.ID})
+	a.emitTreeChanged()
+
+	if stepErr != nil {
+		return entry, fmt.Errorf("%s of %q stopped: %w", from, entry.Label, stepErr)
+	}
+	return entry, nil
+}
+
+func (a *App) revertStep(step JournalStep, inverse *journalOp) error {
+	switch step.Kind {
+	case JournalCreate:
+		if _, err := os.Lstat(step.Path); os.IsNotExist(err) {
+			return nil
+		}
+		if err := inverse.deleting(step.Path); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(step.Path); err != nil {
+			return err
+		}
+		_ = db.ClearShadowPaths(step.Path)
+	case JournalRename:
+		if err := a.renamePath(step.NewPath, step.Path); err != nil {
+			return err
+		}
+		inverse.renamed(step.NewPath, step.Path)
+	case JournalDelete:
+		if _, err := os.Lstat(step.Path); err == nil {
+			return fmt.Errorf("%s already exists", step.Path)
+		}
+		if err := snapshotPath(step.Backup, step.Path); err != nil {
+			return err
+		}
+		inverse.created(step.Path)
+	case JournalReplace:
+		data, err := os.ReadFile(step.Backup)
+		if err != nil {
+			return err
+		}
+		if err := inverse.replacing(step.Path); err != nil {
+			return err
+		}
+		if err := writeFileAtomic(step.Path, data, 0644); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown journal step %q", step.Kind)
+	}
+	return nil
+}
+
+// journalDir is where backups of deleted and replaced files are kept
+func journalDir() string {
+	return filepath.Join(filepath.Dir(db.path), "journal")
+}
+
+func removeJournalBackups(ids []string) {
+	for _, id := range ids {
+		_ = os.RemoveAll(filepath.Join(journalDir(), id))
+	}
+}
+
+// snapshotPath copies a file or tree to dst, keeping symlinks as links
+func snapshotPath(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			return os.MkdirAll(target, info.Mode().Perm())
+		}
+		_, err = copyEntry(path, target, d, CopyOptions{Overwrite: OverwriteError})
+		return err
+	})
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// licenseHeaderSetting holds the project's header template, used by the
+// license-header lint rule and when ApplyLicenseHeader gets no template
+const licenseHeaderSetting = "license_header"
+
+var copyrightYearsExpr = regexp.MustCompile(`(\d{4})(?:\s*[-–]\s*(\d{4}))?`)
+
+// LicenseHeaderResult lists what ApplyLicenseHeader changed
+type LicenseHeaderResult struct {
+	Added     []string `json:"added"`
+	Updated   []string `json:"updated"`
+	Unchanged int      `json:"unchanged"`
+}
+
+// ApplyLicenseHeader puts a comment header at the top of every .adoc file
+// in the project, or refreshes an existing one. {year} in the template
+// becomes a year range that keeps the first year already in the file and
+// ends with the current one, e.g. "Copyright {year} ACME" ->
+// "Copyright 2021-2026 ACME". Changes can be undone as one operation.
+func (a *App) ApplyLicenseHeader(projectPath string, template string) (*LicenseHeaderResult, error) {
+	if template == "" {
+		template = a.projectSettingString(licenseHeaderSetting, "")
+	}
+	if strings.TrimSpace(template) == "" {
+		return nil, fmt.Errorf("no license header template given or set in %q", licenseHeaderSetting)
+	}
+	docs, err := globProjectFiles(projectPath, "**/*.adoc")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &LicenseHeaderResult{Added: []string{}, Updated: []string{}}
+	op := a.beginOperation("Apply license header")
+	year := time.Now().Year()
+	for _, path := range docs {
+		content, err := os.ReadFile(longPath(path))
+		if err != nil {
+			op.discard()
+			return result, newFileError("read", path, err)
+		}
+		updated, had := withLicenseHeader(string(content), template, year)
+		if updated == string(content) {
+			result.Unchanged++
+			continue
+		}
+		if err := op.replacing(path); err != nil {
+			op.discard()
+			return result, err
+		}
+		if err := writeFileAtomic(path, []byte(updated), 0644); err != nil {
+			a.commitOperation(op)
+			return result, newFileError("save", path, err)
+		}
+		if had {
+			result.Updated = append(result.Updated, path)
+		} else {
+			result.Added = append(result.Added, path)
+		}
+	}
+	a.commitOperation(op)
+	return result, nil
+}
+
+// CheckLicenseHeaders runs the license-header check over every .adoc file
+// in the project, for a CI-style report
+func (a *App) CheckLicenseHeaders(projectPath string, template string) ([]Diagnostic, error) {
+	if template == "" {
+		template = a.projectSettingString(licenseHeaderSetting, "")
+	}
+	docs, err := globProjectFiles(projectPath, "**/*.adoc")
+	if err != nil {
+		return nil, err
+	}
+	diagnostics := []Diagnostic{}
+	for _, path := range docs {
+		content, err := os.ReadFile(longPath(path))
+		if err != nil {
+			return nil, newFileError("read", path, err)
+		}
+		doc := &lintDocument{Path: path, Content: string(content), LicenseHeader: template}
+		for _, d := range checkLicenseHeader(doc) {
+			d.Path = path
+			d.Severity = SeverityWarn
+			d.Source = "lint"
+			d.Rule = "license-header"
+			diagnostics = append(diagnostics, d)
+		}
+	}
+	return diagnostics, nil
+}
+
+// checkLicenseHeader flags documents without the project's license header
+// or whose copyright year is out of date
+func checkLicenseHeader(doc *lintDocument) []Diagnostic {
+	if strings.TrimSpace(doc.LicenseHeader) == "" {
+		return nil
+	}
+	updated, had := withLicenseHeader(doc.Content, doc.LicenseHeader, time.Now().Year())
+	switch {
+	case !had:
+		return []Diagnostic{{Line: 1, Column: 1, Message: "License header is missing"}}
+	case updated != doc.Content:
+		return []Diagnostic{{Line: 1, Column: 1, Message: "License header is out of date"}}
+	}
+	return nil
+}
+
+// withLicenseHeader returns content with the rendered header in place of
+// the existing one, and whether it already had a header. A header is the
+// leading comment lines that read as the template does, whatever their
+// years; other leading comments are kept below a new header.
+func withLicenseHeader(content, template string, year int) (string, bool) {
+	lines := strings.SplitAfter(content, "\n")
+	templateLines := strings.Split(strings.TrimRight(template, "\r\n"), "\n")
+	had := len(lines) >= len(templateLines)
+	for i := 0; had && i < len(templateLines); i++ {
+		had = licenseHeaderLineExpr(templateLines[i]).MatchString(strings.TrimRight(lines[i], "\r\n"))
+	}
+	end := 0
+	if had {
+		end = len(templateLines)
+	}
+	existing := strings.Join(lines[:end], "")
+
+	first := year
+	if had {
+		if m := copyrightYearsExpr.FindStringSubmatch(existing); m != nil {
+			if y, err := strconv.Atoi(m[1]); err == nil && y <= year {
+				first = y
+			}
+		}
+	}
+	years := strconv.Itoa(year)
+	if first < year {
+		years = fmt.Sprintf("%d-%d", first, year)
+	}
+
+	newline := "\n"
+	if strings.Contains(content, "\r\n") {
+		newline = "\r\n"
+	}
+	var header strings.Builder
+	for _, line := range templateLines {
+		line = strings.TrimRight(strings.ReplaceAll(line, "{year}", years), "\r")
+		if line == "" {
+			header.WriteString("//" + newline)
+		} else {
+			header.WriteString("// " + line + newline)
+		}
+	}
+	return header.String() + strings.Join(lines[end:], ""), had
+}
+
+// licenseHeaderLineExpr matches a header comment line rendered from one
+// line of the template, with any year or year range in place of {year}
+func licenseHeaderLineExpr(templateLine string) *regexp.Regexp {
+	parts := strings.Split(strings.TrimSpace(templateLine), "{year}")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+	return regexp.MustCompile(`^//\s*` + strings.Join(parts, copyrightYearsExpr.String()) + `\s*$`)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"os"
+)
+
+// Line ending styles, as used by the "line_endings" project setting and
+// reported by DetectLineEnding
+const (
+	LineEndingLF       = "lf"
+	LineEndingCRLF     = "crlf"
+	LineEndingPreserve = "preserve"
+	LineEndingMixed    = "mixed"
+	LineEndingNone     = "none"
+)
+
+// DetectLineEnding reports whether a file uses LF, CRLF, a mix of both, or
+// has no line breaks at all
+func (a *App) DetectLineEnding(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return detectLineEnding(data), nil
+}
+
+func detectLineEnding(data []byte) string {
+	crlf := bytes.Count(data, []byte("\r\n"))
+	lf := bytes.Count(data, []byte("\n")) - crlf
+	switch {
+	case crlf == 0 && lf == 0:
+		return LineEndingNone
+	case crlf == 0:
+		return LineEndingLF
+	case lf == 0:
+		return LineEndingCRLF
+	default:
+		return LineEndingMixed
+	}
+}
+
+// normalizeLineEndings rewrites every line break in data to style. Any
+// other style (including "preserve") leaves data untouched.
+func normalizeLineEndings(data []byte, style string) []byte {
+	switch style {
+	case LineEndingLF:
+		return bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	case LineEndingCRLF:
+		lf := bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+		return bytes.ReplaceAll(lf, []byte("\n"), []byte("\r\n"))
+	default:
+		return data
+	}
+}
+
+// lineEndingStyle returns the current project's "line_endings" setting
+func (a *App) lineEndingStyle() string {
+	if style, ok := a.projectSetting("line_endings").(string); ok && style != "" {
+		return style
+	}
+	return LineEndingPreserve
+}
+
+// lineEndingFor returns the style to save path with. "preserve" keeps the
+// line endings the file has on disk, since the editor hands over LF text;
+// new files and files with mixed endings are written as given.
+func (a *App) lineEndingFor(path string) string {
+	style := a.lineEndingStyle()
+	if style != LineEndingPreserve {
+		return style
+	}
+	data, err := os.ReadFile(longPath(path))
+	if err != nil {
+		return LineEndingPreserve
+	}
+	switch existing := detectLineEnding(data); existing {
+	case LineEndingLF, LineEndingCRLF:
+		return existing
+	default:
+		return LineEndingPreserve
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Diagnostic severities, as used in the lint section of the project config
+const (
+	SeverityError  = "error"
+	SeverityWarn   = "warn"
+	SeverityInfo   = "info"
+	SeverityIgnore = "ignore"
+)
+
+// lintDocument is what a lint rule gets to look at
+type lintDocument struct {
+	Path    string
+	Content string
+	// LicenseHeader is the project's license header template, if any
+	LicenseHeader string
+	// Attributes are set for every document by the project config
+	Attributes map[string]string
+	Length     LengthLimits
+	// Terms is the inclusive-language wordlist
+	Terms    []inclusiveTerm
+	Acronyms *acronymRules
+}
+
+// lintRule checks a single document
+type lintRule struct {
+	ID       string
+	Severity string
+	Check    func(doc *lintDocument) []Diagnostic
+}
+
+// lintRules are run by LintFile. Their severity can be changed per project
+// under lint.rules in .ndxcraft/config.yaml, like that of any other
+// diagnostic source.
+var lintRules = []lintRule{
+	{ID: "reference-case", Severity: SeverityWarn, Check: checkReferenceCase},
+	{ID: "license-header", Severity: SeverityWarn, Check: checkLicenseHeader},
+	{ID: "image-alt-text", Severity: SeverityWarn, Check: checkImageAltText},
+	{ID: "bare-url", Severity: SeverityInfo, Check: checkBareURL},
+	{ID: "unbalanced-delimiter", Severity: SeverityError, Check: checkUnbalancedDelimiter},
+	{ID: "undefined-attribute", Severity: SeverityWarn, Check: checkUndefinedAttribute},
+	{ID: "page-length", Severity: SeverityWarn, Check: checkPageLength},
+	{ID: "section-length", Severity: SeverityWarn, Check: checkSectionLength},
+	{ID: "section-intro", Severity: SeverityWarn, Check: checkSectionIntro},
+	{ID: "inclusive-language", Severity: SeverityWarn, Check: checkInclusiveLanguage},
+	{ID: "acronym-expansion", Severity: SeverityWarn, Check: checkAcronymExpansion},
+}
+
+// LintFile runs the lint rules over a document. Findings silenced by an
+// "// ndxcraft-ignore" comment or recorded in the lint baseline are left
+// out. They also replace the file's lint results in the problems panel.
+func (a *App) LintFile(path string) ([]Diagnostic, error) {
+	diagnostics, err := a.runLintRules(path)
+	if err != nil {
+		return nil, err
+	}
+	return a.storeDiagnostics(DiagnosticSourceLint, path, diagnostics), nil
+}
+
+// runLintRules returns the findings of every enabled rule, before
+// suppressions and the baseline are applied
+func (a *App) runLintRules(path string) ([]Diagnostic, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return a.lintContent(path, content), nil
+}
+
+// lintContent runs the enabled rules over content as the document at path
+func (a *App) lintContent(path string, content []byte) []Diagnostic {
+	doc := &lintDocument{
+		Path:          path,
+		Content:       string(content),
+		LicenseHeader: a.projectSettingString(licenseHeaderSetting, ""),
+	}
+	root := a.currentProjectRoot()
+	cfg, err := LoadProjectConfig(root)
+	if err == nil && cfg != nil {
+		doc.Attributes = projectAttributes(root, cfg)
+		doc.Length = cfg.Lint.Length
+	}
+	doc.Terms = inclusiveTerms(cfg)
+	doc.Acronyms = loadAcronymRules(root, cfg)
+
+	overrides := a.severityOverrides()
+	diagnostics := []Diagnostic{}
+	for _, rule := range lintRules {
+		severity := ruleSeverity(overrides, DiagnosticSourceLint, rule.ID, rule.Severity)
+		if severity == SeverityIgnore {
+			continue
+		}
+		for _, d := range rule.Check(doc) {
+			d.Path = path
+			d.Severity = severity
+			d.Source = DiagnosticSourceLint
+			d.Rule = rule.ID
+			diagnostics = append(diagnostics, d)
+		}
+	}
+	return diagnostics
+}
+
+// referencePath resolves a reference target against the document it is in
+func referencePath(doc *lintDocument, ref adocReference) string {
+	dir := filepath.Dir(doc.Path)
+	if ref.Kind == refImage {
+		images := imagesDir(doc.Content)
+		if images == "" {
+			images = doc.Attributes["imagesdir"]
+		}
+		if images != "" {
+			dir = filepath.Join(dir, filepath.FromSlash(images))
+		}
+	}
+	target := filepath.FromSlash(ref.Target)
+	if filepath.IsAbs(target) {
+		return target
+	}
+	return filepath.Join(dir, target)
+}
+
+// checkReferenceCase flags references that only resolve because the file
+// system ignores case. They break on Linux CI.
+func checkReferenceCase(doc *lintDocument) []Diagnostic {
+	var found []Diagnostic
+	for _, ref := range parseReferences(doc.Content, doc.Attributes) {
+		actual, ok := actualCase(referencePath(doc, ref))
+		if !ok {
+			continue
+		}
+		onDisk, err := filepath.Rel(filepath.Dir(doc.Path), actual)
+		if err != nil {
+			onDisk = actual
+		}
+		found = append(found, Diagnostic{
+			Line:    ref.Line,
+			Column:  ref.Column,
+			Message: fmt.Sprintf("%s target %q differs in case from the file on disk (%s)", ref.Kind, ref.Target, filepath.ToSlash(onDisk)),
+		})
+	}
+	return found
+}
+
+// actualCase looks path up one component at a time and reports the path
+// as spelled on disk when some component matches only case-insensitively
+func actualCase(path string) (string, bool) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+	vol := filepath.VolumeName(abs)
+	current := vol + string(filepath.Separator)
+	mismatch := false
+	for _, part := range strings.Split(strings.TrimPrefix(abs[len(vol):], string(filepath.Separator)), string(filepath.Separator)) {
+		entries, err := os.ReadDir(current)
+		if err != nil {
+			return "", false
+		}
+		match := ""
+		for _, e := range entries {
+			if e.Name() == part {
+				match = part
+				break
+			}
+			if match == "" && strings.EqualFold(e.Name(), part) {
+				match = e.Name()
+			}
+		}
+		if match == "" {
+			return "", false
+		}
+		mismatch = mismatch || match != part
+		current = filepath.Join(current, match)
+	}
+	return current, mismatch
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// lintBaselineVersion is written to baseline files and checked on load
+const lintBaselineVersion = 1
+
+// LintBaselineFile holds the findings that existed when the baseline was
+// generated. It lives in .ndxcraft so it is shared with the team.
+type LintBaselineFile struct {
+	Version   int                              `json:"version"`
+	Generated string                           `json:"generated"`
+	Files     map[string][]LintBaselineFinding `json:"files"`
+}
+
+// LintBaselineFinding is a recorded finding. The fingerprint covers the
+// rule, message and text of the line, not its number, so findings stay
+// known when lines are added above them.
+type LintBaselineFinding struct {
+	Fingerprint string `json:"fingerprint"`
+	Rule        string `json:"rule"`
+	Message     string `json:"message"`
+	Count       int    `json:"count"`
+}
+
+// LintBaselineResult is returned by GenerateLintBaseline
+type LintBaselineResult struct {
+	Path     string `json:"path"`
+	Files    int    `json:"files"`
+	Findings int    `json:"findings"`
+}
+
+// lintBaseline is a loaded baseline file
+type lintBaseline struct {
+	root  string
+	files map[string]map[string]int
+}
+
+type cachedBaseline struct {
+	modTime  time.Time
+	baseline *lintBaseline
+}
+
+var (
+	baselineCacheMu sync.Mutex
+	baselineCache   = make(map[string]cachedBaseline)
+)
+
+// lintBaselinePath returns where a project's baseline is kept
+func lintBaselinePath(root string) string {
+	return filepath.Join(root, ProjectConfigDir, "lint-baseline.json")
+}
+
+// GenerateLintBaseline lints every document of the project and records
+// the findings, so that from now on only new ones are reported. Running
+// it again replaces the baseline.
+func (a *App) GenerateLintBaseline(projectPath string) (*LintBaselineResult, error) {
+	docs, err := globFiles(projectPath, "**/*.adoc")
+	if err != nil {
+		return nil, err
+	}
+	file := LintBaselineFile{
+		Version:   lintBaselineVersion,
+		Generated: time.Now().UTC().Format(time.RFC3339),
+		Files:     map[string][]LintBaselineFinding{},
+	}
+	result := &LintBaselineResult{Path: lintBaselinePath(projectPath)}
+	for _, path := range docs {
+		diagnostics, err := a.runLintRules(path)
+		if err != nil {
+			return nil, newFileError("read", path, err)
+		}
+		diagnostics = a.filterDiagnostics(path, diagnostics, nil)
+		if len(diagnostics) == 0 {
+			continue
+		}
+		content, err := os.ReadFile(longPath(path))
+		if err != nil {
+			return nil, newFileError("read", path, err)
+		}
+		index := map[string]int{}
+		var findings []LintBaselineFinding
+		for _, d := range diagnostics {
+			fp := baselineFingerprint(d, string(content))
+			if i, ok := index[fp]; ok {
+				findings[i].Count++
+				continue
+			}
+			index[fp] = len(findings)
+			findings = append(findings, LintBaselineFinding{Fingerprint: fp, Rule: d.Rule, Message: d.Message, Count: 1})
+		}
+		rel, _ := filepath.Rel(projectPath, path)
+		file.Files[filepath.ToSlash(rel)] = findings
+		result.Files++
+		result.Findings += len(diagnostics)
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(result.Path), 0755); err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(result.Path, append(data, '\n'), 0644); err != nil {
+		return nil, newFileError("write", result.Path, err)
+	}
+	return result, nil
+}
+
+// lintBaseline returns the open project's baseline, or nil
+func (a *App) lintBaseline() *lintBaseline {
+	root := a.currentProjectRoot()
+	if root == "" {
+		return nil
+	}
+	path := lintBaselinePath(root)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	baselineCacheMu.Lock()
+	defer baselineCacheMu.Unlock()
+	if cached, ok := baselineCache[path]; ok && cached.modTime.Equal(info.ModTime()) {
+		return cached.baseline
+	}
+
+	baseline := &lintBaseline{root: root, files: map[string]map[string]int{}}
+	data, err := os.ReadFile(path)
+	var file LintBaselineFile
+	if err == nil {
+		err = json.Unmarshal(data, &file)
+	}
+	if err != nil || file.Version > lintBaselineVersion {
+		// An unreadable baseline hides nothing
+		baseline = nil
+	} else {
+		for rel, findings := range file.Files {
+			counts := map[string]int{}
+			for _, f := range findings {
+				counts[f.Fingerprint] += f.Count
+			}
+			baseline.files[rel] = counts
+		}
+	}
+	baselineCache[path] = cachedBaseline{modTime: info.ModTime(), baseline: baseline}
+	return baseline
+}
+
+// remaining returns a copy of the recorded counts for a file, to be used
+// up as findings are matched
+func (b *lintBaseline) remaining(path string) map[string]int {
+	rel, err := filepath.Rel(b.root, path)
+	if err != nil {
+		return nil
+	}
+	counts := map[string]int{}
+	for fp, n := range b.files[filepath.ToSlash(rel)] {
+		counts[fp] = n
+	}
+	return counts
+}
+
+// baselineFingerprint identifies a finding by what it says and the line it
+// is on
+func baselineFingerprint(d Diagnostic, content string) string {
+	_, line, _ := lineAt(content, d.Line)
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s\x00%s\x00%s\x00%s", d.Source, d.Rule, d.Message, strings.TrimSpace(line))))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	lintImageExpr     = regexp.MustCompile(`image::?([^\[\s]+)\[([^\]]*)\]`)
+	lintBareURLExpr   = regexp.MustCompile(`(?:^|[\s(])(https?://[^\s\[\]<>]+)`)
+	lintAttrRefExpr   = regexp.MustCompile(`(\\?)\{([A-Za-z0-9_][\w-]*)\}`)
+	lintAttrEntryExpr = regexp.MustCompile(`^:([\w-]+)!?:`)
+)
+
+// builtinAttributes are provided by Asciidoctor and need no definition
+var builtinAttributes = map[string]bool{
+	"amp": true, "apos": true, "asterisk": true, "author": true, "authorinitials": true, "backend": true,
+	"backslash": true, "backtick": true, "blank": true, "brvbar": true, "caret": true, "cpp": true,
+	"deg": true, "docdate": true, "docdatetime": true, "docdir": true, "docfile": true, "docname": true,
+	"doctime": true, "doctitle": true, "doctype": true, "docyear": true, "email": true, "empty": true,
+	"endsb": true, "firstname": true, "gt": true, "imagesdir": true, "lastname": true, "ldquo": true,
+	"localdate": true, "localdatetime": true, "localtime": true, "localyear": true, "lsquo": true,
+	"lt": true, "middlename": true, "nbsp": true, "outfilesuffix": true, "plus": true, "pp": true,
+	"quot": true, "rdquo": true, "revdate": true, "revnumber": true, "revremark": true, "rsquo": true,
+	"sp": true, "startsb": true, "tilde": true, "toc": true, "two-colons": true, "two-semicolons": true,
+	"vbar": true, "wj": true, "zwsp": true,
+}
+
+// forEachProseLine calls fn with the 1-based number and text of every line
+// outside verbatim blocks and comments, where markup is interpreted
+func forEachProseLine(content string, fn func(n int, line string)) {
+	delimiter := ""
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		if delimiter != "" {
+			if trimmed == delimiter {
+				delimiter = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") {
+			delimiter = "```"
+			continue
+		}
+		if slices.Contains(adocVerbatimSet, trimmed) {
+			delimiter = trimmed
+			continue
+		}
+		if strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+		fn(i+1, line)
+	}
+}
+
+// countWords counts the words of a document's prose, leaving out
+// attribute entries, comments and verbatim blocks
+func countWords(content string) int {
+	words := 0
+	forEachProseLine(content, func(n int, line string) {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, ":") || isBlockDelimiter(trimmed) {
+			return
+		}
+		trimmed = strings.TrimLeft(trimmed, "=*.-#| ")
+		words += len(strings.Fields(trimmed))
+	})
+	return words
+}
+
+// checkImageAltText flags images without alt text, which screen readers
+// then announce by file name
+func checkImageAltText(doc *lintDocument) []Diagnostic {
+	var found []Diagnostic
+	forEachProseLine(doc.Content, func(n int, line string) {
+		for _, m := range lintImageExpr.FindAllStringSubmatchIndex(line, -1) {
+			if imageAltText(line[m[4]:m[5]]) != "" {
+				continue
+			}
+			found = append(found, Diagnostic{
+				Line:    n,
+				Column:  m[0] + 1,
+				Message: fmt.Sprintf("Image %s has no alt text", line[m[2]:m[3]]),
+			})
+		}
+	})
+	return found
+}
+
+// imageAltText returns the alt text from an image macro's attribute list:
+// the first positional attribute or alt=
+func imageAltText(attrs string) string {
+	for i, attr := range strings.Split(attrs, ",") {
+		attr = strings.TrimSpace(attr)
+		if name, value, ok := strings.Cut(attr, "="); ok {
+			if strings.TrimSpace(name) == "alt" {
+				return strings.Trim(strings.TrimSpace(value), `"`)
+			}
+			continue
+		}
+		if i == 0 {
+			return strings.Trim(attr, `"`)
+		}
+	}
+	return ""
+}
+
+// checkBareURL flags URLs without link text, which render as the raw
+// address
+func checkBareURL(doc *lintDocument) []Diagnostic {
+	var found []Diagnostic
+	forEachProseLine(doc.Content, func(n int, line string) {
+		if strings.HasPrefix(line, ":") {
+			// Attribute entries hold URLs as values
+			return
+		}
+		for _, m := range lintBareURLExpr.FindAllStringSubmatchIndex(line, -1) {
+			start, end := m[2], bareURLEnd(line, m[2], m[3])
+			if end < len(line) && line[end] == '[' {
+				continue
+			}
+			if strings.Count(line[:start], "`")%2 == 1 {
+				continue
+			}
+			found = append(found, Diagnostic{
+				Line:    n,
+				Column:  start + 1,
+				Message: fmt.Sprintf("URL %s has no link text", line[start:end]),
+			})
+		}
+	})
+	return found
+}
+
+// bareURLEnd drops trailing punctuation that ends the sentence rather
+// than the URL
+func bareURLEnd(line string, start, end int) int {
+	for end > start && strings.ContainsRune(".,;:!?)'\"", rune(line[end-1])) {
+		end--
+	}
+	return end
+}
+
+// openDelimiter is a block whose closing delimiter hasn't been seen yet
+type openDelimiter struct {
+	delimiter string
+	line      int
+}
+
+// checkUnbalancedDelimiter flags blocks that are never closed, which
+// swallow the rest of the document
+func checkUnbalancedDelimiter(doc *lintDocument) []Diagnostic {
+	var found []Diagnostic
+	for _, open := range unclosedDelimiters(doc.Content) {
+		found = append(found, Diagnostic{
+			Line:    open.line,
+			Column:  1,
+			Message: fmt.Sprintf("Block opened with %s is never closed", open.delimiter),
+		})
+	}
+	return found
+}
+
+// unclosedDelimiters pairs block delimiters and returns those left open
+func unclosedDelimiters(content string) []openDelimiter {
+	var stack []openDelimiter
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			trimmed = "```"
+		}
+		if len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if trimmed == top.delimiter {
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			if top.delimiter == "```" || slices.Contains(adocVerbatimSet, top.delimiter) {
+				continue
+			}
+		}
+		if isBlockDelimiter(trimmed) {
+			stack = append(stack, openDelimiter{delimiter: trimmed, line: i + 1})
+		}
+	}
+	return stack
+}
+
+// checkUndefinedAttribute flags attribute references that are defined
+// neither in the document, the files it includes, the project config nor
+// by Asciidoctor. They render as the literal {name}.
+func checkUndefinedAttribute(doc *lintDocument) []Diagnostic {
+	defined := definedAttributes(doc.Content)
+	for _, ref := range parseReferences(doc.Content, doc.Attributes) {
+		if ref.Kind != refInclude {
+			continue
+		}
+		if content, err := os.ReadFile(longPath(referencePath(doc, ref))); err == nil {
+			for name := range definedAttributes(string(content)) {
+				defined[name] = true
+			}
+		}
+	}
+
+	var found []Diagnostic
+	forEachProseLine(doc.Content, func(n int, line string) {
+		if lintAttrEntryExpr.MatchString(line) {
+			return
+		}
+		for _, m := range lintAttrRefExpr.FindAllStringSubmatchIndex(line, -1) {
+			name := line[m[4]:m[5]]
+			if m[3] > m[2] || defined[name] || builtinAttributes[name] {
+				continue
+			}
+			if _, ok := doc.Attributes[name]; ok {
+				continue
+			}
+			found = append(found, Diagnostic{
+				Line:    n,
+				Column:  m[0] + 1,
+				Message: fmt.Sprintf("Attribute {%s} is not defined", name),
+			})
+		}
+	})
+	return found
+}
+
+// definedAttributes collects the names set by attribute entries
+func definedAttributes(content string) map[string]bool {
+	names := map[string]bool{}
+	for _, line := range strings.Split(content, "\n") {
+		if m := lintAttrEntryExpr.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			names[m[1]] = true
+		}
+	}
+	return names
+}
+
+// altTextFromFile suggests alt text from an image's file name, e.g.
+// "network-diagram.png" -> "Network diagram"
+func altTextFromFile(target string) string {
+	name := strings.TrimSuffix(filepath.Base(target), filepath.Ext(target))
+	name = strings.Join(strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == '_' || r == ' ' }), " ")
+	if name == "" {
+		return "Image"
+	}
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(r)) + name[size:]
+}
+
+// checkPageLength flags pages over the lint.length.maxPageWords budget
+func checkPageLength(doc *lintDocument) []Diagnostic {
+	limit := doc.Length.MaxPageWords
+	if limit <= 0 {
+		return nil
+	}
+	words := countWords(doc.Content)
+	if words <= limit {
+		return nil
+	}
+	line := 1
+	if sections := parseSections(doc.Content, SlugOptions{}); len(sections) > 0 && sections[0].Level == 0 {
+		line = sections[0].Line
+	}
+	return []Diagnostic{{
+		Line:    line,
+		Column:  1,
+		Message: fmt.Sprintf("Page has %d words, over the budget of %d; consider splitting it", words, limit),
+	}}
+}
+
+// checkSectionLength flags sections whose own text, up to the next
+// heading, is over the lint.length.maxSectionWords budget
+func checkSectionLength(doc *lintDocument) []Diagnostic {
+	limit := doc.Length.MaxSectionWords
+	if limit <= 0 {
+		return nil
+	}
+	var found []Diagnostic
+	for _, s := range parseSections(doc.Content, SlugOptions{}) {
+		if s.Level == 0 {
+			continue
+		}
+		if words := countWords(s.Body); words > limit {
+			found = append(found, Diagnostic{
+				Line:    s.Line,
+				Column:  1,
+				Message: fmt.Sprintf("Section %q has %d words, over the budget of %d", s.Title, words, limit),
+			})
+		}
+	}
+	return found
+}
+
+// checkSectionIntro flags sections that go straight into a subheading
+// with less than lint.length.minIntroWords of introduction
+func checkSectionIntro(doc *lintDocument) []Diagnostic {
+	limit := doc.Length.MinIntroWords
+	if limit <= 0 {
+		return nil
+	}
+	var found []Diagnostic
+	sections := parseSections(doc.Content, SlugOptions{})
+	for i := 0; i+1 < len(sections); i++ {
+		s := sections[i]
+		if sections[i+1].Level <= s.Level {
+			continue
+		}
+		if words := countWords(s.Body); words < limit {
+			found = append(found, Diagnostic{
+				Line:    s.Line,
+				Column:  1,
+				Message: fmt.Sprintf("%q needs at least %d words of introduction before its first subheading (has %d)", s.Title, limit, words),
+			})
+		}
+	}
+	return found
+}
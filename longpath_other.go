@@ -0,0 +1,19 @@
+//go:build !windows
+
+package main
+
+import (
+	"errors"
+	"syscall"
+)
+
+// longPath is a no-op outside Windows
+func longPath(path string) string {
+	return path
+}
+
+// isPathTooLong reports whether err means the path exceeds what the file
+// system accepts
+func isPathTooLong(err error) bool {
+	return errors.Is(err, syscall.ENAMETOOLONG)
+}
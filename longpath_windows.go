@@ -0,0 +1,40 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// maxPath is the classic MAX_PATH limit, less room for an 8.3 file name
+// that CreateDirectory insists on
+const maxPath = 248
+
+// longPath returns path in \\?\ form when it is too long for the regular
+// Win32 API. The os package already does this for absolute paths; this
+// covers relative ones and paths handed to other tools.
+func longPath(path string) string {
+	if len(path) < maxPath || strings.HasPrefix(path, `\\?\`) {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	if strings.HasPrefix(abs, `\\`) {
+		return `\\?\UNC\` + abs[2:]
+	}
+	return `\\?\` + abs
+}
+
+// errFilenameExcedRange is ERROR_FILENAME_EXCED_RANGE
+const errFilenameExcedRange = syscall.Errno(206)
+
+// isPathTooLong reports whether err means the path exceeds what the file
+// system accepts, even in long form
+func isPathTooLong(err error) bool {
+	return errors.Is(err, errFilenameExcedRange)
+}
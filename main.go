@@ -2,10 +2,12 @@ package main
 
 import (
 	"embed"
+	"os"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
+	"github.com/wailsapp/wails/v2/pkg/options/mac"
 )
 
 //go:embed all:frontend/dist
@@ -17,8 +19,15 @@ func main() {
 		println("Error initializing database:", err.Error())
 	}
 
+	if code, ok := runCheckCommand(os.Args[1:]); ok {
+		os.Exit(code)
+	}
+
 	// Create an instance of the app structure
 	app := NewApp()
+	app.safeMode = safeModeRequested(os.Args[1:])
+	cwd, _ := os.Getwd()
+	app.pendingOpen = app.parseOpenArgs(os.Args[1:], cwd)
 
 	// Create application with options
 	err := wails.Run(&options.App{
@@ -29,7 +38,19 @@ func main() {
 			Assets: assets,
 		},
 		BackgroundColour: &options.RGBA{R: 27, G: 38, B: 54, A: 1},
-		OnStartup:        app.startup,
+		DragAndDrop: &options.DragAndDrop{
+			EnableFileDrop:     true,
+			DisableWebViewDrop: true,
+		},
+		SingleInstanceLock: &options.SingleInstanceLock{
+			UniqueId:               "com.ndx-video.ndxcraft",
+			OnSecondInstanceLaunch: app.handleSecondInstance,
+		},
+		Mac: &mac.Options{
+			OnUrlOpen: app.handleOpenURL,
+		},
+		OnStartup:      app.startup,
+		ErrorFormatter: errorFormatter,
 		Bind: []interface{}{
 			app,
 		},
@@ -0,0 +1,29 @@
+//go:build darwin
+
+package main
+
+import "syscall"
+
+var networkFSTypes = map[string]bool{
+	"smbfs":   true,
+	"nfs":     true,
+	"afpfs":   true,
+	"webdav":  true,
+	"macfuse": true,
+}
+
+// isNetworkPath reports whether path lives on a network filesystem
+func isNetworkPath(path string) bool {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return false
+	}
+	name := make([]byte, 0, len(st.Fstypename))
+	for _, c := range st.Fstypename {
+		if c == 0 {
+			break
+		}
+		name = append(name, byte(c))
+	}
+	return networkFSTypes[string(name)]
+}
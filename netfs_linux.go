@@ -0,0 +1,25 @@
+//go:build linux
+
+package main
+
+import "syscall"
+
+// Filesystem magic numbers of network mounts (see statfs(2))
+var networkFSTypes = map[int64]bool{
+	0x6969:     true, // NFS
+	0x517B:     true, // SMB
+	0xFF534D42: true, // CIFS
+	0xFE534D42: true, // SMB2
+	0x65735546: true, // FUSE (sshfs, rclone, ...)
+	0x564C:     true, // NCP
+	0x00C36400: true, // Ceph
+}
+
+// isNetworkPath reports whether path lives on a network filesystem
+func isNetworkPath(path string) bool {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return false
+	}
+	return networkFSTypes[int64(st.Type)]
+}
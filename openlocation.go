@@ -0,0 +1,151 @@
+package main
+
+import (
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/options"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// EventOpenLocation is emitted with an OpenLocation when a file is opened
+// from the command line or an ndxcraft:// link while the app is running
+const EventOpenLocation = "editor:openLocation"
+
+// OpenLocation is a file to open and where to put the cursor. Line and
+// Column are 1-based; 0 means unspecified.
+type OpenLocation struct {
+	Path   string `json:"path"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
+
+// lineSuffixExpr matches the "file.adoc:12" and "file.adoc:12:5" forms
+// printed by compilers and linters
+var lineSuffixExpr = regexp.MustCompile(`^(.+?):(\d+)(?::(\d+))?$`)
+
+// GetPendingOpenLocation returns the location given on the command line
+// at launch, once; the frontend asks for it when the editor is ready
+func (a *App) GetPendingOpenLocation() *OpenLocation {
+	a.pendingOpenMu.Lock()
+	defer a.pendingOpenMu.Unlock()
+	loc := a.pendingOpen
+	a.pendingOpen = nil
+	return loc
+}
+
+// handleSecondInstance receives the arguments of a second launch, which
+// exits straight away, and positions this instance's editor
+func (a *App) handleSecondInstance(data options.SecondInstanceData) {
+	if a.ctx == nil {
+		return
+	}
+	runtime.WindowUnminimise(a.ctx)
+	runtime.Show(a.ctx)
+	if loc := a.parseOpenArgs(data.Args, data.WorkingDirectory); loc != nil {
+		runtime.EventsEmit(a.ctx, EventOpenLocation, loc)
+	}
+}
+
+// handleOpenURL receives ndxcraft:// links on macOS, where they are not
+// passed as arguments
+func (a *App) handleOpenURL(rawURL string) {
+	loc := a.parseOpenURL(rawURL)
+	if loc == nil {
+		return
+	}
+	if a.ctx == nil {
+		a.pendingOpenMu.Lock()
+		a.pendingOpen = loc
+		a.pendingOpenMu.Unlock()
+		return
+	}
+	runtime.EventsEmit(a.ctx, EventOpenLocation, loc)
+}
+
+// parseOpenArgs finds the first path[:line[:column]] or ndxcraft:// URL in
+// command line arguments. Flags are skipped and relative paths resolved
+// against dir.
+func (a *App) parseOpenArgs(args []string, dir string) *OpenLocation {
+	for _, arg := range args {
+		if arg == "" || strings.HasPrefix(arg, "-") {
+			continue
+		}
+		if strings.HasPrefix(strings.ToLower(arg), "ndxcraft:") {
+			if loc := a.parseOpenURL(arg); loc != nil {
+				return loc
+			}
+			continue
+		}
+		if loc := parseFileLocation(arg, dir); loc != nil {
+			return loc
+		}
+	}
+	return nil
+}
+
+// parseOpenURL reads ndxcraft://open?file=<path>&line=<n>&column=<n>. A
+// relative file is taken from the open project, so CI can link to
+// repository paths.
+func (a *App) parseOpenURL(rawURL string) *OpenLocation {
+	u, err := url.Parse(rawURL)
+	if err != nil || !strings.EqualFold(u.Scheme, "ndxcraft") {
+		return nil
+	}
+	if u.Host != "open" && strings.Trim(u.Opaque+u.Path, "/") != "open" {
+		return nil
+	}
+	q := u.Query()
+	file := q.Get("file")
+	if file == "" {
+		return nil
+	}
+	path := filepath.FromSlash(file)
+	if !filepath.IsAbs(path) {
+		root := a.currentProjectRoot()
+		if root == "" {
+			return nil
+		}
+		path = filepath.Join(root, path)
+		if !isWithin(path, root) {
+			return nil
+		}
+	}
+	if !exists(path) {
+		return nil
+	}
+	loc := &OpenLocation{Path: filepath.Clean(path)}
+	loc.Line, _ = strconv.Atoi(q.Get("line"))
+	loc.Column, _ = strconv.Atoi(q.Get("column"))
+	loc.Line, loc.Column = max(loc.Line, 0), max(loc.Column, 0)
+	return loc
+}
+
+// parseFileLocation splits "path:line:column", preferring a file whose
+// name really ends in ":12" when one exists
+func parseFileLocation(arg, dir string) *OpenLocation {
+	abs := func(p string) string {
+		if !filepath.IsAbs(p) && dir != "" {
+			p = filepath.Join(dir, p)
+		}
+		return filepath.Clean(p)
+	}
+	if path := abs(arg); exists(path) {
+		return &OpenLocation{Path: path}
+	}
+	m := lineSuffixExpr.FindStringSubmatch(arg)
+	if m == nil {
+		return nil
+	}
+	path := abs(m[1])
+	if !exists(path) {
+		return nil
+	}
+	loc := &OpenLocation{Path: path}
+	loc.Line, _ = strconv.Atoi(m[2])
+	loc.Column, _ = strconv.Atoi(m[3])
+	return loc
+}
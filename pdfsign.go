@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// Keychain entries SignPDF uses unless told otherwise. The certificate is
+// stored base64-encoded.
+const (
+	defaultSigningCertSecret     = "pdf-signing-certificate"
+	defaultSigningPasswordSecret = "pdf-signing-password"
+)
+
+// pdfSignatureSize is the room reserved for the CMS signature, in bytes
+const pdfSignatureSize = 16384
+
+// PDFSignOptions controls SignPDF
+type PDFSignOptions struct {
+	// CertificateSecret names a keychain secret holding a base64 PKCS#12
+	// file; PasswordSecret names the secret holding its password
+	CertificateSecret string `json:"certificateSecret"`
+	PasswordSecret    string `json:"passwordSecret"`
+	Reason            string `json:"reason"`
+	Location          string `json:"location"`
+	ContactInfo       string `json:"contactInfo"`
+}
+
+// PDFSignature describes a signature added by SignPDF
+type PDFSignature struct {
+	Path     string    `json:"path"`
+	Signer   string    `json:"signer"`
+	Issuer   string    `json:"issuer"`
+	NotAfter time.Time `json:"notAfter"`
+	SignedAt time.Time `json:"signedAt"`
+}
+
+// SignPDF adds an invisible digital signature to a PDF, using a PKCS#12
+// certificate kept in the OS keychain. The signature is appended as an
+// incremental update, so existing content is left byte-for-byte intact.
+func (a *App) SignPDF(path string, opts PDFSignOptions) (*PDFSignature, error) {
+	key, cert, chain, err := loadSigningIdentity(opts)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(longPath(path))
+	if err != nil {
+		return nil, newFileError("sign", path, err)
+	}
+
+	now := time.Now()
+	signed, err := signPDF(data, opts, now, func(content []byte) ([]byte, error) {
+		return signDetached(content, key, cert, chain, now)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign %s: %w", path, err)
+	}
+	if err := writeFileAtomic(path, signed, 0644); err != nil {
+		return nil, newFileError("sign", path, err)
+	}
+	return &PDFSignature{
+		Path:     path,
+		Signer:   cert.Subject.CommonName,
+		Issuer:   cert.Issuer.CommonName,
+		NotAfter: cert.NotAfter,
+		SignedAt: now,
+	}, nil
+}
+
+// loadSigningIdentity reads the signing key, its certificate and the
+// intermediate certificates bundled with them, which are embedded in the
+// signature so validators can build the path to a trusted root
+func loadSigningIdentity(opts PDFSignOptions) (any, *x509.Certificate, []*x509.Certificate, error) {
+	certSecret := opts.CertificateSecret
+	if certSecret == "" {
+		certSecret = defaultSigningCertSecret
+	}
+	passwordSecret := opts.PasswordSecret
+	if passwordSecret == "" {
+		passwordSecret = defaultSigningPasswordSecret
+	}
+
+	encoded, err := getSecret(certSecret)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	p12, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("secret %q is not base64: %w", certSecret, err)
+	}
+	password, err := getSecret(passwordSecret)
+	if err != nil {
+		password = ""
+	}
+
+	key, cert, chain, err := pkcs12.DecodeChain(p12, password)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read signing certificate: %w", err)
+	}
+	if time.Now().After(cert.NotAfter) {
+		return nil, nil, nil, fmt.Errorf("signing certificate %q expired on %s", cert.Subject.CommonName, cert.NotAfter.Format(time.DateOnly))
+	}
+	return key, cert, chain, nil
+}
+
+// signPDF appends a signature field to the document. sign receives the
+// bytes covered by the signature and returns the DER-encoded CMS blob.
+func signPDF(data []byte, opts PDFSignOptions, at time.Time, sign func([]byte) ([]byte, error)) ([]byte, error) {
+	u, err := newPDFUpdate(data)
+	if err != nil {
+		return nil, err
+	}
+	body, _, ok := u.object(u.rootNum)
+	catalog, isDict := pdfDict(body)
+	if !ok || !isDict {
+		return nil, fmt.Errorf("document catalog not found")
+	}
+	if bytes.Contains(catalog, []byte("/AcroForm")) {
+		return nil, fmt.Errorf("PDFs that already contain form fields can't be signed yet")
+	}
+
+	// Signature dictionary. ByteRange and Contents are placeholders,
+	// filled in once the final layout is known.
+	const byteRangePlaceholder = "/ByteRange [0 0000000000 0000000000 0000000000]"
+	contentsPlaceholder := "/Contents <" + strings.Repeat("0", pdfSignatureSize*2) + ">"
+	var sigDict bytes.Buffer
+	sigDict.WriteString("<< /Type /Sig /Filter /Adobe.PPKLite /SubFilter /adbe.pkcs7.detached\n")
+	sigDict.WriteString(byteRangePlaceholder + "\n")
+	sigDict.WriteString(contentsPlaceholder + "\n")
+	fmt.Fprintf(&sigDict, "/M %s\n", pdfString(pdfDate(at)))
+	for _, field := range []struct{ key, value string }{
+		{"Reason", opts.Reason},
+		{"Location", opts.Location},
+		{"ContactInfo", opts.ContactInfo},
+	} {
+		if field.value != "" {
+			fmt.Fprintf(&sigDict, "/%s %s\n", field.key, pdfString(field.value))
+		}
+	}
+	sigDict.WriteString(">>")
+
+	sigNum := u.newObject()
+	sigAt := u.out.Len()
+	u.write(sigNum, 0, sigDict.Bytes())
+	fieldNum := u.newObject()
+	u.write(fieldNum, 0, fmt.Appendf(nil, "<< /Type /Annot /Subtype /Widget /FT /Sig /T (Signature%d) /V %d 0 R /Rect [0 0 0 0] /F 132 >>", fieldNum, sigNum))
+	u.write(u.rootNum, u.rootGen, fmt.Appendf(nil, "<< %s /AcroForm << /Fields [%d 0 R] /SigFlags 3 >> >>", catalog, fieldNum))
+	signed := u.finish()
+
+	byteRangeAt := sigAt + bytes.Index(signed[sigAt:], []byte(byteRangePlaceholder))
+	contentsAt := sigAt + bytes.Index(signed[sigAt:], []byte(contentsPlaceholder)) + len("/Contents ")
+	contentsEnd := contentsAt + pdfSignatureSize*2 + 1
+	byteRange := fmt.Sprintf("/ByteRange [0 %010d %010d %010d]", contentsAt, contentsEnd+1, len(signed)-contentsEnd-1)
+	copy(signed[byteRangeAt:], byteRange)
+
+	covered := append(append([]byte{}, signed[:contentsAt]...), signed[contentsEnd+1:]...)
+	sig, err := sign(covered)
+	if err != nil {
+		return nil, err
+	}
+	if len(sig) > pdfSignatureSize {
+		return nil, fmt.Errorf("signature is %d bytes, more than the %d reserved", len(sig), pdfSignatureSize)
+	}
+	hex.Encode(signed[contentsAt+1:], sig)
+	return signed, nil
+}
+
+// pdfDate formats t as a PDF date string
+func pdfDate(t time.Time) string {
+	_, offset := t.Zone()
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	return fmt.Sprintf("D:%s%s%02d'%02d'", t.Format("20060102150405"), sign, offset/3600, offset%3600/60)
+}
+
+// pdfString encodes s as a PDF literal string, or as UTF-16 hex when it
+// isn't plain ASCII
+func pdfString(s string) string {
+	ascii := true
+	for _, r := range s {
+		if r > 126 || r < 32 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		r := strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`)
+		return "(" + r.Replace(s) + ")"
+	}
+
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, r := range s {
+		if r > 0xFFFF {
+			r -= 0x10000
+			fmt.Fprintf(&b, "%04X%04X", 0xD800+(r>>10), 0xDC00+(r&0x3FF))
+			continue
+		}
+		fmt.Fprintf(&b, "%04X", r)
+	}
+	b.WriteString(">")
+	return b.String()
+}
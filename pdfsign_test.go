@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// fixturePDF builds a one-page PDF with a classic xref table
+func fixturePDF() []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] >>",
+	}
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return b.Bytes()
+}
+
+func testSigningIdentity(t *testing.T) (*ecdsa.PrivateKey, *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "Test Signer"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, cert
+}
+
+var byteRangeExpr = regexp.MustCompile(`/ByteRange \[(\d+) (\d+) (\d+) (\d+)\]`)
+
+func TestSignPDFRoundTrip(t *testing.T) {
+	original := fixturePDF()
+	key, cert := testSigningIdentity(t)
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	signed, err := signPDF(original, PDFSignOptions{Reason: "Approved"}, at, func(content []byte) ([]byte, error) {
+		return signDetached(content, key, cert, nil, at)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(signed, original) {
+		t.Fatal("incremental update changed the original bytes")
+	}
+
+	// ByteRange covers the whole file except the hex Contents string
+	m := byteRangeExpr.FindSubmatch(signed)
+	if m == nil {
+		t.Fatal("no ByteRange in signed PDF")
+	}
+	var r [4]int
+	for i := range r {
+		r[i], _ = strconv.Atoi(string(m[i+1]))
+	}
+	if r[0] != 0 || r[2]+r[3] != len(signed) {
+		t.Fatalf("ByteRange %v does not span the file of %d bytes", r, len(signed))
+	}
+	if signed[r[1]] != '<' || signed[r[2]-1] != '>' {
+		t.Fatalf("ByteRange gap %d-%d is not the Contents string", r[1], r[2])
+	}
+	if got := r[2] - r[1]; got != pdfSignatureSize*2+2 {
+		t.Fatalf("Contents is %d bytes, want %d", got, pdfSignatureSize*2+2)
+	}
+	covered := append(append([]byte{}, signed[r[0]:r[0]+r[1]]...), signed[r[2]:r[2]+r[3]]...)
+
+	blob := make([]byte, pdfSignatureSize)
+	if _, err := hex.Decode(blob, signed[r[1]+1:r[2]-1]); err != nil {
+		t.Fatal(err)
+	}
+
+	// The CMS blob parses and its signed digest is over the covered bytes
+	var ci contentInfo
+	if _, err := asn1.Unmarshal(blob, &ci); err != nil {
+		t.Fatalf("CMS blob does not parse: %v", err)
+	}
+	if !ci.ContentType.Equal(oidSignedData) {
+		t.Fatalf("content type %v, want signedData", ci.ContentType)
+	}
+	var sd signedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		t.Fatal(err)
+	}
+	embedded, err := x509.ParseCertificate(sd.Certificates.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !embedded.Equal(cert) {
+		t.Fatal("embedded certificate is not the signer's")
+	}
+	var info signerInfo
+	if _, err := asn1.Unmarshal(sd.SignerInfos.Bytes, &info); err != nil {
+		t.Fatal(err)
+	}
+
+	digest := sha256.Sum256(covered)
+	var attr struct {
+		Type   asn1.ObjectIdentifier
+		Values asn1.RawValue
+	}
+	found := false
+	for rest := info.SignedAttrs.Bytes; len(rest) > 0; {
+		if rest, err = asn1.Unmarshal(rest, &attr); err != nil {
+			t.Fatal(err)
+		}
+		if !attr.Type.Equal(oidMessageDigest) {
+			continue
+		}
+		var value []byte
+		if _, err := asn1.Unmarshal(attr.Values.Bytes, &value); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(value, digest[:]) {
+			t.Fatal("messageDigest does not match the ByteRange content")
+		}
+		found = true
+	}
+	if !found {
+		t.Fatal("no messageDigest attribute")
+	}
+
+	attrSet, err := asn1.Marshal(asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: info.SignedAttrs.Bytes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	attrDigest := sha256.Sum256(attrSet)
+	if !ecdsa.VerifyASN1(&key.PublicKey, attrDigest[:], info.Signature) {
+		t.Fatal("signature over the signed attributes does not verify")
+	}
+
+	// The update chains to the original xref and adds the signature field
+	u, err := newPDFUpdate(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	catalog, _, ok := u.object(u.rootNum)
+	if !ok || !bytes.Contains(catalog, []byte("/AcroForm")) {
+		t.Fatalf("catalog has no AcroForm: %s", catalog)
+	}
+	if !bytes.Contains(signed[len(original):], []byte("/Prev ")) {
+		t.Fatal("update trailer does not point at the previous xref")
+	}
+}
+
+func TestSignPDFRefusesExistingForm(t *testing.T) {
+	data := bytes.Replace(fixturePDF(), []byte("/Pages 2 0 R >>"), []byte("/Pages 2 0 R /AcroForm << >> >>"), 1)
+	_, err := signPDF(data, PDFSignOptions{}, time.Now(), func([]byte) ([]byte, error) { return nil, nil })
+	if err == nil {
+		t.Fatal("signed a PDF that already has form fields")
+	}
+}
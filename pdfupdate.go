@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+var (
+	pdfStartXrefExpr = regexp.MustCompile(`startxref\s+(\d+)\s+%%EOF\s*$`)
+	pdfTrailerExpr   = regexp.MustCompile(`(?s)trailer\s*<<(.*?)>>\s*startxref`)
+	pdfSizeExpr      = regexp.MustCompile(`/Size\s+(\d+)`)
+	pdfRootExpr      = regexp.MustCompile(`/Root\s+(\d+)\s+(\d+)\s+R`)
+	pdfInfoExpr      = regexp.MustCompile(`/Info\s+\d+\s+\d+\s+R`)
+	pdfIDExpr        = regexp.MustCompile(`(?s)/ID\s*\[.*?\]`)
+	pdfObjectExpr    = regexp.MustCompile(`(?s)(?:^|\s)(\d+)\s+(\d+)\s+obj\b(.*?)endobj`)
+)
+
+// pdfUpdate appends an incremental update to a PDF: new and replaced
+// objects go after the original bytes with their own xref section, so the
+// original content, and any signatures over it, stay valid. Only files
+// with a classic cross-reference table are supported.
+type pdfUpdate struct {
+	data     []byte
+	trailer  []byte
+	prevXref int
+	size     int
+	rootNum  int
+	rootGen  int
+
+	out     bytes.Buffer
+	offsets map[int]pdfXrefEntry
+}
+
+type pdfXrefEntry struct {
+	offset int
+	gen    int
+}
+
+func newPDFUpdate(data []byte) (*pdfUpdate, error) {
+	m := pdfStartXrefExpr.FindSubmatch(data)
+	if m == nil {
+		return nil, fmt.Errorf("not a PDF file or missing startxref")
+	}
+	prevXref, _ := strconv.Atoi(string(m[1]))
+	if prevXref >= len(data) || !bytes.HasPrefix(data[prevXref:], []byte("xref")) {
+		return nil, fmt.Errorf("PDFs with cross-reference streams are not supported yet")
+	}
+
+	trailers := pdfTrailerExpr.FindAllSubmatch(data, -1)
+	if trailers == nil {
+		return nil, fmt.Errorf("trailer not found")
+	}
+	trailer := trailers[len(trailers)-1][1]
+	sizeMatch := pdfSizeExpr.FindSubmatch(trailer)
+	rootMatch := pdfRootExpr.FindSubmatch(trailer)
+	if sizeMatch == nil || rootMatch == nil {
+		return nil, fmt.Errorf("trailer has no /Size or /Root")
+	}
+
+	u := &pdfUpdate{
+		data:     data,
+		trailer:  trailer,
+		prevXref: prevXref,
+		offsets:  make(map[int]pdfXrefEntry),
+	}
+	u.size, _ = strconv.Atoi(string(sizeMatch[1]))
+	u.rootNum, _ = strconv.Atoi(string(rootMatch[1]))
+	u.rootGen, _ = strconv.Atoi(string(rootMatch[2]))
+
+	u.out.Write(data)
+	if data[len(data)-1] != '\n' {
+		u.out.WriteByte('\n')
+	}
+	return u, nil
+}
+
+// object returns the body of the latest revision of an object
+func (u *pdfUpdate) object(num int) ([]byte, int, bool) {
+	expr := regexp.MustCompile(fmt.Sprintf(`(?s)(?:^|\s)%d\s+(\d+)\s+obj\b(.*?)endobj`, num))
+	all := expr.FindAllSubmatch(u.data, -1)
+	if all == nil {
+		return nil, 0, false
+	}
+	last := all[len(all)-1]
+	gen, _ := strconv.Atoi(string(last[1]))
+	return bytes.TrimSpace(last[2]), gen, true
+}
+
+// pages returns the object numbers of every page, in file order
+func (u *pdfUpdate) pages() []int {
+	latest := make(map[int][]byte)
+	var order []int
+	for _, m := range pdfObjectExpr.FindAllSubmatch(u.data, -1) {
+		num, _ := strconv.Atoi(string(m[1]))
+		if _, seen := latest[num]; !seen {
+			order = append(order, num)
+		}
+		latest[num] = m[3]
+	}
+	var pages []int
+	for _, num := range order {
+		if pdfPageExpr.Match(latest[num]) {
+			pages = append(pages, num)
+		}
+	}
+	return pages
+}
+
+// newObject reserves the next object number
+func (u *pdfUpdate) newObject() int {
+	num := u.size
+	u.size++
+	return num
+}
+
+// write appends an object; body is everything between "obj" and "endobj"
+func (u *pdfUpdate) write(num, gen int, body []byte) {
+	u.offsets[num] = pdfXrefEntry{offset: u.out.Len(), gen: gen}
+	fmt.Fprintf(&u.out, "%d %d obj\n", num, gen)
+	u.out.Write(body)
+	u.out.WriteString("\nendobj\n")
+}
+
+// finish writes the xref section and trailer and returns the new file
+func (u *pdfUpdate) finish() []byte {
+	nums := make([]int, 0, len(u.offsets))
+	for num := range u.offsets {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+
+	xrefAt := u.out.Len()
+	u.out.WriteString("xref\n0 1\n0000000000 65535 f \n")
+	for _, num := range nums {
+		e := u.offsets[num]
+		fmt.Fprintf(&u.out, "%d 1\n%010d %05d n \n", num, e.offset, e.gen)
+	}
+	fmt.Fprintf(&u.out, "trailer\n<< /Size %d /Root %d %d R /Prev %d", u.size, u.rootNum, u.rootGen, u.prevXref)
+	if info := pdfInfoExpr.Find(u.trailer); info != nil {
+		fmt.Fprintf(&u.out, " %s", info)
+	}
+	if id := pdfIDExpr.Find(u.trailer); id != nil {
+		fmt.Fprintf(&u.out, " %s", id)
+	}
+	fmt.Fprintf(&u.out, " >>\nstartxref\n%d\n%%%%EOF\n", xrefAt)
+	return u.out.Bytes()
+}
+
+// pdfDict returns the inside of a dictionary object body
+func pdfDict(body []byte) ([]byte, bool) {
+	if !bytes.HasPrefix(body, []byte("<<")) || !bytes.HasSuffix(body, []byte(">>")) {
+		return nil, false
+	}
+	return bytes.TrimSpace(body[2 : len(body)-2]), true
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"sync"
+)
+
+// Values of the "network_mode" setting
+const (
+	NetworkModeAuto = "auto"
+	NetworkModeOn   = "on"
+	NetworkModeOff  = "off"
+)
+
+// Autosave intervals the frontend should use
+const (
+	defaultAutosaveMs  = 2000
+	degradedAutosaveMs = 15000
+)
+
+// statWorkers is how many stats run at once in degraded mode, where each
+// one is a network round-trip
+const statWorkers = 8
+
+// PerformanceProfile tells the frontend how hard it may work the disk
+type PerformanceProfile struct {
+	Network bool `json:"network"`
+	// Degraded is set for network projects unless "network_mode" is "off"
+	Degraded         bool `json:"degraded"`
+	AutosaveInterval int  `json:"autosaveInterval"`
+	WatchFiles       bool `json:"watchFiles"`
+	BackgroundIndex  bool `json:"backgroundIndex"`
+}
+
+var (
+	networkRootsMu sync.Mutex
+	networkRoots   = make(map[string]bool)
+)
+
+// networkRoot caches isNetworkPath per project root; statfs on a stalled
+// share is itself slow
+func networkRoot(root string) bool {
+	networkRootsMu.Lock()
+	defer networkRootsMu.Unlock()
+	if v, ok := networkRoots[root]; ok {
+		return v
+	}
+	v := isNetworkPath(root)
+	networkRoots[root] = v
+	return v
+}
+
+// hasEmbeddings reports whether a project has been indexed before
+func hasEmbeddings(project string) bool {
+	if db == nil {
+		return false
+	}
+	rows, err := db.GetEmbeddings(project)
+	return err == nil && len(rows) > 0
+}
+
+// degraded reports whether a project should run in network drive mode
+func (a *App) degraded(root string) bool {
+	if root == "" {
+		return false
+	}
+	switch a.projectSettingString("network_mode", NetworkModeAuto) {
+	case NetworkModeOn:
+		return true
+	case NetworkModeOff:
+		return false
+	}
+	return networkRoot(root)
+}
+
+// GetPerformanceProfile returns the profile of a project: network-mounted
+// roots get longer autosave intervals and no file watching or background
+// indexing
+func (a *App) GetPerformanceProfile(projectPath string) PerformanceProfile {
+	p := PerformanceProfile{
+		Network:          networkRoot(projectPath),
+		Degraded:         a.degraded(projectPath),
+		AutosaveInterval: defaultAutosaveMs,
+		WatchFiles:       true,
+		BackgroundIndex:  true,
+	}
+	if p.Degraded {
+		p.AutosaveInterval = degradedAutosaveMs
+		p.WatchFiles = false
+		p.BackgroundIndex = false
+	}
+	return p
+}
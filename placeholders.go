@@ -0,0 +1,124 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Events emitted by HydrateFile
+const (
+	EventHydrateProgress = "file:hydrate:progress"
+	EventHydrateDone     = "file:hydrate:done"
+)
+
+// hydrateTimeout bounds how long HydrateFile waits for an evicted iCloud
+// file to reappear
+const hydrateTimeout = 5 * time.Minute
+
+// ErrNotDownloaded is returned when reading a cloud placeholder, which
+// would block until the sync provider has fetched it
+var ErrNotDownloaded = errors.New("file is not downloaded")
+
+// HydrateProgress is the payload of the hydrate events
+type HydrateProgress struct {
+	Path  string `json:"path"`
+	Read  int64  `json:"read"`
+	Size  int64  `json:"size"`
+	Done  bool   `json:"done"`
+	Error string `json:"error,omitempty"`
+}
+
+// icloudStub returns the stub iCloud Drive leaves in place of an evicted
+// file ("name.adoc" becomes ".name.adoc.icloud")
+func icloudStub(path string) string {
+	return filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".icloud")
+}
+
+// cloudPlaceholder reports whether path is a placeholder whose content has
+// not been downloaded yet
+func cloudPlaceholder(path string) bool {
+	info, err := os.Stat(path)
+	if err == nil {
+		return isCloudPlaceholder(path, info)
+	}
+	return os.IsNotExist(err) && exists(icloudStub(path))
+}
+
+// errNotDownloaded is returned instead of reading a placeholder
+func errNotDownloaded(op, path string) *FileError {
+	return &FileError{
+		Op:      op,
+		Path:    path,
+		Code:    FileErrNotDownloaded,
+		Message: path + " is stored in the cloud and not downloaded yet",
+		Hint:    "Download it to open it",
+		err:     ErrNotDownloaded,
+	}
+}
+
+// HydrateFile downloads a cloud placeholder in the background, emitting
+// EventHydrateProgress while it goes and EventHydrateDone at the end
+func (a *App) HydrateFile(path string) error {
+	if !cloudPlaceholder(path) {
+		return nil
+	}
+	if err := requestHydration(path); err != nil {
+		return newFileError("hydrate", path, err)
+	}
+	go a.hydrateFile(path)
+	return nil
+}
+
+func (a *App) hydrateFile(path string) {
+	result := HydrateProgress{Path: path}
+	if err := a.waitForHydration(path, &result); err != nil {
+		result.Error = err.Error()
+	}
+	result.Done = true
+	runtime.EventsEmit(a.ctx, EventHydrateDone, result)
+	if result.Error == "" {
+		a.emitTreeChanged()
+	}
+}
+
+// waitForHydration reads path through once, which makes Windows providers
+// fetch it, after waiting for an evicted iCloud file to come back
+func (a *App) waitForHydration(path string, progress *HydrateProgress) error {
+	deadline := time.Now().Add(hydrateTimeout)
+	for !exists(path) {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for %s to download", filepath.Base(path))
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	progress.Size = info.Size()
+
+	buf := make([]byte, defaultChunkSize)
+	for {
+		n, err := f.Read(buf)
+		progress.Read += int64(n)
+		runtime.EventsEmit(a.ctx, EventHydrateProgress, *progress)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
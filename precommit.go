@@ -0,0 +1,302 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// Pre-commit checks
+const (
+	PreCommitLint        = "lint"
+	PreCommitLinks       = "links"
+	PreCommitAsciidoctor = "asciidoctor"
+)
+
+// preCommitChecksSetting is the project setting holding the pipeline
+const preCommitChecksSetting = "precommit_checks"
+
+// PreCommitResult is the outcome of one check of the pipeline
+type PreCommitResult struct {
+	Check  string `json:"check"`
+	Passed bool   `json:"passed"`
+	// Skipped is set when the check could not run, e.g. asciidoctor is not
+	// installed; it does not block the commit
+	Skipped     bool         `json:"skipped,omitempty"`
+	Message     string       `json:"message,omitempty"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// PreCommitError stops GitCommit when a check of the pipeline fails
+type PreCommitError struct {
+	Message string            `json:"message"`
+	Results []PreCommitResult `json:"results"`
+}
+
+func (e *PreCommitError) Error() string { return e.Message }
+
+var asciidoctorMessageExpr = regexp.MustCompile(`^asciidoctor: (ERROR|WARNING|INFO): (?:(.+?): line (\d+): )?(.*)$`)
+
+// GetPreCommitChecks returns the checks GitCommit runs for a project, in
+// order; none unless SetPreCommitChecks was called
+func (a *App) GetPreCommitChecks(projectPath string) ([]string, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	checks := []string{}
+	v, err := db.GetProjectSetting(projectPath, preCommitChecksSetting)
+	if err != nil {
+		return nil, err
+	}
+	list, _ := v.([]interface{})
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			checks = append(checks, s)
+		}
+	}
+	return checks, nil
+}
+
+// SetPreCommitChecks sets the checks GitCommit runs before committing:
+// "lint", "links" (missing include, image and xref targets) and
+// "asciidoctor" (a conversion whose output is discarded). An empty list
+// turns the pipeline off.
+func (a *App) SetPreCommitChecks(projectPath string, checks []string) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	for _, c := range checks {
+		switch c {
+		case PreCommitLint, PreCommitLinks, PreCommitAsciidoctor:
+		default:
+			return fmt.Errorf("unknown pre-commit check %q", c)
+		}
+	}
+	return db.SetProjectSetting(projectPath, preCommitChecksSetting, checks)
+}
+
+// preCommitDoc is a document the pipeline checks, with the content to
+// check: the staged version when committing, the file on disk otherwise
+type preCommitDoc struct {
+	path    string
+	content []byte
+	err     error
+}
+
+// RunPreCommitChecks runs the project's pipeline over the given documents
+// (every document when empty), so the UI can show problems before the
+// user commits
+func (a *App) RunPreCommitChecks(projectPath string, paths []string) ([]PreCommitResult, error) {
+	checks, err := a.GetPreCommitChecks(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		if paths, err = globFiles(projectPath, "**/*.adoc"); err != nil {
+			return nil, err
+		}
+	}
+	docs := make([]preCommitDoc, 0, len(paths))
+	for _, path := range paths {
+		content, err := os.ReadFile(longPath(path))
+		docs = append(docs, preCommitDoc{path: path, content: content, err: err})
+	}
+	return a.runPreCommitChecks(projectPath, checks, docs, exists), nil
+}
+
+// preCommit runs the pipeline over the documents staged in wt, as they are
+// in the index, and returns a *PreCommitError when a check fails. Link
+// targets must be in the index too, as files left out of the commit would
+// be missing from it.
+func (a *App) preCommit(projectPath string, repo *git.Repository, wt *git.Worktree) error {
+	checks, err := a.GetPreCommitChecks(projectPath)
+	if err != nil || len(checks) == 0 {
+		return nil
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return err
+	}
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return err
+	}
+	root := wt.Filesystem.Root()
+	staged := map[string]bool{}
+	for _, e := range idx.Entries {
+		staged[filepath.Join(root, filepath.FromSlash(e.Name))] = true
+	}
+	var docs []preCommitDoc
+	for rel, fs := range status {
+		switch fs.Staging {
+		case git.Unmodified, git.Untracked, git.Deleted:
+			continue
+		}
+		if !isPage(rel) {
+			continue
+		}
+		doc := preCommitDoc{path: filepath.Join(root, filepath.FromSlash(rel))}
+		doc.content, doc.err = indexContent(repo, rel)
+		docs = append(docs, doc)
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+
+	present := func(path string) bool {
+		if !isWithin(path, root) {
+			return exists(path)
+		}
+		return staged[filepath.Clean(path)]
+	}
+	results := a.runPreCommitChecks(projectPath, checks, docs, present)
+	var failed []string
+	for _, r := range results {
+		if !r.Passed && !r.Skipped {
+			failed = append(failed, r.Check)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return &PreCommitError{
+		Message: fmt.Sprintf("pre-commit checks failed: %s", strings.Join(failed, ", ")),
+		Results: results,
+	}
+}
+
+// runPreCommitChecks runs each check over docs; present tells whether a
+// link target exists
+func (a *App) runPreCommitChecks(projectPath string, checks []string, docs []preCommitDoc, present func(string) bool) []PreCommitResult {
+	results := []PreCommitResult{}
+	for _, check := range checks {
+		results = append(results, a.runPreCommitCheck(projectPath, check, docs, present))
+	}
+	return results
+}
+
+// runPreCommitCheck runs one check. Findings are graded, suppressed and
+// baselined as in the editor; only errors fail it and warnings are
+// reported alongside.
+func (a *App) runPreCommitCheck(projectPath string, check string, docs []preCommitDoc, present func(string) bool) PreCommitResult {
+	result := PreCommitResult{Check: check, Diagnostics: []Diagnostic{}}
+	cfg, _ := LoadProjectConfig(projectPath)
+	attrs := projectAttributes(projectPath, cfg)
+	baseline := a.lintBaseline()
+	for _, doc := range docs {
+		var found []Diagnostic
+		err := doc.err
+		if err == nil {
+			switch check {
+			case PreCommitLint:
+				found = a.lintContent(doc.path, doc.content)
+			case PreCommitLinks:
+				found = missingTargets(doc.path, doc.content, attrs, present)
+			case PreCommitAsciidoctor:
+				found, err = asciidoctorDryRun(doc.path, doc.content)
+				if errors.Is(err, exec.ErrNotFound) {
+					result.Skipped = true
+					result.Message = "asciidoctor is not installed"
+					return result
+				}
+			default:
+				result.Skipped = true
+				result.Message = fmt.Sprintf("unknown check %q", check)
+				return result
+			}
+		}
+		if err != nil {
+			found = []Diagnostic{{Path: doc.path, Severity: SeverityError, Source: check, Message: err.Error()}}
+		} else {
+			for i := range found {
+				if found[i].Path == "" {
+					found[i].Path = doc.path
+				}
+				if found[i].Severity == "" {
+					found[i].Severity = SeverityWarn
+				}
+			}
+			found = a.filterDiagnosticsOf(doc.path, doc.content, found, baseline)
+		}
+		result.Diagnostics = append(result.Diagnostics, found...)
+	}
+	result.Passed = true
+	for _, d := range result.Diagnostics {
+		if d.Severity == SeverityError {
+			result.Passed = false
+		}
+	}
+	return result
+}
+
+// missingTargets reports includes, images and xrefs of content whose file
+// is not present
+func missingTargets(path string, content []byte, attrs map[string]string, present func(string) bool) []Diagnostic {
+	doc := &lintDocument{Path: path, Content: string(content), Attributes: attrs}
+	found := []Diagnostic{}
+	for _, ref := range parseReferences(doc.Content, doc.Attributes) {
+		if present(referencePath(doc, ref)) {
+			continue
+		}
+		found = append(found, Diagnostic{
+			Path:     path,
+			Line:     ref.Line,
+			Column:   ref.Column,
+			Severity: SeverityError,
+			Source:   DiagnosticSourceLinks,
+			Rule:     "missing-target",
+			Message:  fmt.Sprintf("%s target %q not found", ref.Kind, ref.Target),
+		})
+	}
+	return found
+}
+
+// asciidoctorDryRun converts content, as the document at path, without
+// keeping the output and turns the converter's messages into diagnostics
+func asciidoctorDryRun(path string, content []byte) ([]Diagnostic, error) {
+	bin, err := exec.LookPath("asciidoctor")
+	if err != nil {
+		return nil, err
+	}
+	var stderr bytes.Buffer
+	dir := filepath.Dir(path)
+	cmd := exec.Command(bin, "--safe-mode", "safe", "--base-dir", dir,
+		"--attribute", "docfile="+path, "--out-file", os.DevNull, "-")
+	cmd.Dir = dir
+	cmd.Stdin = bytes.NewReader(content)
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+
+	found := []Diagnostic{}
+	for _, line := range strings.Split(stderr.String(), "\n") {
+		m := asciidoctorMessageExpr.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		d := Diagnostic{Path: path, Severity: SeverityInfo, Source: DiagnosticSourceConverter, Message: m[4]}
+		switch m[1] {
+		case "ERROR":
+			d.Severity = SeverityError
+		case "WARNING":
+			d.Severity = SeverityWarn
+		}
+		d.Line, _ = strconv.Atoi(m[3])
+		found = append(found, d)
+	}
+	var exitErr *exec.ExitError
+	if runErr != nil && !errors.As(runErr, &exitErr) {
+		return nil, runErr
+	}
+	if exitErr != nil && len(found) == 0 {
+		return nil, fmt.Errorf("asciidoctor failed: %s", strings.TrimSpace(stderr.String()))
+	}
+	return found, nil
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"gopkg.in/yaml.v3"
+)
+
+// Kinds of FilePreview
+const (
+	PreviewMarkdown = "markdown"
+	PreviewYAML     = "yaml"
+	PreviewImage    = "image"
+	PreviewText     = "text"
+	PreviewBinary   = "binary"
+)
+
+// previewImageLimit caps the size of images inlined as data URLs
+const previewImageLimit = 10 * 1024 * 1024
+
+// FilePreview is what the UI shows for a file that isn't AsciiDoc
+type FilePreview struct {
+	Path     string `json:"path"`
+	Kind     string `json:"kind"`
+	MimeType string `json:"mimeType"`
+	Size     int64  `json:"size"`
+	// Text is the raw content of text files
+	Text string `json:"text,omitempty"`
+	// HTML is rendered Markdown
+	HTML        string                 `json:"html,omitempty"`
+	FrontMatter map[string]interface{} `json:"frontMatter,omitempty"`
+	// Issues are YAML syntax errors, in the YAML file or the front matter
+	Issues []ConfigIssue `json:"issues,omitempty"`
+	Image  *ImageInfo    `json:"image,omitempty"`
+}
+
+// ImageInfo describes an image file
+type ImageInfo struct {
+	Format  string `json:"format"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	DataURL string `json:"dataUrl,omitempty"`
+}
+
+var markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// PreviewFile returns a preview payload suited to the file type: rendered
+// Markdown with its front matter, YAML with syntax issues, image metadata
+// with a data URL, or text. Binary files get metadata only.
+func (a *App) PreviewFile(path string) (*FilePreview, error) {
+	if cloudPlaceholder(path) {
+		return nil, errNotDownloaded("preview", path)
+	}
+	data, err := os.ReadFile(longPath(path))
+	if err != nil {
+		return nil, newFileError("preview", path, err)
+	}
+
+	p := &FilePreview{
+		Path:     path,
+		MimeType: detectMimeType(path, data),
+		Size:     int64(len(data)),
+	}
+	switch ext := strings.ToLower(filepath.Ext(path)); {
+	case ext == ".md" || ext == ".markdown":
+		p.Kind = PreviewMarkdown
+		err = previewMarkdown(p, data)
+	case ext == ".yaml" || ext == ".yml":
+		p.Kind = PreviewYAML
+		p.Text = string(data)
+		p.Issues = yamlSyntaxIssues(data, 0)
+	case strings.HasPrefix(p.MimeType, "image/"):
+		p.Kind = PreviewImage
+		p.Image = previewImage(p, data)
+	case isText(data):
+		p.Kind = PreviewText
+		p.Text = string(data)
+	default:
+		p.Kind = PreviewBinary
+	}
+	return p, err
+}
+
+func previewMarkdown(p *FilePreview, data []byte) error {
+	body := data
+	if front, rest, ok := splitFrontMatter(data); ok {
+		body = rest
+		p.Issues = yamlSyntaxIssues(front, 1)
+		if len(p.Issues) == 0 {
+			_ = yaml.Unmarshal(front, &p.FrontMatter)
+			p.FrontMatter = normalizeSettings(p.FrontMatter)
+		}
+	}
+	p.Text = string(data)
+
+	var html bytes.Buffer
+	if err := markdown.Convert(body, &html); err != nil {
+		return err
+	}
+	p.HTML = html.String()
+	return nil
+}
+
+// splitFrontMatter separates a leading "---" delimited YAML block
+func splitFrontMatter(data []byte) ([]byte, []byte, bool) {
+	text := string(data)
+	if !strings.HasPrefix(text, "---\n") && !strings.HasPrefix(text, "---\r\n") {
+		return nil, data, false
+	}
+	start := strings.Index(text, "\n") + 1
+	for i := start; i < len(text); {
+		end := strings.Index(text[i:], "\n")
+		line := text[i:]
+		if end >= 0 {
+			line = text[i : i+end]
+		}
+		if strings.TrimRight(line, "\r") == "---" {
+			rest := ""
+			if end >= 0 {
+				rest = text[i+end+1:]
+			}
+			return []byte(text[start:i]), []byte(rest), true
+		}
+		if end < 0 {
+			break
+		}
+		i += end + 1
+	}
+	return nil, data, false
+}
+
+// yamlSyntaxIssues parses data and reports a syntax error, with lines
+// shifted by offset when data is embedded in a larger file
+func yamlSyntaxIssues(data []byte, offset int) []ConfigIssue {
+	var node yaml.Node
+	err := yaml.Unmarshal(data, &node)
+	if err == nil {
+		return nil
+	}
+	issue := ConfigIssue{Message: err.Error()}
+	if m := yamlErrorLineExpr.FindStringSubmatch(err.Error()); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		issue.Line = line + offset
+	}
+	return []ConfigIssue{issue}
+}
+
+func previewImage(p *FilePreview, data []byte) *ImageInfo {
+	info := &ImageInfo{Format: strings.TrimPrefix(p.MimeType, "image/")}
+	if cfg, format, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		info.Format = format
+		info.Width = cfg.Width
+		info.Height = cfg.Height
+	}
+	if len(data) <= previewImageLimit {
+		info.DataURL = fmt.Sprintf("data:%s;base64,%s", p.MimeType, base64.StdEncoding.EncodeToString(data))
+	}
+	return info
+}
+
+// isText reports whether data looks like UTF-8 text
+func isText(data []byte) bool {
+	sample := data[:min(len(data), 8000)]
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return false
+	}
+	// Don't fail on a rune cut off by the sample
+	for i := 0; i < utf8.UTFMax && len(sample) > 0 && !utf8.Valid(sample); i++ {
+		sample = sample[:len(sample)-1]
+	}
+	return utf8.Valid(sample)
+}
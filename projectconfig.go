@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectConfigDir is the per-project folder that travels with the repo
+const ProjectConfigDir = ".ndxcraft"
+
+// ProjectConfig is the team configuration stored in .ndxcraft/config.yaml.
+// Anything set here wins over the project settings stored in the database.
+type ProjectConfig struct {
+	Attributes     map[string]string `yaml:"attributes" json:"attributes"`
+	Lint           LintConfig        `yaml:"lint" json:"lint"`
+	ExportPresets  []ExportPreset    `yaml:"exportPresets" json:"exportPresets"`
+	PublishTargets []PublishTarget   `yaml:"publishTargets" json:"publishTargets"`
+	Glossary       string            `yaml:"glossary" json:"glossary"`
+	StyleProfile   *StyleProfile     `yaml:"styleProfile" json:"styleProfile"`
+	// InclusiveLanguage customizes the wordlist of the inclusive-language
+	// lint rule
+	InclusiveLanguage *InclusiveLanguageConfig `yaml:"inclusiveLanguage" json:"inclusiveLanguage"`
+	ContentSources    []ContentSource          `yaml:"contentSources" json:"contentSources"`
+	Settings          map[string]interface{}   `yaml:"settings" json:"settings"`
+}
+
+// LintConfig enables, disables or re-grades diagnostics. Rules maps a rule
+// id, or a whole source such as "vale", to error, warn, info or ignore.
+type LintConfig struct {
+	Rules    map[string]string `yaml:"rules" json:"rules"`
+	Length   LengthLimits      `yaml:"length" json:"length"`
+	Acronyms AcronymConfig     `yaml:"acronyms" json:"acronyms"`
+}
+
+// LengthLimits are the word budgets checked by the length rules. Zero
+// turns a limit off.
+type LengthLimits struct {
+	MaxPageWords    int `yaml:"maxPageWords" json:"maxPageWords"`
+	MaxSectionWords int `yaml:"maxSectionWords" json:"maxSectionWords"`
+	// MinIntroWords is the prose a section needs before its first
+	// subheading
+	MinIntroWords int `yaml:"minIntroWords" json:"minIntroWords"`
+}
+
+// ExportPreset is a named export configuration
+type ExportPreset struct {
+	Name       string            `yaml:"name" json:"name"`
+	Format     string            `yaml:"format" json:"format"`
+	Output     string            `yaml:"output" json:"output"`
+	Attributes map[string]string `yaml:"attributes" json:"attributes"`
+	// Audience is public (the default) or internal; see PrepareExportSource
+	Audience string `yaml:"audience" json:"audience"`
+	// Redirects is the ExportRedirects format written into the output of
+	// html presets: "html" (the default), "netlify", "all" or "none"
+	Redirects string `yaml:"redirects" json:"redirects"`
+}
+
+type cachedConfig struct {
+	modTime time.Time
+	config  *ProjectConfig
+}
+
+var (
+	configCacheMu sync.Mutex
+	configCache   = make(map[string]cachedConfig)
+)
+
+// projectConfigPath returns where the config file for a project root lives
+func projectConfigPath(root string) string {
+	return filepath.Join(root, ProjectConfigDir, "config.yaml")
+}
+
+// LoadProjectConfig reads .ndxcraft/config.yaml from the project root.
+// It returns nil without an error when the project has no config file.
+func LoadProjectConfig(root string) (*ProjectConfig, error) {
+	if root == "" {
+		return nil, nil
+	}
+	path := projectConfigPath(root)
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	configCacheMu.Lock()
+	cached, ok := configCache[path]
+	configCacheMu.Unlock()
+	if ok && cached.modTime.Equal(info.ModTime()) {
+		return cached.config, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg ProjectConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	cfg.Settings = normalizeSettings(cfg.Settings)
+
+	configCacheMu.Lock()
+	configCache[path] = cachedConfig{modTime: info.ModTime(), config: &cfg}
+	configCacheMu.Unlock()
+	return &cfg, nil
+}
+
+// normalizeSettings round-trips YAML values through JSON so they have the
+// same shapes as DB-stored settings (numbers as float64, string-keyed maps)
+func normalizeSettings(settings map[string]interface{}) map[string]interface{} {
+	if len(settings) == 0 {
+		return settings
+	}
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return settings
+	}
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return settings
+	}
+	return normalized
+}
+
+// GetProjectConfig returns the committed config of a project, or nil
+func (a *App) GetProjectConfig(projectPath string) (*ProjectConfig, error) {
+	return LoadProjectConfig(projectPath)
+}
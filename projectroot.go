@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Events emitted when the open project's root disappears or comes back
+const (
+	EventProjectUnavailable = "project:unavailable"
+	EventProjectAvailable   = "project:available"
+)
+
+// Reasons in ProjectStatus
+const (
+	ProjectMissing      = "missing"
+	ProjectDisconnected = "disconnected"
+	ProjectNotFolder    = "notFolder"
+	ProjectUnreachable  = "unreachable"
+)
+
+const (
+	projectCheckEvery = 15 * time.Second
+	// projectStatTimeout bounds a stat of a dead network share, which can
+	// otherwise hang for minutes
+	projectStatTimeout = 5 * time.Second
+)
+
+// ProjectStatus is the payload of the project availability events
+type ProjectStatus struct {
+	Path      string `json:"path"`
+	Available bool   `json:"available"`
+	Reason    string `json:"reason,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// CheckProjectRoot reports whether a project folder can be reached
+func (a *App) CheckProjectRoot(path string) ProjectStatus {
+	return projectRootStatus(path)
+}
+
+func projectRootStatus(path string) ProjectStatus {
+	status := ProjectStatus{Path: path}
+
+	type result struct {
+		info os.FileInfo
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		info, err := os.Stat(longPath(path))
+		done <- result{info, err}
+	}()
+
+	select {
+	case r := <-done:
+		switch {
+		case r.err == nil && r.info.IsDir():
+			status.Available = true
+		case r.err == nil:
+			status.Reason = ProjectNotFolder
+			status.Message = path + " is not a folder"
+		case os.IsNotExist(r.err) && !volumeExists(path):
+			status.Reason = ProjectDisconnected
+			status.Message = "The drive holding " + path + " is not connected"
+		case os.IsNotExist(r.err):
+			status.Reason = ProjectMissing
+			status.Message = path + " was moved or deleted"
+		default:
+			status.Reason = ProjectUnreachable
+			status.Message = r.err.Error()
+		}
+	case <-time.After(projectStatTimeout):
+		status.Reason = ProjectUnreachable
+		status.Message = "Timed out reaching " + path
+	}
+	return status
+}
+
+// volumeExists reports whether the drive or share holding path is there.
+// Off Windows, an unmounted share leaves an empty mount point, so the
+// nearest existing ancestor being empty counts as disconnected.
+func volumeExists(path string) bool {
+	if volume := filepath.VolumeName(path); volume != "" {
+		_, err := os.Stat(volume + string(filepath.Separator))
+		return err == nil
+	}
+	for dir := filepath.Dir(path); dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		if entries, err := os.ReadDir(dir); err == nil {
+			return len(entries) > 0
+		}
+	}
+	return true
+}
+
+// watchProjectRoot checks the open project's root at startup and then
+// periodically, emitting EventProjectUnavailable when it goes away and
+// EventProjectAvailable when it returns
+func (a *App) watchProjectRoot(ctx context.Context) {
+	ticker := time.NewTicker(projectCheckEvery)
+	defer ticker.Stop()
+
+	lastRoot, lastAvailable := "", true
+	for {
+		if root := a.currentProjectRoot(); root != "" {
+			status := projectRootStatus(root)
+			if root != lastRoot || status.Available != lastAvailable {
+				if !status.Available {
+					runtime.EventsEmit(ctx, EventProjectUnavailable, status)
+				} else if root == lastRoot {
+					runtime.EventsEmit(ctx, EventProjectAvailable, status)
+				}
+			}
+			lastRoot, lastAvailable = root, status.Available
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelocateProject points a project at its new location: the projects
+// list, project settings, shadow files and remembered paths are rewritten,
+// and the open project follows if it was the one moved
+func (a *App) RelocateProject(oldPath string, newPath string) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	if status := projectRootStatus(newPath); !status.Available {
+		return fmt.Errorf("cannot relocate to %s: %s", newPath, status.Message)
+	}
+	oldPath, newPath = filepath.Clean(oldPath), filepath.Clean(newPath)
+
+	if err := db.RelocateProject(oldPath, newPath); err != nil {
+		return err
+	}
+	rootRaw, _ := db.GetPreference("projectRoot")
+	if root, _ := rootRaw.(string); root != "" && filepath.Clean(root) == oldPath {
+		if err := db.SetPreference("projectRoot", newPath); err != nil {
+			return err
+		}
+		if a.ctx != nil {
+			runtime.EventsEmit(a.ctx, EventProjectOpened, newPath)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// PublishTarget is a deploy destination declared in the project config.
+// Options may reference ${ENV_VAR} or ${secret:name}; references are only
+// resolved in the backend when the target is used, and only once the user
+// has approved the host the target's URL names. The URL itself can't hold
+// references, so a cloned repository can't send values anywhere else.
+type PublishTarget struct {
+	Name    string            `yaml:"name" json:"name"`
+	Type    string            `yaml:"type" json:"type"`
+	URL     string            `yaml:"url" json:"url"`
+	Options map[string]string `yaml:"options" json:"options"`
+}
+
+// PublishTargetTest is the outcome of TestPublishTarget. Resolved values
+// stay in the backend; only the names of missing references are reported.
+type PublishTargetTest struct {
+	Name string `json:"name"`
+	OK   bool   `json:"ok"`
+	// Missing lists the ${...} references with no value
+	Missing []string `json:"missing,omitempty"`
+	// NeedsApproval is set when the target references values but the
+	// user hasn't approved Host with ApprovePublishTarget
+	NeedsApproval bool   `json:"needsApproval,omitempty"`
+	Host          string `json:"host,omitempty"`
+	// Status is the HTTP status of the target URL, when it has one
+	Status int    `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+var interpolationExpr = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+var publishHTTPClient = &http.Client{Timeout: 15 * time.Second}
+
+// GetPublishTargets lists a project's publishing targets with references
+// left unresolved, so secrets are never sent to the frontend
+func (a *App) GetPublishTargets(projectPath string) ([]PublishTarget, error) {
+	cfg, err := LoadProjectConfig(projectPath)
+	if err != nil || cfg == nil {
+		return []PublishTarget{}, err
+	}
+	return cfg.PublishTargets, nil
+}
+
+// TestPublishTarget resolves a target's references and, for http(s) URLs,
+// checks that the server answers, so a missing variable or secret shows up
+// before a deploy
+func (a *App) TestPublishTarget(projectPath string, name string) (*PublishTargetTest, error) {
+	result := &PublishTargetTest{Name: name}
+	target, err := resolvePublishTarget(projectPath, name)
+	if err != nil {
+		var refs *unresolvedRefsError
+		var approval *unapprovedTargetError
+		switch {
+		case errors.As(err, &refs):
+			result.Missing = refs.missing
+		case errors.As(err, &approval):
+			result.NeedsApproval, result.Host = true, approval.host
+		default:
+			return nil, err
+		}
+		result.Error = err.Error()
+		return result, nil
+	}
+	if !strings.HasPrefix(target.URL, "http://") && !strings.HasPrefix(target.URL, "https://") {
+		result.OK = true
+		return result, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishHTTPClient.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, target.URL, nil)
+	if err != nil {
+		result.Error = fmt.Sprintf("invalid URL in target %q", name)
+		return result, nil
+	}
+	resp, err := publishHTTPClient.Do(req)
+	if err != nil {
+		result.Error = fmt.Sprintf("%s did not answer", req.URL.Host)
+		return result, nil
+	}
+	resp.Body.Close()
+	result.Status = resp.StatusCode
+	result.OK = resp.StatusCode < 500
+	if !result.OK {
+		result.Error = fmt.Sprintf("%s answered %s", req.URL.Host, resp.Status)
+	}
+	return result, nil
+}
+
+// unresolvedRefsError lists the references resolvePublishTarget could not
+// resolve
+type unresolvedRefsError struct {
+	target  string
+	missing []string
+}
+
+func (e *unresolvedRefsError) Error() string {
+	return fmt.Sprintf("unresolved references in target %q: %s", e.target, strings.Join(e.missing, ", "))
+}
+
+// unapprovedTargetError means a target references values but the user
+// hasn't approved the host they would go to
+type unapprovedTargetError struct {
+	target string
+	host   string
+}
+
+func (e *unapprovedTargetError) Error() string {
+	return fmt.Sprintf("approve sending variables and secrets to %s before using target %q", e.host, e.target)
+}
+
+// publishApprovalKey is the project setting holding the host the user let
+// a target send resolved values to
+func publishApprovalKey(name string) string {
+	return "publish_approved:" + name
+}
+
+// publishTargetHost is what approvals are given for: the host of the
+// target's URL, or the whole URL when it has none
+func publishTargetHost(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		return strings.ToLower(u.Host)
+	}
+	return rawURL
+}
+
+// ApprovePublishTarget lets a target resolve the ${...} references of its
+// options for the host its URL names now. The approval is kept in the
+// user's project settings and lapses when the URL moves to another host.
+func (a *App) ApprovePublishTarget(projectPath string, name string) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	t, err := findPublishTarget(projectPath, name)
+	if err != nil {
+		return err
+	}
+	if interpolationExpr.MatchString(t.URL) {
+		return fmt.Errorf("the URL of target %q must not reference variables or secrets; use options for credentials", name)
+	}
+	return db.SetProjectSetting(projectPath, publishApprovalKey(name), publishTargetHost(t.URL))
+}
+
+// findPublishTarget returns the named target from the project config
+func findPublishTarget(projectPath, name string) (*PublishTarget, error) {
+	cfg, err := LoadProjectConfig(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return nil, fmt.Errorf("project has no config file")
+	}
+	for i := range cfg.PublishTargets {
+		if cfg.PublishTargets[i].Name == name {
+			return &cfg.PublishTargets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("publish target %q not found", name)
+}
+
+// resolvePublishTarget returns the named target with every ${...}
+// reference in its options replaced by its environment or keychain value
+func resolvePublishTarget(projectPath, name string) (*PublishTarget, error) {
+	t, err := findPublishTarget(projectPath, name)
+	if err != nil {
+		return nil, err
+	}
+	if interpolationExpr.MatchString(t.URL) {
+		return nil, fmt.Errorf("the URL of target %q must not reference variables or secrets; use options for credentials", name)
+	}
+
+	resolved := PublishTarget{Name: t.Name, Type: t.Type, URL: t.URL, Options: make(map[string]string, len(t.Options))}
+	referenced := false
+	for _, v := range t.Options {
+		referenced = referenced || interpolationExpr.MatchString(v)
+	}
+	if referenced {
+		host := publishTargetHost(t.URL)
+		var approved interface{}
+		if db != nil {
+			approved, _ = db.GetProjectSetting(projectPath, publishApprovalKey(name))
+		}
+		if approved != host {
+			return nil, &unapprovedTargetError{target: name, host: host}
+		}
+	}
+
+	var missing []string
+	for k, v := range t.Options {
+		resolved.Options[k] = interpolate(v, &missing)
+	}
+	if len(missing) > 0 {
+		return nil, &unresolvedRefsError{target: name, missing: missing}
+	}
+	return &resolved, nil
+}
+
+// interpolate expands ${ENV_VAR} and ${secret:name}, collecting the
+// references that could not be resolved
+func interpolate(s string, missing *[]string) string {
+	return interpolationExpr.ReplaceAllStringFunc(s, func(match string) string {
+		ref := interpolationExpr.FindStringSubmatch(match)[1]
+
+		if name, ok := strings.CutPrefix(ref, "secret:"); ok {
+			value, err := getSecret(name)
+			if err != nil {
+				*missing = append(*missing, ref)
+				return match
+			}
+			return value
+		}
+
+		value, ok := os.LookupEnv(ref)
+		if !ok {
+			*missing = append(*missing, ref)
+			return match
+		}
+		return value
+	})
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// pullRequestUpstream is the remote of the repository a fork proposes
+// changes to
+const pullRequestUpstream = "upstream"
+
+// hostingHTTPClient talks to the GitHub and GitLab APIs
+var hostingHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// PullRequest is a pull (or GitLab merge) request created by
+// CreatePullRequest
+type PullRequest struct {
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+	Head   string `json:"head"`
+	Base   string `json:"base"`
+}
+
+// CreatePullRequest opens a pull request on GitHub, or a merge request on
+// GitLab, from the current branch into baseBranch (the repository's
+// default branch when empty), then opens it in the browser. It uses the
+// token GitPush stores for the host, so the branch should be pushed first.
+// When an "upstream" remote is configured the request goes there, from
+// the fork the branch is pushed to.
+func (a *App) CreatePullRequest(projectPath string, title string, body string, baseBranch string) (*PullRequest, error) {
+	if strings.TrimSpace(title) == "" {
+		return nil, fmt.Errorf("pull request title must not be empty")
+	}
+	repo, _, err := openRepo(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	branch, headRemote, merge, err := trackedBranch(repo)
+	if err != nil {
+		return nil, err
+	}
+	headInfo, err := a.remoteInfo(projectPath, headRemote)
+	if err != nil {
+		return nil, err
+	}
+	info := headInfo
+	if _, err := repo.Remote(pullRequestUpstream); err == nil && headRemote != pullRequestUpstream {
+		if info, err = a.remoteInfo(projectPath, pullRequestUpstream); err != nil {
+			return nil, err
+		}
+		if info.Host != headInfo.Host {
+			return nil, fmt.Errorf("the %s remote is on %s but your branch is pushed to %s", pullRequestUpstream, info.Host, headInfo.Host)
+		}
+	}
+	token, err := getSecret(gitTokenSecret(info.Host))
+	if err != nil {
+		return nil, fmt.Errorf("store an access token for %s to create pull requests", info.Host)
+	}
+
+	api := &hostingAPI{info: info, token: token}
+	ctx := context.Background()
+	if baseBranch == "" {
+		if baseBranch, err = api.defaultBranch(ctx); err != nil {
+			return nil, err
+		}
+	}
+	fork := !strings.EqualFold(headInfo.Owner+"/"+headInfo.Repo, info.Owner+"/"+info.Repo)
+	pr := &PullRequest{Head: merge.Short(), Base: baseBranch}
+	if !fork && pr.Head == pr.Base {
+		return nil, fmt.Errorf("%s is the base branch; create a branch for your changes first", branch.Short())
+	}
+
+	switch info.Provider {
+	case ProviderGitHub:
+		if fork {
+			pr.Head = headInfo.Owner + ":" + pr.Head
+		}
+		var created struct {
+			Number  int    `json:"number"`
+			HTMLURL string `json:"html_url"`
+		}
+		err = api.do(ctx, http.MethodPost, "/pulls", map[string]string{
+			"title": title, "body": body, "head": pr.Head, "base": pr.Base,
+		}, &created)
+		pr.Number, pr.URL = created.Number, created.HTMLURL
+	case ProviderGitLab:
+		// Merge requests from a fork are created on the fork and name
+		// the project they target
+		request := map[string]interface{}{
+			"title": title, "description": body, "source_branch": pr.Head, "target_branch": pr.Base,
+		}
+		if fork {
+			var target struct {
+				ID int `json:"id"`
+			}
+			if err := api.do(ctx, http.MethodGet, "", nil, &target); err != nil {
+				return nil, err
+			}
+			request["target_project_id"] = target.ID
+			api = &hostingAPI{info: headInfo, token: token}
+			pr.Head = headInfo.Owner + ":" + pr.Head
+		}
+		var created struct {
+			IID    int    `json:"iid"`
+			WebURL string `json:"web_url"`
+		}
+		err = api.do(ctx, http.MethodPost, "/merge_requests", request, &created)
+		pr.Number, pr.URL = created.IID, created.WebURL
+	default:
+		return nil, fmt.Errorf("pull requests are supported on GitHub and GitLab, not %s", info.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if a.ctx != nil && pr.URL != "" {
+		runtime.BrowserOpenURL(a.ctx, pr.URL)
+	}
+	return pr, nil
+}
+
+// hostingAPI calls the REST API of the repository's host
+type hostingAPI struct {
+	info  *gitRemoteInfo
+	token string
+}
+
+// repoURL is the API URL of the repository, which path is appended to
+func (h *hostingAPI) repoURL(path string) string {
+	switch h.info.Provider {
+	case ProviderGitHub:
+		base := "https://api.github.com"
+		if h.info.Host != "github.com" {
+			base = "https://" + h.info.Host + "/api/v3"
+		}
+		return base + "/repos/" + h.info.Owner + "/" + h.info.Repo + path
+	case ProviderGitLab:
+		return "https://" + h.info.Host + "/api/v4/projects/" + url.PathEscape(h.info.Owner+"/"+h.info.Repo) + path
+	}
+	return ""
+}
+
+func (h *hostingAPI) defaultBranch(ctx context.Context) (string, error) {
+	var repo struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := h.do(ctx, http.MethodGet, "", nil, &repo); err != nil {
+		return "", err
+	}
+	if repo.DefaultBranch == "" {
+		return "", fmt.Errorf("%s/%s has no default branch", h.info.Owner, h.info.Repo)
+	}
+	return repo.DefaultBranch, nil
+}
+
+// do sends a JSON request and decodes the response into out. Error
+// responses are turned into the API's own message.
+func (h *hostingAPI) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, h.repoURL(path), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if h.info.Provider == ProviderGitLab {
+		req.Header.Set("PRIVATE-TOKEN", h.token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+h.token)
+	}
+
+	resp, err := hostingHTTPClient.Do(req)
+	if err != nil {
+		return &GitError{Op: "pull request", Code: GitErrNetwork, Message: err.Error(), err: err,
+			Hint: "Check your network connection"}
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return hostingError(resp.StatusCode, data)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+// hostingError explains a failed API call using the message the host
+// sent, e.g. GitHub's "No commits between main and docs"
+func hostingError(status int, data []byte) error {
+	var msg struct {
+		Message interface{} `json:"message"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	_ = json.Unmarshal(data, &msg)
+	// GitLab sends lists or maps of messages as well as strings
+	text := ""
+	switch m := msg.Message.(type) {
+	case string:
+		text = m
+	case nil:
+	default:
+		b, _ := json.Marshal(m)
+		text = string(b)
+	}
+	if text == "" {
+		text = http.StatusText(status)
+	}
+	for _, e := range msg.Errors {
+		if e.Message != "" {
+			text += ": " + e.Message
+		}
+	}
+	e := &GitError{Op: "pull request", Code: GitErrOther, Message: text}
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		e.Code = GitErrAuth
+		e.Hint = "Check that the stored token may create pull requests"
+	case http.StatusNotFound:
+		e.Code = GitErrNoRemote
+		e.Hint = "Check the remote URL, or that the token can see the repository"
+	case http.StatusUnprocessableEntity, http.StatusConflict:
+		e.Hint = "Push your branch first, and check that it differs from the base branch"
+	}
+	return e
+}
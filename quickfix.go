@@ -0,0 +1,328 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TextEdit replaces the text between two positions. Lines and columns are
+// 1-based and columns count bytes, as in Diagnostic; an insertion has
+// the same start and end.
+type TextEdit struct {
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	EndLine   int    `json:"endLine"`
+	EndColumn int    `json:"endColumn"`
+	NewText   string `json:"newText"`
+}
+
+// QuickFix is an edit that resolves a diagnostic
+type QuickFix struct {
+	ID           string     `json:"id"`
+	DiagnosticID string     `json:"diagnosticId"`
+	Title        string     `json:"title"`
+	Edits        []TextEdit `json:"edits"`
+}
+
+// byteEdit is a TextEdit as offsets into the file
+type byteEdit struct {
+	start, end int
+	text       string
+}
+
+// proposedFix is what a quickFixer suggests
+type proposedFix struct {
+	title string
+	edits []byteEdit
+}
+
+// pendingFix is a computed QuickFix waiting to be applied, tied to the
+// file content it was computed from
+type pendingFix struct {
+	fix   QuickFix
+	path  string
+	hash  [sha256.Size]byte
+	edits []byteEdit
+}
+
+// quickFixer proposes fixes for a lint finding. line is the text of the
+// diagnostic's line and lineStart its offset in content.
+type quickFixer func(content string, lineStart int, line string, d Diagnostic) []proposedFix
+
+// quickFixers by lint rule id
+var quickFixers = map[string]quickFixer{
+	"image-alt-text":       fixImageAltText,
+	"bare-url":             fixBareURL,
+	"unbalanced-delimiter": fixUnbalancedDelimiter,
+	"undefined-attribute":  fixUndefinedAttribute,
+}
+
+var lintURLExpr = regexp.MustCompile(`^https?://[^\s\[\]<>]+`)
+
+// GetQuickFixes computes the fixes available for a diagnostic reported by
+// LintFile, against the file as it is on disk
+func (a *App) GetQuickFixes(diagnosticID string) ([]QuickFix, error) {
+	d, ok := a.findDiagnostic(diagnosticID)
+	if !ok {
+		return nil, fmt.Errorf("diagnostic %s not found; lint the file again", diagnosticID)
+	}
+	fixes := []QuickFix{}
+	fixer := a.quickFixerFor(d.Rule)
+	if d.Source != DiagnosticSourceLint || fixer == nil {
+		return fixes, nil
+	}
+	data, err := os.ReadFile(longPath(d.Path))
+	if err != nil {
+		return nil, newFileError("read", d.Path, err)
+	}
+	content := string(data)
+	lineStart, line, ok := lineAt(content, d.Line)
+	if !ok {
+		return fixes, nil
+	}
+
+	hash := sha256.Sum256(data)
+	a.diagnosticsMu.Lock()
+	defer a.diagnosticsMu.Unlock()
+	for i, p := range fixer(content, lineStart, line, d) {
+		fix := QuickFix{ID: fmt.Sprintf("%s-%d", d.ID, i), DiagnosticID: d.ID, Title: p.title, Edits: []TextEdit{}}
+		for _, e := range p.edits {
+			l, c := positionAt(content, e.start)
+			el, ec := positionAt(content, e.end)
+			fix.Edits = append(fix.Edits, TextEdit{Line: l, Column: c, EndLine: el, EndColumn: ec, NewText: e.text})
+		}
+		a.quickFixes[fix.ID] = &pendingFix{fix: fix, path: d.Path, hash: hash, edits: p.edits}
+		fixes = append(fixes, fix)
+	}
+	return fixes, nil
+}
+
+// ApplyQuickFix applies a fix from GetQuickFixes to the file on disk, as
+// an operation that can be undone, re-lints the file and returns its new
+// content for the editor. It refuses when the file changed in between.
+func (a *App) ApplyQuickFix(id string) (string, error) {
+	a.diagnosticsMu.Lock()
+	pending, ok := a.quickFixes[id]
+	a.diagnosticsMu.Unlock()
+	if !ok {
+		return "", fmt.Errorf("quick fix %s not found", id)
+	}
+	data, err := os.ReadFile(longPath(pending.path))
+	if err != nil {
+		return "", newFileError("read", pending.path, err)
+	}
+	if sha256.Sum256(data) != pending.hash {
+		return "", fmt.Errorf("%s changed since the fix was suggested; lint it again", pending.path)
+	}
+
+	edits := append([]byteEdit{}, pending.edits...)
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	content := string(data)
+	for _, e := range edits {
+		content = content[:e.start] + e.text + content[e.end:]
+	}
+
+	op := a.beginOperation("Quick fix: " + pending.fix.Title)
+	if err := op.replacing(pending.path); err != nil {
+		op.discard()
+		return "", err
+	}
+	if err := writeFileAtomic(pending.path, []byte(content), 0644); err != nil {
+		op.discard()
+		return "", newFileError("save", pending.path, err)
+	}
+	a.commitOperation(op)
+	a.rememberStamp(pending.path, []byte(content))
+
+	a.diagnosticsMu.Lock()
+	for key, p := range a.quickFixes {
+		if p.path == pending.path {
+			delete(a.quickFixes, key)
+		}
+	}
+	a.diagnosticsMu.Unlock()
+	_, _ = a.LintFile(pending.path)
+	return content, nil
+}
+
+// quickFixerFor returns the fixer of a lint rule. Some fixers depend on
+// the project's wordlist or glossary.
+func (a *App) quickFixerFor(rule string) quickFixer {
+	switch rule {
+	case "inclusive-language":
+		cfg, _ := LoadProjectConfig(a.currentProjectRoot())
+		return fixInclusiveLanguage(inclusiveTerms(cfg))
+	case "acronym-expansion":
+		root := a.currentProjectRoot()
+		cfg, _ := LoadProjectConfig(root)
+		return fixAcronymExpansion(loadAcronymRules(root, cfg))
+	}
+	return quickFixers[rule]
+}
+
+// findDiagnostic looks a stored diagnostic up by id
+func (a *App) findDiagnostic(id string) (Diagnostic, bool) {
+	a.diagnosticsMu.Lock()
+	defer a.diagnosticsMu.Unlock()
+	for _, list := range a.diagnostics {
+		for _, d := range list {
+			if d.ID == id {
+				return d, true
+			}
+		}
+	}
+	return Diagnostic{}, false
+}
+
+// lineAt returns the offset and text (without line break) of line n
+func lineAt(content string, n int) (int, string, bool) {
+	start := 0
+	for i := 1; i < n; i++ {
+		next := strings.IndexByte(content[start:], '\n')
+		if next < 0 {
+			return 0, "", false
+		}
+		start += next + 1
+	}
+	line := content[start:]
+	if end := strings.IndexByte(line, '\n'); end >= 0 {
+		line = line[:end]
+	}
+	return start, strings.TrimRight(line, "\r"), true
+}
+
+// positionAt turns an offset into a 1-based line and byte column
+func positionAt(content string, offset int) (int, int) {
+	before := content[:offset]
+	line := strings.Count(before, "\n") + 1
+	return line, offset - (strings.LastIndexByte(before, '\n') + 1) + 1
+}
+
+func fixImageAltText(content string, lineStart int, line string, d Diagnostic) []proposedFix {
+	col := d.Column - 1
+	if col < 0 || col > len(line) {
+		return nil
+	}
+	m := lintImageExpr.FindStringSubmatchIndex(line[col:])
+	if m == nil || m[0] != 0 {
+		return nil
+	}
+	alt := altTextFromFile(line[col+m[2] : col+m[3]])
+	attrs := line[col+m[4] : col+m[5]]
+	at := lineStart + col + m[4]
+	edit := byteEdit{start: at, end: at, text: alt + ", "}
+	switch {
+	case strings.TrimSpace(attrs) == "":
+		edit = byteEdit{start: at, end: at + len(attrs), text: alt}
+	case strings.HasPrefix(strings.TrimSpace(attrs), ","):
+		edit.text = alt
+	}
+	return []proposedFix{{title: fmt.Sprintf("Add alt text %q", alt), edits: []byteEdit{edit}}}
+}
+
+func fixBareURL(content string, lineStart int, line string, d Diagnostic) []proposedFix {
+	col := d.Column - 1
+	if col < 0 || col > len(line) {
+		return nil
+	}
+	match := lintURLExpr.FindString(line[col:])
+	if match == "" {
+		return nil
+	}
+	end := bareURLEnd(line, col, col+len(match))
+	u, err := url.Parse(line[col:end])
+	if err != nil || u.Host == "" {
+		return nil
+	}
+	text := strings.TrimPrefix(u.Hostname(), "www.")
+	at := lineStart + end
+	return []proposedFix{{title: fmt.Sprintf("Add link text %q", text), edits: []byteEdit{{start: at, end: at, text: "[" + text + "]"}}}}
+}
+
+func fixUnbalancedDelimiter(content string, lineStart int, line string, d Diagnostic) []proposedFix {
+	delimiter := strings.TrimSpace(line)
+	if strings.HasPrefix(delimiter, "```") {
+		delimiter = "```"
+	}
+	if !isBlockDelimiter(delimiter) {
+		return nil
+	}
+	// Close before the next heading, which most likely wasn't meant to be
+	// part of the block, or else at the end
+	for n := d.Line + 1; ; n++ {
+		start, text, ok := lineAt(content, n)
+		if !ok {
+			break
+		}
+		if adocHeadingExpr.MatchString(text) {
+			return []proposedFix{{
+				title: fmt.Sprintf("Close the %s block before %q", delimiter, strings.TrimSpace(text)),
+				edits: []byteEdit{{start: start, end: start, text: delimiter + "\n\n"}},
+			}}
+		}
+	}
+	text := delimiter + "\n"
+	if !strings.HasSuffix(content, "\n") {
+		text = "\n" + text
+	}
+	return []proposedFix{{
+		title: fmt.Sprintf("Close the %s block at the end of the document", delimiter),
+		edits: []byteEdit{{start: len(content), end: len(content), text: text}},
+	}}
+}
+
+func fixUndefinedAttribute(content string, lineStart int, line string, d Diagnostic) []proposedFix {
+	col := d.Column - 1
+	if col < 0 || col > len(line) {
+		return nil
+	}
+	m := lintAttrRefExpr.FindStringSubmatch(line[col:])
+	if m == nil || !strings.HasPrefix(line[col:], m[0]) {
+		return nil
+	}
+	name := m[2]
+	header := headerEnd(content)
+	entry := ":" + name + ":\n"
+	if header > 0 && content[header-1] != '\n' {
+		entry = "\n" + entry
+	}
+	return []proposedFix{
+		{
+			title: fmt.Sprintf("Define :%s: in the document header", name),
+			edits: []byteEdit{{start: header, end: header, text: entry}},
+		},
+		{
+			title: fmt.Sprintf(`Escape as \{%s}`, name),
+			edits: []byteEdit{{start: lineStart + col, end: lineStart + col, text: `\`}},
+		},
+	}
+}
+
+// headerEnd returns the offset where a new attribute entry goes: the end
+// of the document header, or the start of a document without a title
+func headerEnd(content string) int {
+	offset := 0
+	inHeader := false
+	for _, line := range strings.SplitAfter(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case inHeader && trimmed == "":
+			return offset
+		case !inHeader && (trimmed == "" || strings.HasPrefix(trimmed, "//")):
+		case !inHeader && strings.HasPrefix(trimmed, "= "):
+			inHeader = true
+		case !inHeader:
+			return 0
+		}
+		offset += len(line)
+	}
+	if !inHeader {
+		return 0
+	}
+	return offset
+}
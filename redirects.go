@@ -0,0 +1,331 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Redirect output formats for ExportRedirects
+const (
+	RedirectsMetaRefresh = "html"
+	RedirectsNetlify     = "netlify"
+)
+
+// netlifyRedirectsMarker brackets the lines ExportRedirects owns in a
+// _redirects file, so rules written by hand survive
+const netlifyRedirectsMarker = "# ndxCraft redirects"
+
+// Redirect sends readers of a page that was renamed, moved or merged to
+// where its content is now. Paths are relative to the project root.
+type Redirect struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Added string `json:"added"`
+}
+
+// redirectsFile is the manifest in .ndxcraft, shared with the team
+type redirectsFile struct {
+	Redirects []Redirect `json:"redirects"`
+}
+
+// RedirectExport lists what ExportRedirects wrote
+type RedirectExport struct {
+	Files []string `json:"files"`
+	// Skipped are old paths where the output already has a page
+	Skipped []string `json:"skipped"`
+}
+
+// redirectsPath returns where a project's redirects are kept
+func redirectsPath(root string) string {
+	return filepath.Join(root, ProjectConfigDir, "redirects.json")
+}
+
+// GetRedirects lists the project's redirects by old path
+func (a *App) GetRedirects(projectPath string) ([]Redirect, error) {
+	return loadRedirects(projectPath)
+}
+
+// AddRedirect records that the page at from now lives at to, for pages
+// merged into another one. Renames and moves are recorded by themselves.
+func (a *App) AddRedirect(projectPath string, from string, to string) ([]Redirect, error) {
+	fromRel, err := projectRelPath(projectPath, from)
+	if err != nil {
+		return nil, err
+	}
+	toRel, err := projectRelPath(projectPath, to)
+	if err != nil {
+		return nil, err
+	}
+	if !exists(to) {
+		return nil, fmt.Errorf("%s does not exist", to)
+	}
+	if fromRel == toRel {
+		return nil, fmt.Errorf("a page can't redirect to itself")
+	}
+	list, err := loadRedirects(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	list = addRedirect(list, fromRel, toRel)
+	return list, saveRedirects(projectPath, list)
+}
+
+// RemoveRedirect drops the redirect from an old path
+func (a *App) RemoveRedirect(projectPath string, from string) ([]Redirect, error) {
+	fromRel, err := projectRelPath(projectPath, from)
+	if err != nil {
+		return nil, err
+	}
+	list, err := loadRedirects(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	kept := list[:0]
+	for _, r := range list {
+		if r.From != fromRel {
+			kept = append(kept, r)
+		}
+	}
+	return kept, saveRedirects(projectPath, kept)
+}
+
+// ExportRedirects writes the project's redirects into an export's output
+// folder: a meta-refresh page at each old URL, or a Netlify _redirects
+// file. An empty format writes both.
+func (a *App) ExportRedirects(projectPath string, outputDir string, format string) (*RedirectExport, error) {
+	if format != "" && format != RedirectsMetaRefresh && format != RedirectsNetlify {
+		return nil, fmt.Errorf("unknown redirect format %q", format)
+	}
+	list, err := loadRedirects(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	result := &RedirectExport{Files: []string{}, Skipped: []string{}}
+	if format == "" || format == RedirectsMetaRefresh {
+		for _, r := range list {
+			from, to := pageURL(r.From), pageURL(r.To)
+			target := filepath.Join(outputDir, filepath.FromSlash(from))
+			if !isWithin(target, outputDir) {
+				return nil, fmt.Errorf("redirect from %s leaves the output folder", r.From)
+			}
+			if data, err := os.ReadFile(longPath(target)); err == nil && !strings.Contains(string(data), `http-equiv="refresh"`) {
+				result.Skipped = append(result.Skipped, from)
+				continue
+			}
+			rel, err := filepath.Rel(filepath.Dir(target), filepath.Join(outputDir, filepath.FromSlash(to)))
+			if err != nil {
+				return nil, err
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return nil, newFileError("write", target, err)
+			}
+			if err := writeFileAtomic(target, []byte(metaRefreshPage(filepath.ToSlash(rel))), 0644); err != nil {
+				return nil, newFileError("write", target, err)
+			}
+			result.Files = append(result.Files, target)
+		}
+	}
+	if format == "" || format == RedirectsNetlify {
+		target := filepath.Join(outputDir, "_redirects")
+		if err := writeNetlifyRedirects(target, list); err != nil {
+			return nil, newFileError("write", target, err)
+		}
+		result.Files = append(result.Files, target)
+	}
+	return result, nil
+}
+
+// recordRenameRedirects adds redirects for the pages a rename or move
+// within the open project takes away from their old path. The manifest
+// change is part of op, so undoing the rename takes the redirects back.
+func (a *App) recordRenameRedirects(op *journalOp, oldPath, newPath string) {
+	root := a.currentProjectRoot()
+	if root == "" || !isWithin(oldPath, root) || !isWithin(newPath, root) {
+		return
+	}
+	var moves [][2]string
+	if info, err := os.Stat(newPath); err == nil && info.IsDir() {
+		_ = filepath.WalkDir(newPath, func(p string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && isPage(p) {
+				if old, ok := rebasePath(p, newPath, oldPath); ok {
+					moves = append(moves, [2]string{old, p})
+				}
+			}
+			return nil
+		})
+	} else if isPage(oldPath) && isPage(newPath) {
+		moves = append(moves, [2]string{oldPath, newPath})
+	}
+	if len(moves) == 0 {
+		return
+	}
+
+	list, err := loadRedirects(root)
+	if err != nil {
+		return
+	}
+	for _, m := range moves {
+		from, err1 := projectRelPath(root, m[0])
+		to, err2 := projectRelPath(root, m[1])
+		if err1 == nil && err2 == nil && from != to {
+			list = addRedirect(list, from, to)
+		}
+	}
+	manifest := redirectsPath(root)
+	if exists(manifest) {
+		if err := op.replacing(manifest); err != nil {
+			return
+		}
+	} else {
+		op.created(manifest)
+	}
+	_ = saveRedirects(root, list)
+}
+
+// addRedirect adds from → to, pointing existing redirects to from straight
+// at to and dropping any redirect away from to, which is a page again
+func addRedirect(list []Redirect, from, to string) []Redirect {
+	kept := make([]Redirect, 0, len(list)+1)
+	for _, r := range list {
+		if r.From == to || r.From == from {
+			continue
+		}
+		if r.To == from {
+			r.To = to
+		}
+		kept = append(kept, r)
+	}
+	kept = append(kept, Redirect{From: from, To: to, Added: time.Now().Format("2006-01-02")})
+	sort.Slice(kept, func(i, j int) bool { return kept[i].From < kept[j].From })
+	return kept
+}
+
+// loadRedirects reads the manifest; a project without one has none
+func loadRedirects(root string) ([]Redirect, error) {
+	path := redirectsPath(root)
+	data, err := os.ReadFile(longPath(path))
+	if os.IsNotExist(err) {
+		return []Redirect{}, nil
+	}
+	if err != nil {
+		return nil, newFileError("read", path, err)
+	}
+	var file redirectsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	if file.Redirects == nil {
+		file.Redirects = []Redirect{}
+	}
+	if err := checkRedirects(file.Redirects); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return file.Redirects, nil
+}
+
+// checkRedirects rejects entries that aren't plain relative paths inside
+// the project. The manifest is committed, and its paths name the files
+// ExportRedirects writes into the output folder.
+func checkRedirects(list []Redirect) error {
+	for _, r := range list {
+		for _, p := range []string{r.From, r.To} {
+			if p == "" || path.IsAbs(p) || path.Clean(p) != p || p == ".." || strings.HasPrefix(p, "../") ||
+				strings.ContainsAny(p, "\\:") || strings.IndexFunc(p, unicode.IsControl) >= 0 {
+				return fmt.Errorf("redirect path %q must be relative to the project and stay inside it", p)
+			}
+		}
+	}
+	return nil
+}
+
+func saveRedirects(root string, list []Redirect) error {
+	if err := checkRedirects(list); err != nil {
+		return err
+	}
+	path := redirectsPath(root)
+	data, err := json.MarshalIndent(redirectsFile{Redirects: list}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, append(data, '\n'), 0644); err != nil {
+		return newFileError("write", path, err)
+	}
+	return nil
+}
+
+// writeNetlifyRedirects replaces the ndxCraft section of a _redirects
+// file, keeping any other rules
+func writeNetlifyRedirects(target string, list []Redirect) error {
+	var kept []string
+	if data, err := os.ReadFile(longPath(target)); err == nil {
+		inside := false
+		for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+			switch {
+			case line == netlifyRedirectsMarker:
+				inside = true
+			case line == netlifyRedirectsMarker+" end":
+				inside = false
+			case !inside:
+				kept = append(kept, line)
+			}
+		}
+	}
+	lines := append(kept, netlifyRedirectsMarker)
+	for _, r := range list {
+		from, to := pageURL(r.From), pageURL(r.To)
+		// Pretty URLs drop the extension
+		from = strings.ReplaceAll(from, " ", "%20")
+		to = strings.ReplaceAll(to, " ", "%20")
+		lines = append(lines,
+			fmt.Sprintf("/%s /%s 301", from, to),
+			fmt.Sprintf("/%s /%s 301", strings.TrimSuffix(from, ".html"), strings.TrimSuffix(to, ".html")))
+	}
+	lines = append(lines, netlifyRedirectsMarker+" end")
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(target, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+// metaRefreshPage is a page that forwards to url at once
+func metaRefreshPage(url string) string {
+	u := html.EscapeString(url)
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Moved</title>
+<meta http-equiv="refresh" content="0; url=%s">
+<link rel="canonical" href="%s">
+</head>
+<body>
+<p>This page has moved to <a href="%s">%s</a>.</p>
+</body>
+</html>
+`, u, u, u, u)
+}
+
+// pageURL is where a page is published, relative to the output folder
+func pageURL(rel string) string {
+	if isPage(rel) {
+		return strings.TrimSuffix(rel, path.Ext(rel)) + ".html"
+	}
+	return rel
+}
+
+// isPage reports whether a path is an AsciiDoc document
+func isPage(p string) bool {
+	return strings.EqualFold(filepath.Ext(p), ".adoc")
+}
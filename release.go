@@ -0,0 +1,343 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ReleaseError is a release refused by the content freeze or the health
+// check. Like GitError it reaches the frontend as an object.
+type ReleaseError struct {
+	Message  string `json:"message"`
+	Errors   int    `json:"errors,omitempty"`
+	Warnings int    `json:"warnings,omitempty"`
+	// Changed lists the uncommitted files that block the release
+	Changed []string `json:"changed,omitempty"`
+}
+
+func (e *ReleaseError) Error() string { return e.Message }
+
+// ReleaseDocs cuts a docs release of the committed state of the project:
+// the health check must pass without errors, the export presets' outputs
+// are snapshotted into a folder named after the version (under the
+// "release_folder" setting, a folder inside the project, or releases/),
+// the commit is tagged (with the "release_tag_prefix" setting, "v" by
+// default) and the release is recorded. Export the presets after the last
+// change to the sources; older outputs are refused.
+func (a *App) ReleaseDocs(projectPath string, version string) (*DocsRelease, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	version = strings.TrimSpace(version)
+	if version == "" || strings.ContainsAny(version, `/\`) || version == "." || version == ".." {
+		return nil, fmt.Errorf("invalid version %q", version)
+	}
+	tag := a.projectSettingStringIn(projectPath, "release_tag_prefix", "v") + strings.TrimPrefix(version, "v")
+	if err := plumbing.NewTagReferenceName(tag).Validate(); err != nil {
+		return nil, fmt.Errorf("invalid tag name %q", tag)
+	}
+
+	repo, wt, err := openRepo(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if wt == nil {
+		return nil, fmt.Errorf("repository has no working tree")
+	}
+	if _, err := repo.Tag(tag); err == nil {
+		return nil, fmt.Errorf("tag %s already exists", tag)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("commit something before releasing")
+	}
+	if changed, err := uncommittedFiles(wt); err != nil {
+		return nil, err
+	} else if len(changed) > 0 {
+		return nil, &ReleaseError{Message: "commit or stash your changes before releasing", Changed: changed}
+	}
+
+	run, err := a.collectChecks(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	checks := run.summary()
+	if checks.Errors > 0 {
+		return nil, &ReleaseError{
+			Message:  fmt.Sprintf("the health check found %d errors", checks.Errors),
+			Errors:   checks.Errors,
+			Warnings: checks.Warnings,
+		}
+	}
+
+	folder, err := a.projectFolder(projectPath, "release_folder", "releases")
+	if err != nil {
+		return nil, err
+	}
+	release := &DocsRelease{
+		Version:   version,
+		Tag:       tag,
+		Commit:    head.Hash().String(),
+		Folder:    filepath.Join(folder, version),
+		Errors:    checks.Errors,
+		Warnings:  checks.Warnings,
+		CreatedAt: time.Now(),
+	}
+	if exists(release.Folder) {
+		return nil, fmt.Errorf("%s already exists", release.Folder)
+	}
+	if release.Outputs, err = a.snapshotExports(projectPath, release); err != nil {
+		os.RemoveAll(release.Folder)
+		return nil, err
+	}
+	if _, err := a.GitCreateTag(projectPath, tag, "Docs release "+version); err != nil {
+		os.RemoveAll(release.Folder)
+		return nil, err
+	}
+	if err := db.AddRelease(projectPath, *release); err != nil {
+		_ = repo.DeleteTag(tag)
+		os.RemoveAll(release.Folder)
+		return nil, err
+	}
+	a.emitTreeChanged()
+	return release, nil
+}
+
+// GetReleases lists the project's docs releases, newest first
+func (a *App) GetReleases(projectPath string) ([]DocsRelease, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	return db.GetReleases(projectPath)
+}
+
+// snapshotExports copies the output of every export preset into the
+// release folder, one folder per preset, with a checksum manifest. Hidden
+// content is stripped from the copies of public presets. Outputs older
+// than the committed sources are refused, as they may not match the
+// release.
+func (a *App) snapshotExports(projectPath string, release *DocsRelease) ([]string, error) {
+	cfg, err := LoadProjectConfig(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	var presets []ExportPreset
+	if cfg != nil {
+		presets = cfg.ExportPresets
+	}
+	outputs := map[string]string{}
+	skip := []string{filepath.Dir(release.Folder)}
+	for _, p := range presets {
+		if p.Output == "" {
+			continue
+		}
+		src := filepath.Join(projectPath, filepath.FromSlash(p.Output))
+		if filepath.IsAbs(filepath.FromSlash(p.Output)) || !isWithin(src, projectPath) || src == projectPath {
+			return nil, fmt.Errorf("the output of export preset %s must be inside the project", p.Name)
+		}
+		if !exists(src) {
+			return nil, fmt.Errorf("export preset %s has no output at %s; export it first", p.Name, p.Output)
+		}
+		outputs[p.Name] = src
+		skip = append(skip, src)
+	}
+	changed, err := lastSourceChange(projectPath, skip)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range presets {
+		if src, ok := outputs[p.Name]; ok {
+			if err := checkOutputCurrent(src, release.Commit, changed); err != nil {
+				return nil, fmt.Errorf("export preset %s: %w", p.Name, err)
+			}
+		}
+	}
+
+	if err := os.MkdirAll(release.Folder, 0755); err != nil {
+		return nil, newFileError("write", release.Folder, err)
+	}
+	for _, p := range presets {
+		src, ok := outputs[p.Name]
+		if !ok {
+			continue
+		}
+		dst := filepath.Join(release.Folder, a.SlugifyTitle(p.Name, ""))
+		if info, err := os.Stat(src); err == nil && !info.IsDir() {
+			if err := os.MkdirAll(dst, 0755); err != nil {
+				return nil, newFileError("write", dst, err)
+			}
+			dst = filepath.Join(dst, filepath.Base(src))
+		}
+		err := a.copyTree(src, dst, CopyOptions{Overwrite: OverwriteError}, &CopyProgress{Src: src}, map[string]bool{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to snapshot %s: %w", p.Name, err)
+		}
+		if p.Audience != ExportAudienceInternal {
+			if err := stripHiddenTree(dst, a.hiddenRoles()); err != nil {
+				return nil, fmt.Errorf("failed to snapshot %s: %w", p.Name, err)
+			}
+		}
+		if err := a.exportPresetRedirects(projectPath, p, dst); err != nil {
+			return nil, fmt.Errorf("failed to snapshot %s: %w", p.Name, err)
+		}
+	}
+
+	manifest := ExportManifest{Commit: release.Commit, Created: release.CreatedAt.UTC(), Files: []ManifestEntry{}}
+	snapshot := []string{}
+	files, err := globFiles(release.Folder, "**/*")
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		sum, size, err := fileChecksum(f)
+		if err != nil {
+			return nil, newFileError("read", f, err)
+		}
+		rel, _ := filepath.Rel(release.Folder, f)
+		rel = filepath.ToSlash(rel)
+		snapshot = append(snapshot, rel)
+		manifest.Files = append(manifest.Files, ManifestEntry{Path: rel, Size: size, SHA256: sum})
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(release.Folder, ManifestName)
+	if err := writeFileAtomic(path, append(data, '\n'), 0644); err != nil {
+		return nil, newFileError("write", path, err)
+	}
+	return snapshot, nil
+}
+
+// lastSourceChange returns when the newest of the files committed under
+// projectPath was last written, leaving out the folders in skip, where
+// exports and releases go
+func lastSourceChange(projectPath string, skip []string) (time.Time, error) {
+	repo, wt, err := openRepo(projectPath)
+	if err != nil {
+		return time.Time{}, err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return time.Time{}, err
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return time.Time{}, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return time.Time{}, err
+	}
+	root := wt.Filesystem.Root()
+	var newest time.Time
+	walker := object.NewTreeWalker(tree, true, nil)
+	defer walker.Close()
+	for {
+		name, entry, err := walker.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return time.Time{}, err
+		}
+		if !entry.Mode.IsFile() {
+			continue
+		}
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if !isWithin(path, projectPath) || slices.ContainsFunc(skip, func(dir string) bool { return isWithin(path, dir) }) {
+			continue
+		}
+		if info, err := os.Lstat(path); err == nil && info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+	}
+	return newest, nil
+}
+
+// checkOutputCurrent refuses an export output that predates the sources.
+// Outputs with a manifest (see ExportManifest) must name commit instead.
+func checkOutputCurrent(output, commit string, changed time.Time) error {
+	if data, err := os.ReadFile(longPath(filepath.Join(output, ManifestName))); err == nil {
+		var manifest ExportManifest
+		if json.Unmarshal(data, &manifest) == nil && manifest.Commit != "" {
+			if manifest.Commit != commit {
+				return fmt.Errorf("the output was exported from commit %.7s, not %.7s; export it again", manifest.Commit, commit)
+			}
+			return nil
+		}
+	}
+	stale := false
+	err := filepath.WalkDir(output, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().Before(changed) {
+			stale = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if stale {
+		return fmt.Errorf("the output is older than the last change to the sources; export it again")
+	}
+	return nil
+}
+
+// exportPresetRedirects writes the project's redirects into the output
+// folder of an html preset, in the format the preset asks for
+func (a *App) exportPresetRedirects(projectPath string, p ExportPreset, outputDir string) error {
+	if p.Format != "html" || p.Redirects == "none" {
+		return nil
+	}
+	if info, err := os.Stat(outputDir); err != nil || !info.IsDir() {
+		return nil
+	}
+	format := RedirectsMetaRefresh
+	switch p.Redirects {
+	case "all":
+		format = ""
+	case RedirectsNetlify:
+		format = RedirectsNetlify
+	}
+	_, err := a.ExportRedirects(projectPath, outputDir, format)
+	return err
+}
+
+// uncommittedFiles lists the tracked files with changes not committed yet
+func uncommittedFiles(wt *git.Worktree) ([]string, error) {
+	status, err := wt.Status()
+	if err != nil {
+		return nil, err
+	}
+	var changed []string
+	for rel, fs := range status {
+		if fs.Worktree == git.Untracked {
+			continue
+		}
+		if fs.Worktree != git.Unmodified || fs.Staging != git.Unmodified {
+			changed = append(changed, rel)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ManifestName is the checksum manifest written into reproducible exports
+const ManifestName = "MANIFEST.json"
+
+// ExportManifest lists the files of an export with their SHA-256 sums
+type ExportManifest struct {
+	Commit  string          `json:"commit,omitempty"`
+	Created time.Time       `json:"created"`
+	Files   []ManifestEntry `json:"files"`
+}
+
+// ManifestEntry is one file of an ExportManifest
+type ManifestEntry struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// reproducibleTime is the timestamp stamped on every entry of a
+// reproducible export: SOURCE_DATE_EPOCH when set, otherwise the earliest
+// time a zip can hold
+func reproducibleTime() time.Time {
+	if epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+		return time.Unix(epoch, 0).UTC()
+	}
+	return time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// gitHead returns the commit hash checked out in root, or "" when root is
+// not a git working tree
+func gitHead(root string) string {
+	gitDir := filepath.Join(root, ".git")
+	// Worktrees and submodules have a .git file pointing elsewhere
+	if data, err := os.ReadFile(gitDir); err == nil {
+		if dir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: "); ok {
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(root, dir)
+			}
+			gitDir = dir
+		}
+	}
+
+	head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return ""
+	}
+	ref, ok := strings.CutPrefix(strings.TrimSpace(string(head)), "ref: ")
+	if !ok {
+		return strings.TrimSpace(string(head))
+	}
+	if data, err := os.ReadFile(filepath.Join(gitDir, filepath.FromSlash(ref))); err == nil {
+		return strings.TrimSpace(string(data))
+	}
+
+	packed, err := os.Open(filepath.Join(gitDir, "packed-refs"))
+	if err != nil {
+		return ""
+	}
+	defer packed.Close()
+	scanner := bufio.NewScanner(packed)
+	for scanner.Scan() {
+		if hash, name, ok := strings.Cut(scanner.Text(), " "); ok && name == ref {
+			return hash
+		}
+	}
+	return ""
+}
+
+// fileChecksum returns the hex SHA-256 of a file and its size
+func fileChecksum(path string) (string, int64, error) {
+	f, err := os.Open(longPath(path))
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+// writeChecksumFile writes a sha256sum-compatible file next to path
+func writeChecksumFile(path, sum string) error {
+	line := sum + "  " + filepath.Base(path) + "\n"
+	return os.WriteFile(path+".sha256", []byte(line), 0644)
+}
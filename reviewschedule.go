@@ -0,0 +1,196 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// reviewDateLayout is how review dates are written in document metadata
+const reviewDateLayout = "2006-01-02"
+
+// ReviewEntry is a document with a scheduled review
+type ReviewEntry struct {
+	Path    string `json:"path"`
+	Title   string `json:"title"`
+	Due     string `json:"due"`
+	Owner   string `json:"owner,omitempty"`
+	Overdue bool   `json:"overdue"`
+}
+
+// ReviewSchedule is the result of ExportReviewSchedule
+type ReviewSchedule struct {
+	OutputPath string        `json:"outputPath"`
+	Entries    []ReviewEntry `json:"entries"`
+}
+
+// ExportReviewSchedule writes a calendar of document review due dates so
+// re-reviews show up in Outlook or Google Calendar. A document's due date
+// is its "review-due" attribute, or "last-reviewed" plus
+// "review-interval" (e.g. "90d", "6m", "1y"); "review-owner" names who
+// reviews it. Only the "ics" format is supported.
+func (a *App) ExportReviewSchedule(projectPath string, format string, outputPath string) (*ReviewSchedule, error) {
+	if format == "" {
+		format = "ics"
+	}
+	if format != "ics" {
+		return nil, fmt.Errorf("unsupported review schedule format %q", format)
+	}
+	entries, err := reviewEntries(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(outputPath, []byte(reviewCalendar(projectPath, entries, time.Now())), 0644); err != nil {
+		return nil, newFileError("export", outputPath, err)
+	}
+	return &ReviewSchedule{OutputPath: outputPath, Entries: entries}, nil
+}
+
+// reviewEntries collects the documents with a review date, soonest first
+func reviewEntries(projectPath string) ([]ReviewEntry, error) {
+	docs, err := globFiles(projectPath, "**/*.adoc")
+	if err != nil {
+		return nil, err
+	}
+	today := time.Now().Format(reviewDateLayout)
+	entries := []ReviewEntry{}
+	for _, path := range docs {
+		content, err := os.ReadFile(longPath(path))
+		if err != nil {
+			continue
+		}
+		meta := documentMetadata(content)
+		due, ok := reviewDue(meta)
+		if !ok {
+			continue
+		}
+		title := meta["doctitle"]
+		if title == "" {
+			title = meta["title"]
+		}
+		if title == "" {
+			title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		entries = append(entries, ReviewEntry{
+			Path:    path,
+			Title:   title,
+			Due:     due.Format(reviewDateLayout),
+			Owner:   meta["review-owner"],
+			Overdue: due.Format(reviewDateLayout) < today,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Due != entries[j].Due {
+			return entries[i].Due < entries[j].Due
+		}
+		return entries[i].Path < entries[j].Path
+	})
+	return entries, nil
+}
+
+// reviewDue reads a document's review due date from its metadata
+func reviewDue(meta map[string]string) (time.Time, bool) {
+	for _, key := range []string{"review-due", "review_due", "next-review"} {
+		if v := strings.TrimSpace(meta[key]); v != "" {
+			t, err := time.Parse(reviewDateLayout, v)
+			return t, err == nil
+		}
+	}
+	last, err := time.Parse(reviewDateLayout, strings.TrimSpace(meta["last-reviewed"]))
+	if err != nil {
+		return time.Time{}, false
+	}
+	interval := strings.ToLower(strings.TrimSpace(meta["review-interval"]))
+	if interval == "" {
+		return time.Time{}, false
+	}
+	unit := interval[len(interval)-1]
+	n, err := strconv.Atoi(strings.TrimRight(interval, "dwmy"))
+	if err != nil || n <= 0 {
+		return time.Time{}, false
+	}
+	switch unit {
+	case 'w':
+		return last.AddDate(0, 0, 7*n), true
+	case 'm':
+		return last.AddDate(0, n, 0), true
+	case 'y':
+		return last.AddDate(n, 0, 0), true
+	}
+	return last.AddDate(0, 0, n), true
+}
+
+// reviewCalendar renders entries as an iCalendar file of all-day events
+// with a reminder the day before. UIDs are derived from the document path,
+// so re-importing an updated calendar moves events instead of doubling
+// them.
+func reviewCalendar(projectPath string, entries []ReviewEntry, now time.Time) string {
+	var b strings.Builder
+	line := func(s string) { b.WriteString(icsFold(s) + "\r\n") }
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//ndxCraft//Review Schedule//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:" + icsEscape("Document reviews - "+filepath.Base(projectPath)))
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, e := range entries {
+		due, _ := time.Parse(reviewDateLayout, e.Due)
+		rel, err := projectRelPath(projectPath, e.Path)
+		if err != nil {
+			rel = e.Path
+		}
+		sum := sha1.Sum([]byte(filepath.Base(projectPath) + "/" + rel))
+		description := "Review " + rel
+		if e.Owner != "" {
+			description += "\nOwner: " + e.Owner
+		}
+
+		line("BEGIN:VEVENT")
+		line("UID:" + hex.EncodeToString(sum[:]) + "@ndxcraft")
+		line("DTSTAMP:" + stamp)
+		line("DTSTART;VALUE=DATE:" + due.Format("20060102"))
+		line("DTEND;VALUE=DATE:" + due.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY:" + icsEscape("Review: "+e.Title))
+		line("DESCRIPTION:" + icsEscape(description))
+		line("TRANSP:TRANSPARENT")
+		line("BEGIN:VALARM")
+		line("ACTION:DISPLAY")
+		line("DESCRIPTION:" + icsEscape("Review due: "+e.Title))
+		line("TRIGGER:-P1D")
+		line("END:VALARM")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+// icsEscape escapes a TEXT value (RFC 5545 section 3.3.11)
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// icsFold wraps content lines longer than 75 octets, keeping UTF-8
+// sequences whole
+func icsFold(s string) string {
+	if len(s) <= 75 {
+		return s
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		n := len(string(r))
+		if width+n > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += n
+	}
+	return b.String()
+}
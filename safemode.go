@@ -0,0 +1,36 @@
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+// safeModePreferences are still honoured in safe mode; everything else
+// reads as unset so the app starts with defaults
+var safeModePreferences = map[string]bool{
+	"projectRoot": true,
+}
+
+// errSafeMode is returned by features that are switched off in safe mode
+var errSafeMode = errors.New("disabled in safe mode")
+
+// safeModeRequested reports whether the app was launched with --safe-mode
+// (or -safe-mode) or with NDXCRAFT_SAFE_MODE set
+func safeModeRequested(args []string) bool {
+	if v := os.Getenv("NDXCRAFT_SAFE_MODE"); v != "" && v != "0" && v != "false" {
+		return true
+	}
+	for _, arg := range args {
+		if arg == "--safe-mode" || arg == "-safe-mode" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSafeMode tells the frontend to skip plugins, watchers and custom themes.
+// Stored preferences are left untouched and can still be edited through
+// GetAllPreferences/SavePreference.
+func (a *App) IsSafeMode() bool {
+	return a.safeMode
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/zalando/go-keyring"
+)
+
+// keyringService namespaces every secret ndxCraft keeps in the OS keychain
+const keyringService = "ndxCraft"
+
+// SetSecret stores a named secret in the OS keychain
+func (a *App) SetSecret(name string, value string) error {
+	if name == "" {
+		return fmt.Errorf("secret name must not be empty")
+	}
+	return keyring.Set(keyringService, name, value)
+}
+
+// DeleteSecret removes a named secret from the OS keychain
+func (a *App) DeleteSecret(name string) error {
+	err := keyring.Delete(keyringService, name)
+	if err == keyring.ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+// HasSecret reports whether a named secret exists, without revealing it
+func (a *App) HasSecret(name string) (bool, error) {
+	_, err := keyring.Get(keyringService, name)
+	if err == keyring.ErrNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// getSecret reads a secret for backend use only; values never leave Go
+func getSecret(name string) (string, error) {
+	value, err := keyring.Get(keyringService, name)
+	if err == keyring.ErrNotFound {
+		return "", fmt.Errorf("secret %q not found in keychain", name)
+	}
+	return value, err
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Slug conventions understood by Slugify
+const (
+	SlugKebab       = "kebab"       // my-section-title
+	SlugSnake       = "snake"       // my_section_title
+	SlugAsciidoctor = "asciidoctor" // _my_section_title (Asciidoctor's default idprefix/idseparator)
+)
+
+// SlugOptions controls how titles are turned into IDs and file names
+type SlugOptions struct {
+	Convention    string `json:"convention"`
+	Transliterate bool   `json:"transliterate"`
+	KeepCJK       bool   `json:"keepCJK"`
+	MaxLength     int    `json:"maxLength"`
+}
+
+// Letters that don't decompose into a base letter plus combining marks
+var slugTransliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "ae", 'œ': "oe", 'Œ': "oe",
+	'ø': "o", 'Ø': "o", 'đ': "d", 'Đ': "d", 'ł': "l", 'Ł': "l",
+	'þ': "th", 'Þ': "th", 'ð': "d", 'Ð': "d", 'ı': "i",
+}
+
+// SlugifyTitle turns a title into a slug using the current project's slug
+// settings. An empty convention uses the project's configured default.
+func (a *App) SlugifyTitle(title string, convention string) string {
+	opts := a.slugOptions()
+	if convention != "" {
+		opts.Convention = convention
+	}
+	return Slugify(title, opts)
+}
+
+// slugOptions reads the slug settings for the current project
+func (a *App) slugOptions() SlugOptions {
+	opts := SlugOptions{Convention: SlugKebab, Transliterate: true, KeepCJK: true}
+
+	if v, ok := a.projectSetting("slug_convention").(string); ok && v != "" {
+		opts.Convention = v
+	}
+	if v, ok := a.projectSetting("slug_transliterate").(bool); ok {
+		opts.Transliterate = v
+	}
+	if v, ok := a.projectSetting("slug_keep_cjk").(bool); ok {
+		opts.KeepCJK = v
+	}
+	if v, ok := a.projectSetting("slug_max_length").(float64); ok {
+		opts.MaxLength = int(v)
+	}
+	return opts
+}
+
+// Slugify is the single place titles become identifiers, so file names,
+// anchors and export names all agree with each other
+func Slugify(title string, opts SlugOptions) string {
+	sep := "-"
+	prefix := ""
+	switch opts.Convention {
+	case SlugSnake:
+		sep = "_"
+	case SlugAsciidoctor:
+		sep = "_"
+		prefix = "_"
+	}
+
+	if opts.Transliterate {
+		title = transliterate(title)
+	}
+
+	var b strings.Builder
+	pendingSep := false
+	for _, r := range strings.ToLower(title) {
+		keep := false
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			keep = true
+		case isCJK(r):
+			keep = opts.KeepCJK
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			// Non-Latin scripts survive when not transliterated
+			keep = !opts.Transliterate
+		case unicode.Is(unicode.Mn, r):
+			continue
+		}
+
+		if !keep {
+			pendingSep = b.Len() > 0
+			continue
+		}
+		if pendingSep {
+			b.WriteString(sep)
+			pendingSep = false
+		}
+		b.WriteRune(r)
+	}
+
+	slug := b.String()
+	if opts.MaxLength > 0 {
+		slug = truncateSlug(slug, sep, opts.MaxLength)
+	}
+	if slug == "" {
+		return ""
+	}
+	return prefix + slug
+}
+
+func transliterate(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if t, ok := slugTransliterations[r]; ok {
+			b.WriteString(t)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return norm.NFC.String(b.String())
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// truncateSlug cuts a slug to max runes, preferring a separator boundary
+func truncateSlug(slug, sep string, max int) string {
+	runes := []rune(slug)
+	if len(runes) <= max {
+		return slug
+	}
+	cut := string(runes[:max])
+	if i := strings.LastIndex(cut, sep); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimSuffix(cut, sep)
+}
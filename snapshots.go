@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Shadow snapshot settings and their defaults
+const (
+	defaultSnapshotInterval  = 24 * time.Hour
+	defaultSnapshotRetention = 14
+	snapshotDateLayout       = "2006-01-02"
+	snapshotLastRunKey       = "shadow_snapshot_last"
+	snapshotCheckEvery       = time.Minute
+)
+
+// SnapshotResult describes a snapshot run
+type SnapshotResult struct {
+	Folder string   `json:"folder"`
+	Files  []string `json:"files"`
+	Pruned []string `json:"pruned"`
+}
+
+// runShadowSnapshots mirrors dirty shadow files to disk on the schedule set
+// by the "shadow_snapshot_*" settings until ctx is done
+func (a *App) runShadowSnapshots(ctx context.Context) {
+	ticker := time.NewTicker(snapshotCheckEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if a.snapshotDue() {
+				_, _ = a.SnapshotShadowFiles()
+			}
+		}
+	}
+}
+
+func (a *App) snapshotDue() bool {
+	if db == nil {
+		return false
+	}
+	if enabled, _ := a.projectSetting("shadow_snapshot_enabled").(bool); !enabled {
+		return false
+	}
+	interval := defaultSnapshotInterval
+	if v, ok := a.projectSetting("shadow_snapshot_interval_minutes").(float64); ok && v > 0 {
+		interval = time.Duration(v) * time.Minute
+	}
+	last, _ := db.GetAppState(snapshotLastRunKey)
+	t, err := time.Parse(time.RFC3339, last)
+	return err != nil || time.Since(t) >= interval
+}
+
+// SnapshotShadowFiles writes every dirty shadow file into today's folder
+// under the snapshot location and prunes folders past the retention period
+func (a *App) SnapshotShadowFiles() (*SnapshotResult, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	shadows, err := db.GetDirtyShadowFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	base := a.snapshotFolder()
+	result := &SnapshotResult{
+		Folder: filepath.Join(base, time.Now().Format(snapshotDateLayout)),
+		Files:  []string{},
+	}
+	root := a.currentProjectRoot()
+	for _, s := range shadows {
+		target := filepath.Join(result.Folder, snapshotName(root, s.Path))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return result, err
+		}
+		if err := writeFileAtomic(target, []byte(s.Content), 0644); err != nil {
+			return result, err
+		}
+		result.Files = append(result.Files, target)
+	}
+
+	retention := defaultSnapshotRetention
+	if v, ok := a.projectSetting("shadow_snapshot_retention_days").(float64); ok {
+		retention = int(v)
+	}
+	result.Pruned = pruneSnapshots(base, retention)
+
+	_ = db.SetAppState(snapshotLastRunKey, time.Now().Format(time.RFC3339))
+	return result, nil
+}
+
+// snapshotFolder is the "shadow_snapshot_folder" setting, or the project's
+// .ndxcraft/drafts folder
+func (a *App) snapshotFolder() string {
+	if v := a.projectSettingString("shadow_snapshot_folder", ""); v != "" {
+		return v
+	}
+	if root := a.currentProjectRoot(); root != "" {
+		return filepath.Join(root, ProjectConfigDir, "drafts")
+	}
+	return filepath.Join(filepath.Dir(db.path), "drafts")
+}
+
+// snapshotName keeps project files at their relative path and files from
+// elsewhere under "_external" with their absolute path flattened in
+func snapshotName(root, path string) string {
+	if root != "" {
+		if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+	}
+	abs := strings.TrimPrefix(path, filepath.VolumeName(path))
+	abs = strings.TrimLeft(abs, `/\`)
+	return filepath.Join("_external", abs)
+}
+
+// pruneSnapshots removes dated folders older than retentionDays; zero or
+// less keeps everything
+func pruneSnapshots(base string, retentionDays int) []string {
+	pruned := []string{}
+	if retentionDays <= 0 {
+		return pruned
+	}
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		return pruned
+	}
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+	for _, e := range entries {
+		day, err := time.ParseInLocation(snapshotDateLayout, e.Name(), time.Local)
+		if err != nil || !e.IsDir() || !day.Before(cutoff) {
+			continue
+		}
+		dir := filepath.Join(base, e.Name())
+		if os.RemoveAll(dir) == nil {
+			pruned = append(pruned, dir)
+		}
+	}
+	return pruned
+}
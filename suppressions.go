@@ -0,0 +1,127 @@
+package main
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+// suppressionExpr matches AsciiDoc line comments that silence findings:
+//
+//	// ndxcraft-ignore reference-case, vale.Spelling
+//	// ndxcraft-ignore-file license-header
+//
+// The first form covers the next line, the second the whole file. A
+// source name such as "vale" silences all of its rules, and without ids
+// every rule is silenced.
+var suppressionExpr = regexp.MustCompile(`^\s*//\s*ndxcraft-ignore(-file)?(?:\s+(.*))?$`)
+
+// suppressions records which rules are silenced where. The "" key stands
+// for every rule.
+type suppressions struct {
+	file  map[string]bool
+	lines map[int]map[string]bool
+}
+
+// parseSuppressions collects the suppression comments of a document
+func parseSuppressions(content string) *suppressions {
+	s := &suppressions{file: map[string]bool{}, lines: map[int]map[string]bool{}}
+	for i, line := range strings.Split(content, "\n") {
+		m := suppressionExpr.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if m == nil {
+			continue
+		}
+		rules := strings.FieldsFunc(m[2], func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+		if len(rules) == 0 {
+			rules = []string{""}
+		}
+		target := s.file
+		if m[1] == "" {
+			// Line i+1 (1-based) is the comment itself
+			target = map[string]bool{}
+			s.lines[i+2] = target
+		}
+		for _, r := range rules {
+			target[r] = true
+		}
+	}
+	return s
+}
+
+// suppressed reports whether d is silenced by a comment
+func (s *suppressions) suppressed(d Diagnostic) bool {
+	for _, rules := range []map[string]bool{s.file, s.lines[d.Line]} {
+		if rules[""] || (d.Rule != "" && rules[d.Rule]) || (d.Source != "" && rules[d.Source]) {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleSeverity applies the lint.rules overrides of the project config.
+// Keys are rule ids, or a source name such as "vale" to re-grade all of a
+// source's findings; a rule id wins over its source.
+func ruleSeverity(overrides map[string]string, source, rule, severity string) string {
+	if s, ok := overrides[rule]; ok && rule != "" {
+		return s
+	}
+	if s, ok := overrides[source]; ok && source != "" {
+		return s
+	}
+	return severity
+}
+
+// severityOverrides returns the lint.rules section of the open project's
+// config
+func (a *App) severityOverrides() map[string]string {
+	if cfg, err := LoadProjectConfig(a.currentProjectRoot()); err == nil && cfg != nil {
+		return cfg.Lint.Rules
+	}
+	return nil
+}
+
+// filterDiagnostics re-grades findings per the project config and drops
+// ignored and suppressed ones, and those recorded in baseline when given.
+// The file is read for its suppression comments when it has findings.
+func (a *App) filterDiagnostics(path string, diagnostics []Diagnostic, baseline *lintBaseline) []Diagnostic {
+	if len(diagnostics) == 0 {
+		return diagnostics
+	}
+	var data []byte
+	if path != "" {
+		data, _ = os.ReadFile(longPath(path))
+	}
+	return a.filterDiagnosticsOf(path, data, diagnostics, baseline)
+}
+
+// filterDiagnosticsOf is filterDiagnostics for findings in data rather than
+// the file on disk, such as a staged version; nil data has no
+// suppression comments
+func (a *App) filterDiagnosticsOf(path string, data []byte, diagnostics []Diagnostic, baseline *lintBaseline) []Diagnostic {
+	if len(diagnostics) == 0 {
+		return diagnostics
+	}
+	overrides := a.severityOverrides()
+	content, haveContent := string(data), data != nil
+	var supp *suppressions
+	if haveContent {
+		supp = parseSuppressions(content)
+	}
+	var known map[string]int
+	if haveContent && baseline != nil {
+		known = baseline.remaining(path)
+	}
+	kept := make([]Diagnostic, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		d.Severity = ruleSeverity(overrides, d.Source, d.Rule, d.Severity)
+		if d.Severity == SeverityIgnore || (supp != nil && supp.suppressed(d)) {
+			continue
+		}
+		if fp := baselineFingerprint(d, content); known[fp] > 0 {
+			known[fp]--
+			continue
+		}
+		kept = append(kept, d)
+	}
+	return kept
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// StyleProfile describes the voice a project's documentation should have
+type StyleProfile struct {
+	Audience         string   `yaml:"audience" json:"audience"`
+	Tone             string   `yaml:"tone" json:"tone"`
+	ForbiddenPhrases []string `yaml:"forbiddenPhrases" json:"forbiddenPhrases"`
+	Examples         []string `yaml:"examples" json:"examples"`
+}
+
+// ToneIssue is a passage that strays from the style profile
+type ToneIssue struct {
+	Excerpt    string `json:"excerpt"`
+	Reason     string `json:"reason"`
+	Suggestion string `json:"suggestion"`
+}
+
+// ToneReport is the result of CheckTone
+type ToneReport struct {
+	Issues  []ToneIssue `json:"issues"`
+	Rewrite string      `json:"rewrite"`
+}
+
+// GetStyleProfile returns the style profile of a project. The committed
+// config file wins over the one saved in the app.
+func (a *App) GetStyleProfile(projectPath string) (*StyleProfile, error) {
+	if cfg, err := LoadProjectConfig(projectPath); err != nil {
+		return nil, err
+	} else if cfg != nil && cfg.StyleProfile != nil {
+		return cfg.StyleProfile, nil
+	}
+
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	raw, err := db.GetProjectSetting(projectPath, "style_profile")
+	if err != nil || raw == nil {
+		return &StyleProfile{}, err
+	}
+	// Stored as JSON; round-trip it into the struct
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var profile StyleProfile
+	err = json.Unmarshal(data, &profile)
+	return &profile, err
+}
+
+// SaveStyleProfile stores a project's style profile in the app database
+func (a *App) SaveStyleProfile(projectPath string, profile StyleProfile) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	return db.SetProjectSetting(projectPath, "style_profile", profile)
+}
+
+// CheckTone flags passages in selection that stray from the current
+// project's style profile and proposes a rewrite. Forbidden phrases are
+// matched locally; everything else is left to the AI provider's judgement.
+func (a *App) CheckTone(selection string) (*ToneReport, error) {
+	profile, err := a.GetStyleProfile(a.currentProjectRoot())
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ToneReport{Issues: forbiddenPhraseIssues(selection, profile.ForbiddenPhrases)}
+
+	var b strings.Builder
+	b.WriteString("You are an editor enforcing a documentation style profile.\n")
+	if profile.Audience != "" {
+		fmt.Fprintf(&b, "Audience: %s\n", profile.Audience)
+	}
+	if profile.Tone != "" {
+		fmt.Fprintf(&b, "Tone: %s\n", profile.Tone)
+	}
+	if len(profile.ForbiddenPhrases) > 0 {
+		fmt.Fprintf(&b, "Never use: %s\n", strings.Join(profile.ForbiddenPhrases, "; "))
+	}
+	for i, ex := range profile.Examples {
+		fmt.Fprintf(&b, "\nExample %d of the desired voice:\n%s\n", i+1, ex)
+	}
+	fmt.Fprintf(&b, `
+Review the following AsciiDoc text. Flag passages that stray from the profile
+and rewrite the whole text to match it, keeping all AsciiDoc markup intact.
+Respond ONLY with JSON of the form
+{"issues": [{"excerpt": "...", "reason": "...", "suggestion": "..."}], "rewrite": "..."}
+
+Text:
+%s`, selection)
+
+	var ai ToneReport
+	if err := a.generateJSON(aiRequest{Model: a.aiModel(), Prompt: b.String(), Action: "checkTone"}, &ai); err != nil {
+		return nil, err
+	}
+	report.Issues = append(report.Issues, ai.Issues...)
+	report.Rewrite = ai.Rewrite
+	return report, nil
+}
+
+// forbiddenPhraseIssues finds forbidden phrases case-insensitively
+func forbiddenPhraseIssues(text string, phrases []string) []ToneIssue {
+	issues := []ToneIssue{}
+	for _, phrase := range phrases {
+		if phrase == "" {
+			continue
+		}
+		expr := regexp.MustCompile("(?i)" + regexp.QuoteMeta(phrase))
+		for _, m := range expr.FindAllStringIndex(text, -1) {
+			issues = append(issues, ToneIssue{
+				Excerpt: text[m[0]:m[1]],
+				Reason:  fmt.Sprintf("%q is on the project's forbidden phrase list", phrase),
+			})
+		}
+	}
+	return issues
+}
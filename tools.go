@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+)
+
+// Kinds of external tools
+const (
+	ToolKindGit      = "git"
+	ToolKindEditor   = "editor"
+	ToolKindTerminal = "terminal"
+)
+
+// InstalledTool is an external app for the "open in..." menus. Args may
+// contain %project_path%, substituted as for the "git_client_args"
+// preference.
+type InstalledTool struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Kind      string   `json:"kind"`
+	Installed bool     `json:"installed"`
+	Command   string   `json:"command,omitempty"`
+	Args      []string `json:"args,omitempty"`
+}
+
+// toolProbe says where to look for a tool. Paths may use $VAR and ~; for
+// macOS apps Command is "open" and Paths are the app bundles.
+type toolProbe struct {
+	id, name, kind string
+	paths          []string
+	commands       []string
+	args           []string
+}
+
+var toolProbes = map[string][]toolProbe{
+	"windows": {
+		{id: "github-desktop", name: "GitHub Desktop", kind: ToolKindGit,
+			paths: []string{"$LOCALAPPDATA/GitHubDesktop/bin/github.bat"}, args: []string{"%project_path%"}},
+		{id: "gitkraken", name: "GitKraken", kind: ToolKindGit,
+			paths: []string{"$LOCALAPPDATA/gitkraken/gitkraken.exe"}, args: []string{"-p", "%project_path%"}},
+		{id: "sourcetree", name: "Sourcetree", kind: ToolKindGit,
+			paths: []string{"$LOCALAPPDATA/SourceTree/SourceTree.exe"}, args: []string{"-f", "%project_path%"}},
+		{id: "fork", name: "Fork", kind: ToolKindGit,
+			paths: []string{"$LOCALAPPDATA/Fork/Fork.exe"}, args: []string{"%project_path%"}},
+		{id: "sublime-merge", name: "Sublime Merge", kind: ToolKindGit,
+			paths:    []string{"$ProgramFiles/Sublime Merge/smerge.exe"},
+			commands: []string{"smerge"}, args: []string{"%project_path%"}},
+		{id: "tower", name: "Tower", kind: ToolKindGit,
+			paths:    []string{"$LOCALAPPDATA/Programs/Tower/Tower.exe"},
+			commands: []string{"gittower"}, args: []string{"%project_path%"}},
+		{id: "vscode", name: "Visual Studio Code", kind: ToolKindEditor,
+			paths:    []string{"$LOCALAPPDATA/Programs/Microsoft VS Code/Code.exe", "$ProgramFiles/Microsoft VS Code/Code.exe"},
+			commands: []string{"code"}, args: []string{"%project_path%"}},
+		{id: "windows-terminal", name: "Windows Terminal", kind: ToolKindTerminal,
+			paths: []string{"$LOCALAPPDATA/Microsoft/WindowsApps/wt.exe"}, commands: []string{"wt"}, args: []string{"-d", "%project_path%"}},
+	},
+	"darwin": {
+		{id: "github-desktop", name: "GitHub Desktop", kind: ToolKindGit,
+			paths: []string{"/Applications/GitHub Desktop.app", "~/Applications/GitHub Desktop.app"}},
+		{id: "gitkraken", name: "GitKraken", kind: ToolKindGit,
+			paths: []string{"/Applications/GitKraken.app", "~/Applications/GitKraken.app"}},
+		{id: "sourcetree", name: "Sourcetree", kind: ToolKindGit,
+			paths: []string{"/Applications/Sourcetree.app", "~/Applications/Sourcetree.app"}},
+		{id: "fork", name: "Fork", kind: ToolKindGit,
+			paths: []string{"/Applications/Fork.app", "~/Applications/Fork.app"}},
+		{id: "sublime-merge", name: "Sublime Merge", kind: ToolKindGit,
+			paths: []string{"/Applications/Sublime Merge.app", "~/Applications/Sublime Merge.app"}},
+		{id: "tower", name: "Tower", kind: ToolKindGit,
+			paths: []string{"/Applications/Tower.app", "~/Applications/Tower.app"}},
+		{id: "vscode", name: "Visual Studio Code", kind: ToolKindEditor,
+			paths: []string{"/Applications/Visual Studio Code.app", "~/Applications/Visual Studio Code.app"}},
+		{id: "iterm", name: "iTerm", kind: ToolKindTerminal,
+			paths: []string{"/Applications/iTerm.app", "~/Applications/iTerm.app"}},
+		{id: "terminal", name: "Terminal", kind: ToolKindTerminal,
+			paths: []string{"/System/Applications/Utilities/Terminal.app", "/Applications/Utilities/Terminal.app"}},
+	},
+	"linux": {
+		{id: "github-desktop", name: "GitHub Desktop", kind: ToolKindGit,
+			commands: []string{"github-desktop"}, args: []string{"%project_path%"}},
+		{id: "gitkraken", name: "GitKraken", kind: ToolKindGit,
+			commands: []string{"gitkraken"}, args: []string{"-p", "%project_path%"}},
+		{id: "sublime-merge", name: "Sublime Merge", kind: ToolKindGit,
+			commands: []string{"smerge"}, args: []string{"%project_path%"}},
+		{id: "vscode", name: "Visual Studio Code", kind: ToolKindEditor,
+			commands: []string{"code", "codium"}, args: []string{"%project_path%"}},
+		{id: "gnome-terminal", name: "GNOME Terminal", kind: ToolKindTerminal,
+			commands: []string{"gnome-terminal"}, args: []string{"--working-directory=%project_path%"}},
+		{id: "konsole", name: "Konsole", kind: ToolKindTerminal,
+			commands: []string{"konsole"}, args: []string{"--workdir", "%project_path%"}},
+	},
+}
+
+// DetectInstalledTools reports which git clients, editors and terminals
+// known for this OS are installed, with the command to open a project in
+// each. Sourcetree, Fork and Tower have no Linux version and iTerm is
+// macOS only.
+func (a *App) DetectInstalledTools() []InstalledTool {
+	tools := []InstalledTool{}
+	for _, p := range toolProbes[goruntime.GOOS] {
+		tools = append(tools, p.detect())
+	}
+	return tools
+}
+
+// OpenInTool opens projectPath in a tool from DetectInstalledTools
+func (a *App) OpenInTool(id string, projectPath string) error {
+	tool, ok := findTool(id)
+	if !ok {
+		return fmt.Errorf("unknown tool %q", id)
+	}
+	if !tool.Installed {
+		return fmt.Errorf("%s is not installed", tool.Name)
+	}
+	return launchTool(tool, projectPath)
+}
+
+// findTool detects a single tool by id
+func findTool(id string) (InstalledTool, bool) {
+	for _, p := range toolProbes[goruntime.GOOS] {
+		if p.id == id {
+			return p.detect(), true
+		}
+	}
+	return InstalledTool{}, false
+}
+
+// launchTool starts a tool on projectPath without waiting for it
+func launchTool(tool InstalledTool, projectPath string) error {
+	args := make([]string, len(tool.Args))
+	for i, arg := range tool.Args {
+		args[i] = strings.ReplaceAll(arg, "%project_path%", projectPath)
+	}
+	return exec.Command(tool.Command, args...).Start()
+}
+
+// detect looks the tool up and fills in how to launch it
+func (p toolProbe) detect() InstalledTool {
+	tool := InstalledTool{ID: p.id, Name: p.name, Kind: p.kind}
+	if path := p.find(); path != "" {
+		tool.Installed = true
+		tool.Command = path
+		tool.Args = p.args
+		if goruntime.GOOS == "darwin" {
+			// Bundles are started through open(1)
+			tool.Command = "open"
+			tool.Args = []string{"-a", path, "%project_path%"}
+		}
+	}
+	return tool
+}
+
+// find returns the first existing path or command on PATH
+func (p toolProbe) find() string {
+	home, _ := os.UserHomeDir()
+	for _, path := range p.paths {
+		if len(path) > 1 && path[:2] == "~/" {
+			if home == "" {
+				continue
+			}
+			path = filepath.Join(home, path[2:])
+		}
+		path = filepath.FromSlash(os.ExpandEnv(path))
+		if filepath.IsAbs(path) && exists(path) {
+			return path
+		}
+	}
+	for _, name := range p.commands {
+		if path, err := exec.LookPath(name); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// githubDesktopBundleID identifies GitHub Desktop to Spotlight on macOS
+const githubDesktopBundleID = "com.github.GitHubClient"
+
+// IsGitHubDesktopInstalled reports whether OpenGitClient can fall back to
+// GitHub Desktop
+func (a *App) IsGitHubDesktopInstalled() bool {
+	_, ok := detectGitHubDesktop()
+	return ok
+}
+
+// detectGitHubDesktop looks for GitHub Desktop the way each OS registers
+// it: the x-github-client protocol on Windows, the bundle id on macOS
+// (found wherever the app was moved) and the github-desktop command on
+// Linux. It returns the app's path when known.
+func detectGitHubDesktop() (string, bool) {
+	switch goruntime.GOOS {
+	case "windows":
+		if exec.Command("reg", "query", `HKCR\x-github-client`).Run() == nil {
+			return "", true
+		}
+	case "darwin":
+		out, err := exec.Command("mdfind", "kMDItemCFBundleIdentifier == '"+githubDesktopBundleID+"'").Output()
+		if err == nil {
+			if first, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n"); first != "" {
+				return first, true
+			}
+		}
+	}
+	for _, p := range toolProbes[goruntime.GOOS] {
+		if p.id == "github-desktop" {
+			if path := p.find(); path != "" {
+				return path, true
+			}
+		}
+	}
+	return "", false
+}
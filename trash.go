@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectTrashDir holds files deleted from within a project
+const ProjectTrashDir = ".ndxcraft-trash"
+
+// defaultTrashRetentionDays is used when "trash_retention_days" is unset
+const defaultTrashRetentionDays = 30
+
+// trashRootFor returns the project whose trash a deleted path should go
+// to, or "" when it is outside the open project or already in the trash
+func (a *App) trashRootFor(path string) string {
+	root := a.currentProjectRoot()
+	if db == nil || root == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	if rel == ProjectTrashDir || strings.HasPrefix(rel, ProjectTrashDir+string(filepath.Separator)) {
+		return ""
+	}
+	return root
+}
+
+// moveToProjectTrash moves path into the project's trash folder and
+// records it so it can be listed and restored
+func (a *App) moveToProjectTrash(root, path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+
+	item := TrashItem{
+		ID:           uuid.New().String(),
+		Project:      root,
+		OriginalPath: path,
+		IsDir:        info.IsDir(),
+		DeletedAt:    time.Now(),
+	}
+	item.TrashPath = filepath.Join(root, ProjectTrashDir, item.ID, filepath.Base(path))
+	if err := os.MkdirAll(filepath.Dir(item.TrashPath), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(path, item.TrashPath); err != nil {
+		os.Remove(filepath.Dir(item.TrashPath))
+		return err
+	}
+	if err := db.AddTrashItem(item); err != nil {
+		return err
+	}
+	_ = db.ClearShadowPaths(path)
+
+	op := a.beginOperation("Delete " + filepath.Base(path))
+	op.renamed(path, item.TrashPath)
+	a.commitOperation(op)
+
+	a.emitTreeChanged()
+	return nil
+}
+
+// ListTrash returns what was deleted from a project, newest first. Entries
+// whose files are gone (purged or restored by undo) are dropped.
+func (a *App) ListTrash(projectPath string) ([]TrashItem, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	items, err := db.GetTrashItems(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	live := []TrashItem{}
+	for _, item := range items {
+		if _, err := os.Lstat(item.TrashPath); os.IsNotExist(err) {
+			os.Remove(filepath.Dir(item.TrashPath))
+			_ = db.DeleteTrashItem(item.ID)
+			continue
+		}
+		live = append(live, item)
+	}
+	return live, nil
+}
+
+// RestoreFromTrash moves a trashed file back to where it was deleted from
+// and returns that path
+func (a *App) RestoreFromTrash(id string) (string, error) {
+	if db == nil {
+		return "", fmt.Errorf("database not initialized")
+	}
+	item, err := db.GetTrashItem(id)
+	if err != nil {
+		return "", err
+	}
+	if exists(item.OriginalPath) {
+		return "", fmt.Errorf("%s already exists", item.OriginalPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(item.OriginalPath), 0755); err != nil {
+		return "", err
+	}
+	if err := a.renamePath(item.TrashPath, item.OriginalPath); err != nil {
+		return "", err
+	}
+	os.Remove(filepath.Dir(item.TrashPath))
+	if err := db.DeleteTrashItem(id); err != nil {
+		return "", err
+	}
+
+	op := a.beginOperation("Restore " + filepath.Base(item.OriginalPath))
+	op.renamed(item.TrashPath, item.OriginalPath)
+	a.commitOperation(op)
+	return item.OriginalPath, nil
+}
+
+// EmptyTrash permanently removes everything in a project's trash
+func (a *App) EmptyTrash(projectPath string) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	if err := purgeTrash(projectPath, time.Now()); err != nil {
+		return err
+	}
+	return os.RemoveAll(filepath.Join(projectPath, ProjectTrashDir))
+}
+
+// purgeExpiredTrash applies the "trash_retention_days" setting to the open
+// project; zero or less keeps trashed files forever
+func (a *App) purgeExpiredTrash() {
+	root := a.currentProjectRoot()
+	if db == nil || root == "" {
+		return
+	}
+	days := defaultTrashRetentionDays
+	if v, ok := a.projectSetting("trash_retention_days").(float64); ok {
+		days = int(v)
+	}
+	if days <= 0 {
+		return
+	}
+	_ = purgeTrash(root, time.Now().AddDate(0, 0, -days))
+}
+
+// purgeTrash removes trash entries of a project deleted before cutoff
+func purgeTrash(project string, cutoff time.Time) error {
+	items, err := db.GetTrashItems(project)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if !item.DeletedAt.Before(cutoff) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Dir(item.TrashPath)); err != nil {
+			return err
+		}
+		if err := db.DeleteTrashItem(item.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// releasedStatuses need no marking on export
+var releasedStatuses = map[string]bool{
+	"released":  true,
+	"published": true,
+	"approved":  true,
+	"final":     true,
+}
+
+// ExportMarkingOptions controls MarkExport
+type ExportMarkingOptions struct {
+	// SourcePath is the document the output was exported from; its
+	// status and classification decide the marking
+	SourcePath string `json:"sourcePath"`
+	// Text overrides the marking derived from the source
+	Text string `json:"text"`
+	// Preset names the export preset the output was made with; when its
+	// audience is public, HTML output loses the elements of hidden
+	// sections and blocks
+	Preset string `json:"preset,omitempty"`
+}
+
+// MarkExport stamps an exported PDF with a diagonal watermark on every
+// page, or adds a banner to the top of exported HTML, when the source
+// document isn't released (":status: draft") or is classified
+// (":classification: confidential"). It returns the marking applied, or ""
+// when the document needs none.
+func (a *App) MarkExport(outputPath string, opts ExportMarkingOptions) (string, error) {
+	if opts.Preset != "" {
+		audience, err := presetAudience(a.currentProjectRoot(), opts.Preset)
+		if err != nil {
+			return "", err
+		}
+		if audience == ExportAudiencePublic {
+			if err := stripHiddenOutput(outputPath, a.hiddenRoles()); err != nil {
+				return "", err
+			}
+		}
+	}
+	marking := strings.TrimSpace(opts.Text)
+	if marking == "" && opts.SourcePath != "" {
+		var err error
+		if marking, err = a.documentMarking(opts.SourcePath); err != nil {
+			return "", err
+		}
+	}
+	if marking == "" {
+		return "", nil
+	}
+
+	data, err := os.ReadFile(longPath(outputPath))
+	if err != nil {
+		return "", newFileError("mark", outputPath, err)
+	}
+	switch strings.ToLower(filepath.Ext(outputPath)) {
+	case ".pdf":
+		data, err = watermarkPDF(data, marking)
+	case ".html", ".htm":
+		data = injectBanner(data, marking)
+	default:
+		return "", fmt.Errorf("can't mark %s files", filepath.Ext(outputPath))
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to mark %s: %w", outputPath, err)
+	}
+	if err := writeFileAtomic(outputPath, data, 0644); err != nil {
+		return "", newFileError("mark", outputPath, err)
+	}
+	return marking, nil
+}
+
+// documentMarking derives the marking from the status and classification
+// attributes of a document, falling back to the project's attributes
+func (a *App) documentMarking(sourcePath string) (string, error) {
+	content, err := os.ReadFile(longPath(sourcePath))
+	if err != nil {
+		return "", newFileError("read", sourcePath, err)
+	}
+
+	attrs := make(map[string]string)
+	if cfg, _ := LoadProjectConfig(a.currentProjectRoot()); cfg != nil {
+		for k, v := range cfg.Attributes {
+			attrs[k] = v
+		}
+	}
+	for k, v := range documentMetadata(content) {
+		attrs[k] = v
+	}
+
+	status := attrs["status"]
+	if status == "" {
+		status = attrs["doc-status"]
+	}
+	var parts []string
+	if s := strings.ToLower(strings.TrimSpace(status)); s != "" && !releasedStatuses[s] {
+		parts = append(parts, strings.ToUpper(s))
+	}
+	if c := strings.ToLower(strings.TrimSpace(attrs["classification"])); c != "" && c != "public" {
+		parts = append(parts, strings.ToUpper(c))
+	}
+	return strings.Join(parts, " - "), nil
+}
+
+var htmlBodyExpr = regexp.MustCompile(`(?i)<body[^>]*>`)
+
+// injectBanner puts a marking banner at the top of an HTML page
+func injectBanner(data []byte, marking string) []byte {
+	banner := fmt.Sprintf(`<div class="ndx-status-banner" role="note" style="position:sticky;top:0;z-index:1000;padding:.5em 1em;background:#b00020;color:#fff;font:bold 14px sans-serif;letter-spacing:.1em;text-align:center">%s</div>`,
+		html.EscapeString(marking))
+	loc := htmlBodyExpr.FindIndex(data)
+	if loc == nil {
+		return append([]byte(banner+"\n"), data...)
+	}
+	out := make([]byte, 0, len(data)+len(banner)+1)
+	out = append(out, data[:loc[1]]...)
+	out = append(out, '\n')
+	out = append(out, banner...)
+	return append(out, data[loc[1]:]...)
+}
+
+var (
+	pdfMediaBoxExpr  = regexp.MustCompile(`/MediaBox\s*\[([^\]]*)\]`)
+	pdfAnnotsExpr    = regexp.MustCompile(`(?s)/Annots\s*\[(.*?)\]`)
+	pdfAnnotsRefExpr = regexp.MustCompile(`/Annots\s+(\d+)\s+(\d+)\s+R`)
+)
+
+// watermarkPDF adds a watermark annotation to every page in an
+// incremental update. Annotations carry their own appearance and
+// resources, so the page content streams are left alone.
+func watermarkPDF(data []byte, marking string) ([]byte, error) {
+	u, err := newPDFUpdate(data)
+	if err != nil {
+		return nil, err
+	}
+	pages := u.pages()
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("no pages found")
+	}
+
+	font := u.newObject()
+	u.write(font, 0, []byte("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>"))
+
+	// Pages without their own MediaBox inherit the document's
+	defaultBox := []float64{0, 0, 612, 792}
+	if m := pdfMediaBoxExpr.FindSubmatch(data); m != nil {
+		if box := parsePDFBox(m[1]); box != nil {
+			defaultBox = box
+		}
+	}
+
+	for _, page := range pages {
+		body, gen, _ := u.object(page)
+		dict, ok := pdfDict(body)
+		if !ok {
+			continue
+		}
+		box := defaultBox
+		if m := pdfMediaBoxExpr.FindSubmatch(dict); m != nil {
+			if b := parsePDFBox(m[1]); b != nil {
+				box = b
+			}
+		}
+		rect := fmt.Sprintf("[%g %g %g %g]", box[0], box[1], box[2], box[3])
+
+		stream := watermarkStream(box, marking)
+		appearance := u.newObject()
+		u.write(appearance, 0, fmt.Appendf(nil,
+			"<< /Type /XObject /Subtype /Form /BBox %s /Resources << /Font << /F1 %d 0 R >> /ExtGState << /GS1 << /ca 0.25 >> >> >> /Length %d >>\nstream\n%s\nendstream",
+			rect, font, len(stream), stream))
+
+		annot := u.newObject()
+		u.write(annot, 0, fmt.Appendf(nil, "<< /Type /Annot /Subtype /Watermark /Rect %s /F 4 /P %d %d R /Contents %s /AP << /N %d 0 R >> >>",
+			rect, page, gen, pdfString(marking), appearance))
+
+		ref := fmt.Sprintf("%d 0 R", annot)
+		switch {
+		case pdfAnnotsExpr.Match(dict):
+			dict = pdfAnnotsExpr.ReplaceAll(dict, []byte("/Annots [$1 "+ref+"]"))
+		case pdfAnnotsRefExpr.Match(dict):
+			// The annotation array is an object of its own
+			m := pdfAnnotsRefExpr.FindSubmatch(dict)
+			num, _ := strconv.Atoi(string(m[1]))
+			arr, arrGen, ok := u.object(num)
+			if !ok || !bytes.HasSuffix(arr, []byte("]")) {
+				return nil, fmt.Errorf("annotations of page %d not found", page)
+			}
+			u.write(num, arrGen, fmt.Appendf(nil, "%s %s]", arr[:len(arr)-1], ref))
+			continue
+		default:
+			dict = fmt.Appendf(nil, "%s /Annots [%s]", dict, ref)
+		}
+		u.write(page, gen, fmt.Appendf(nil, "<< %s >>", dict))
+	}
+	return u.finish(), nil
+}
+
+func parsePDFBox(s []byte) []float64 {
+	fields := strings.Fields(string(s))
+	if len(fields) != 4 {
+		return nil
+	}
+	box := make([]float64, 4)
+	for i, f := range fields {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return nil
+		}
+		box[i] = v
+	}
+	return box
+}
+
+// watermarkStream draws marking diagonally across box, sized to fit
+func watermarkStream(box []float64, marking string) string {
+	w, h := box[2]-box[0], box[3]-box[1]
+	angle := math.Atan2(h, w)
+	cos, sin := math.Cos(angle), math.Sin(angle)
+
+	// Helvetica-Bold capitals average about 0.7em wide; the text spans
+	// 80% of the diagonal
+	const glyphWidth = 0.7
+	chars := float64(utf8.RuneCountInString(marking))
+	size := math.Min(math.Hypot(w, h)*0.8/(chars*glyphWidth), 120)
+	textWidth := chars * glyphWidth * size
+	x := box[0] + w/2 - cos*textWidth/2 + sin*size/3
+	y := box[1] + h/2 - sin*textWidth/2 - cos*size/3
+
+	return fmt.Sprintf("q /GS1 gs 0.6 0 0 rg BT /F1 %.1f Tf %.4f %.4f %.4f %.4f %.2f %.2f Tm %s Tj ET Q",
+		size, cos, sin, -sin, cos, x, y, pdfLatin1String(marking))
+}
+
+// pdfLatin1String encodes s as a literal string for a WinAnsi font;
+// characters outside Latin-1 become "?"
+func pdfLatin1String(s string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32 || r > 255:
+			b.WriteByte('?')
+		case r > 126:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GetProjectRoots returns the folders of a project in display order. A
+// single-root project returns just its own folder.
+func (a *App) GetProjectRoots(projectPath string) ([]ProjectRoot, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	roots, err := db.GetProjectRoots(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(roots) == 0 {
+		roots = []ProjectRoot{{Path: projectPath, Label: filepath.Base(projectPath)}}
+	}
+	return roots, nil
+}
+
+// SetProjectRoots makes a project a multi-root workspace, e.g. a docs repo
+// plus a shared assets repo. Roots must be distinct existing folders that
+// don't contain one another; missing labels default to the folder name.
+func (a *App) SetProjectRoots(projectPath string, roots []ProjectRoot) error {
+	if db == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	cleaned := make([]ProjectRoot, 0, len(roots))
+	for _, r := range roots {
+		if r.Path == "" {
+			return fmt.Errorf("root path must not be empty")
+		}
+		path, err := filepath.Abs(r.Path)
+		if err != nil {
+			return err
+		}
+		if info, err := os.Stat(path); err != nil {
+			return err
+		} else if !info.IsDir() {
+			return fmt.Errorf("%s is not a folder", path)
+		}
+		for _, other := range cleaned {
+			if isWithin(path, other.Path) || isWithin(other.Path, path) {
+				return fmt.Errorf("roots %s and %s overlap", other.Path, path)
+			}
+		}
+		label := strings.TrimSpace(r.Label)
+		if label == "" {
+			label = filepath.Base(path)
+		}
+		cleaned = append(cleaned, ProjectRoot{Path: path, Label: label})
+	}
+
+	// A lone root that is the project itself needs no list
+	if len(cleaned) == 1 && cleaned[0].Path == filepath.Clean(projectPath) {
+		cleaned = nil
+	}
+	if err := db.SetProjectRoots(projectPath, cleaned); err != nil {
+		return err
+	}
+	a.emitTreeChanged()
+	return nil
+}
+
+// workspaceRoots returns the stored roots of a multi-root project, or nil
+func workspaceRoots(projectPath string) []ProjectRoot {
+	if db == nil || projectPath == "" {
+		return nil
+	}
+	roots, _ := db.GetProjectRoots(projectPath)
+	return roots
+}
+
+// isWithin reports whether path is dir or below it
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// workspaceForest lists every root of a workspace as a top-level node with
+// its first level of entries
+func (a *App) workspaceForest(roots []ProjectRoot) []*FileNode {
+	forest := make([]*FileNode, 0, len(roots))
+	for _, r := range roots {
+		node := &FileNode{Name: r.Label, Path: r.Path, IsDir: true, IsRoot: true}
+		opts := a.newTreeOptions(r.Path)
+		ignore := (&ignoreMatcher{}).withDir(r.Path, "")
+		children, err := a.readDirRecursive(r.Path, opts, ignore, false, 1)
+		node.Children = children
+		if err != nil {
+			node.Error = treeErrorCode(err)
+		}
+		forest = append(forest, node)
+	}
+	return forest
+}